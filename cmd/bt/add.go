@@ -7,7 +7,9 @@ import (
 	"os"
 	"strings"
 
+	"github.com/amaya382/baretree/internal/global"
 	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/url"
 	"github.com/amaya382/baretree/internal/worktree"
 	"github.com/spf13/cobra"
 )
@@ -19,10 +21,13 @@ var (
 	addForce      bool
 	addNoFetch    bool
 	addBehind     string
+	addPR         int
+	addMR         int
+	addRemote     string
 )
 
 var addCmd = &cobra.Command{
-	Use:   "add <branch-name>",
+	Use:   "add <branch-name> | --pr <number> | --mr <number>",
 	Short: "Create a worktree for a branch (creates branch with -b)",
 	Long: `Create a new worktree for a branch.
 
@@ -34,6 +39,7 @@ Supports multiple modes:
   2. Existing local branch: bt add existing-branch
   3. Remote branch:         bt add feature/remote (auto-detects origin/feature/remote)
   4. Explicit remote:       bt add upstream/feature/foo
+  5. Pull request:          bt add --pr 123 (GitLab merge request: --mr 123)
 
 The worktree path is automatically determined from the branch name.
 Branch names with slashes create hierarchical directories.
@@ -49,6 +55,14 @@ When the base branch is behind its upstream, an interactive prompt lets you choo
   3. Abort
 Use --behind=continue|pull|abort to skip the prompt.
 
+With --pr or --mr, the request is fetched from the remote (default: origin)
+into a local branch pr/<number> or mr/<number>. Running the same command again
+fast-forwards the branch and its worktree to the latest version of the request.
+The fetched refs can be changed per host (the host of the remote URL, or
+"local" for local paths):
+  baretree.host.<host>.pullRequestRef    (default: refs/pull/{number}/head)
+  baretree.host.<host>.mergeRequestRef   (default: refs/merge-requests/{number}/head)
+
 Examples:
   bt add -b feature/auth           # Creates new branch and worktree
   bt add -b feature/new --base abc123  # Creates new branch based on a commit
@@ -56,8 +70,10 @@ Examples:
   bt add feature/remote            # Auto-detects and tracks origin/feature/remote
   bt add upstream/feature/test     # Tracks upstream/feature/test
   bt add --no-fetch feature/new    # Skip auto-fetch from remotes
-  bt add -b feature/new --behind=pull  # Pull base branch if behind, then create`,
-	Args: cobra.ExactArgs(1),
+  bt add -b feature/new --behind=pull  # Pull base branch if behind, then create
+  bt add --pr 123                  # Check out pull request #123 as pr/123
+  bt add --mr 42 --remote upstream # Check out merge request !42 from upstream`,
+	Args: addArgs,
 	RunE: runAdd,
 }
 
@@ -68,11 +84,23 @@ func init() {
 	addCmd.Flags().BoolVar(&addForce, "force", false, "Force creation even if worktree exists")
 	addCmd.Flags().BoolVar(&addNoFetch, "no-fetch", false, "Skip auto-fetch from remotes")
 	addCmd.Flags().StringVar(&addBehind, "behind", "", "Action when base branch is behind upstream: continue, pull, abort")
+	addCmd.Flags().IntVar(&addPR, "pr", 0, "Create or refresh a worktree for a pull request")
+	addCmd.Flags().IntVar(&addMR, "mr", 0, "Create or refresh a worktree for a merge request")
+	addCmd.Flags().StringVar(&addRemote, "remote", "origin", "Remote to fetch the pull/merge request from")
+	addCmd.MarkFlagsMutuallyExclusive("pr", "mr")
+	addCmd.MarkFlagsMutuallyExclusive("pr", "branch")
+	addCmd.MarkFlagsMutuallyExclusive("mr", "branch")
 }
 
-func runAdd(cmd *cobra.Command, args []string) error {
-	branchSpec := args[0]
+// addArgs requires a branch name, except when adding a pull/merge request
+func addArgs(cmd *cobra.Command, args []string) error {
+	if cmd.Flags().Changed("pr") || cmd.Flags().Changed("mr") {
+		return cobra.NoArgs(cmd, args)
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
 
+func runAdd(cmd *cobra.Command, args []string) error {
 	// Find repository root
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -99,6 +127,17 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	// Create worktree manager
 	wtMgr := worktree.NewManager(repoRoot, bareDir, mgr.Config)
 
+	if cmd.Flags().Changed("remote") && !cmd.Flags().Changed("pr") && !cmd.Flags().Changed("mr") {
+		return fmt.Errorf("--remote can only be used with --pr or --mr")
+	}
+	if cmd.Flags().Changed("pr") {
+		return runAddRequest(wtMgr, worktree.PullRequest, addPR, cmd.Flags().Changed("remote"))
+	}
+	if cmd.Flags().Changed("mr") {
+		return runAddRequest(wtMgr, worktree.MergeRequest, addMR, cmd.Flags().Changed("remote"))
+	}
+	branchSpec := args[0]
+
 	// Auto-fetch unless --no-fetch is specified or no remotes configured
 	if !addNoFetch && wtMgr.Executor.HasRemotes() {
 		fmt.Println("Fetching from remotes...")
@@ -126,11 +165,7 @@ func runAdd(cmd *cobra.Command, args []string) error {
 			baseDisplayInfo = baseInfo.RemoteRef + " (remote)"
 		} else if wtMgr.Executor.IsCommitHash(addBaseBranch) {
 			resolvedBaseBranch = addBaseBranch
-			shortHash := addBaseBranch
-			if len(shortHash) > 7 {
-				shortHash = shortHash[:7]
-			}
-			baseDisplayInfo = shortHash + " (commit)"
+			baseDisplayInfo = shortCommit(addBaseBranch) + " (commit)"
 		} else {
 			return fmt.Errorf("base branch '%s' not found locally or on any remote", addBaseBranch)
 		}
@@ -259,7 +294,9 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Creating worktree for branch '%s'...\n", branchName)
 
 	// Add worktree (pass os.Stdout for real-time output including "Worktree created" message)
-	_, postCreateResult, err := wtMgr.AddWithOptions(branchName, opts, os.Stdout)
+	branchRef := "refs/heads/" + branchName
+	oldSHA := global.ResolveRef(repoRoot, branchRef)
+	worktreePath, postCreateResult, err := wtMgr.AddWithOptions(branchName, opts, os.Stdout)
 	if err != nil {
 		var existsErr *worktree.ErrWorktreeAlreadyExists
 		if errors.As(err, &existsErr) {
@@ -276,6 +313,15 @@ func runAdd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to add worktree: %w", err)
 	}
 
+	op := global.NewOperation(repoRoot)
+	op.RecordRef(repoRoot, branchRef, oldSHA)
+	op.Record(global.JournalStep{Action: global.StepAddWorktree, Path: worktreePath, Branch: branchName})
+	op.SaveOrWarn(os.Stdout)
+
+	if err := wtMgr.RefreshEditorWorkspace(); err != nil {
+		fmt.Printf("Warning: failed to update editor workspace: %v\n", err)
+	}
+
 	// "Worktree created" message and post-create output are already printed by AddWithOptions
 	// Just check if any commands failed and show warning
 	if postCreateResult != nil && len(postCreateResult.CommandResults) > 0 {
@@ -299,6 +345,116 @@ func runAdd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// runAddRequest fetches a pull/merge request into its local branch and creates a worktree
+// for it, or fast-forwards the branch and its existing worktree. If --remote was given
+// (remoteSet), it must match the remote of a remembered request.
+func runAddRequest(wtMgr *worktree.Manager, kind worktree.RequestKind, number int, remoteSet bool) error {
+	if number <= 0 {
+		return fmt.Errorf("invalid request number: %d", number)
+	}
+
+	branchName := worktree.RequestBranch(kind, number)
+
+	// Refresh from the remembered request, or build it from the remote's host settings
+	req := wtMgr.LoadRequest(branchName)
+	if req != nil && remoteSet && req.Remote != addRemote {
+		return fmt.Errorf("'%s' is fetched from remote '%s', not '%s' (remove it with 'bt rm %s --with-branch' to fetch it from another remote)",
+			branchName, req.Remote, addRemote, branchName)
+	}
+	if req == nil {
+		ref, err := requestRef(wtMgr, kind, number)
+		if err != nil {
+			return err
+		}
+		req = &worktree.Request{Remote: addRemote, Ref: ref, Branch: branchName}
+	}
+
+	branchRef := "refs/heads/" + branchName
+	oldSHA := global.ResolveRef(wtMgr.RepoRoot, branchRef)
+
+	fmt.Printf("Fetching %s from %s...\n", req.Ref, req.Remote)
+	update, err := wtMgr.FetchRequest(*req)
+	if err != nil {
+		var refConflictErr *worktree.ErrRefConflict
+		if errors.As(err, &refConflictErr) {
+			return refConflictErr
+		}
+		return err
+	}
+
+	if update.WorktreePath != "" {
+		if update.UpToDate() {
+			fmt.Printf("✓ '%s' is already up to date\n", branchName)
+		} else {
+			op := global.NewOperation(wtMgr.RepoRoot)
+			op.RecordRef(wtMgr.RepoRoot, branchRef, oldSHA)
+			op.SaveOrWarn(os.Stdout)
+			fmt.Printf("✓ Updated '%s' (%s..%s)\n", branchName, shortCommit(update.OldCommit), shortCommit(update.NewCommit))
+		}
+		fmt.Printf("  %s\n", update.WorktreePath)
+		return nil
+	}
+
+	if update.Created {
+		fmt.Printf("Created branch '%s' at %s\n", branchName, shortCommit(update.NewCommit))
+	} else if !update.UpToDate() {
+		fmt.Printf("Updated branch '%s' (%s..%s)\n", branchName, shortCommit(update.OldCommit), shortCommit(update.NewCommit))
+	}
+
+	fmt.Printf("Creating worktree for branch '%s'...\n", branchName)
+	op := global.NewOperation(wtMgr.RepoRoot)
+	op.RecordRef(wtMgr.RepoRoot, branchRef, oldSHA)
+	worktreePath, _, err := wtMgr.AddWithOptions(branchName, worktree.AddOptions{}, os.Stdout)
+	if err != nil {
+		op.SaveOrWarn(os.Stdout)
+		return fmt.Errorf("failed to add worktree: %w", err)
+	}
+	op.Record(global.JournalStep{Action: global.StepAddWorktree, Path: worktreePath, Branch: branchName})
+	op.SaveOrWarn(os.Stdout)
+
+	if err := wtMgr.RefreshEditorWorkspace(); err != nil {
+		fmt.Printf("Warning: failed to update editor workspace: %v\n", err)
+	}
+
+	fmt.Printf("\nNext steps:\n")
+	fmt.Printf("  bt cd %s\n", branchName)
+	fmt.Printf("  bt add --%s %d   # Fetch the latest changes later\n", kind, number)
+
+	return nil
+}
+
+// requestRef returns the ref of a pull/merge request on the --remote remote,
+// using the ref pattern configured for the host of its URL
+func requestRef(wtMgr *worktree.Manager, kind worktree.RequestKind, number int) (string, error) {
+	remoteURL, err := wtMgr.Executor.Execute("remote", "get-url", addRemote)
+	if err != nil {
+		return "", fmt.Errorf("remote '%s' not found", addRemote)
+	}
+
+	host := url.LocalHost
+	if repoPath, err := url.ParseRemoteURL(remoteURL); err == nil && repoPath.Host != "" {
+		host = repoPath.Host
+	}
+
+	globalCfg, err := global.LoadConfig()
+	if err != nil {
+		return "", fmt.Errorf("failed to load config: %w", err)
+	}
+	pattern := globalCfg.PullRequestRefFor(host)
+	if kind == worktree.MergeRequest {
+		pattern = globalCfg.MergeRequestRefFor(host)
+	}
+	return worktree.RequestRef(pattern, number), nil
+}
+
+// shortCommit abbreviates a commit hash for display
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
 // isTerminal checks if stdin is connected to a terminal
 func isTerminal() bool {
 	stat, err := os.Stdin.Stat()
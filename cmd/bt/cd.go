@@ -5,13 +5,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/amaya382/baretree/internal/global"
 	"github.com/amaya382/baretree/internal/repository"
 	"github.com/amaya382/baretree/internal/worktree"
 	"github.com/spf13/cobra"
 )
 
-const historyFile = ".baretree_history"
+var (
+	cdHistory bool
+	cdRoot    bool
+)
 
 var cdCmd = &cobra.Command{
 	Use:   "cd [worktree-name]",
@@ -23,9 +28,22 @@ This command should be used with the shell function installed by 'bt shell-init'
 The worktree name can be:
   - Branch name (e.g., feature/auth)
   - Directory name relative to repo root
+  - Part of a branch or directory name (e.g., auth for feature/auth-refresh)
   - @ for default worktree
   - (empty) for current worktree root (or default worktree if at repo root)
-  - - (dash) to go to previous worktree
+  - - (dash) to go to previous worktree, -N to the N-th previous one
+
+If several worktrees match, the one visited most often and most recently
+(frecency) is chosen. Disable recording and ranking with:
+  git config --global baretree.frecency false
+
+When switching from a subdirectory of a worktree, bt cd goes to the same
+subdirectory of the target worktree (or its nearest existing parent). Use
+--root to go to the worktree root instead, or make that the default with:
+  git config --global baretree.keepSubdir false
+
+Previous directories are remembered per repository (up to 50) in
+$XDG_STATE_HOME/baretree/history.
 
 Setup:
   Add to your shell configuration (~/.bashrc or ~/.zshrc):
@@ -37,6 +55,9 @@ Usage (after shell setup):
   bt cd @               # Change to default worktree
   bt cd                 # Change to current worktree root (or default worktree at repo root)
   bt cd -               # Change to previous worktree
+  bt cd -2              # Change to the worktree before that
+  bt cd --history       # List previous worktrees
+  bt cd main --root     # Change to the root of the main worktree
 
 Examples:
   bt cd feature/test
@@ -47,6 +68,11 @@ Examples:
 	ValidArgsFunction: completeWorktreeNames(true),
 }
 
+func init() {
+	cdCmd.Flags().BoolVar(&cdHistory, "history", false, "List previously visited worktrees of this repository (go back with bt cd -N)")
+	cdCmd.Flags().BoolVar(&cdRoot, "root", false, "Go to the worktree root instead of the same subdirectory")
+}
+
 func runCd(cmd *cobra.Command, args []string) error {
 	var targetName string
 	if len(args) > 0 {
@@ -64,6 +90,23 @@ func runCd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("not in a baretree repository: %w", err)
 	}
 
+	history, err := global.WorktreeHistory(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	if cdHistory {
+		if len(args) > 0 {
+			return fmt.Errorf("--history does not take a worktree name")
+		}
+		return printHistory(history, cwd, func(path string) string {
+			if rel, err := filepath.Rel(repoRoot, path); err == nil && !strings.HasPrefix(rel, "..") {
+				return rel
+			}
+			return path
+		})
+	}
+
 	// Get bare repository path
 	bareDir, err := repository.GetBareRepoPath(repoRoot)
 	if err != nil {
@@ -80,15 +123,20 @@ func runCd(cmd *cobra.Command, args []string) error {
 
 	var targetPath string
 
-	// Handle special case: previous directory
-	if targetName == "-" {
-		targetPath, err = getPreviousDirectory()
+	// Handle special case: previous directories (- or -N)
+	if n, ok := global.ParseHistoryPosition(targetName); ok {
+		targetPath, err = historyEntry(history, cwd, n)
 		if err != nil {
-			return fmt.Errorf("no previous directory: %w", err)
+			return err
 		}
 	} else {
-		// Resolve worktree (pass cwd for empty name resolution)
-		targetPath, err = wtMgr.ResolveFromCwd(targetName, cwd)
+		// Resolve worktree (pass cwd for empty name resolution); partial names are
+		// matched fuzzily and ambiguous ones pick the most frequently used worktree
+		globalCfg, err := global.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+		targetPath, err = wtMgr.ResolveFuzzy(targetName, cwd, globalCfg.FrecencyScorer())
 		if err != nil {
 			var ambiguousErr *worktree.AmbiguousMatchError
 			if errors.As(err, &ambiguousErr) {
@@ -102,10 +150,24 @@ func runCd(cmd *cobra.Command, args []string) error {
 			}
 			return err
 		}
+
+		if globalCfg.Frecency {
+			if err := global.RecordVisit(targetPath); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to record visit: %v\n", err)
+			}
+		}
+
+		// Stay in the same subdirectory when switching to another worktree
+		if globalCfg.KeepSubdir && !cdRoot {
+			targetPath, err = wtMgr.CorrespondingPath(targetPath, cwd)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	// Save current directory to history
-	if err := savePreviousDirectory(cwd); err != nil {
+	if err := history.Move(cwd, targetPath); err != nil {
 		// Non-fatal, just warn
 		fmt.Fprintf(os.Stderr, "Warning: failed to save directory history: %v\n", err)
 	}
@@ -116,27 +178,33 @@ func runCd(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func getPreviousDirectory() (string, error) {
-	homeDir, err := os.UserHomeDir()
+// historyEntry returns the n-th previous directory (1 for the most recent) other than cwd
+func historyEntry(history *global.DirHistory, cwd string, n int) (string, error) {
+	entries, err := history.Entries(cwd)
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("failed to read directory history: %w", err)
 	}
-
-	historyPath := filepath.Join(homeDir, historyFile)
-	data, err := os.ReadFile(historyPath)
-	if err != nil {
-		return "", err
+	if len(entries) == 0 {
+		return "", fmt.Errorf("no previous directory")
 	}
-
-	return string(data), nil
+	if n > len(entries) {
+		return "", fmt.Errorf("no previous directory at -%d (history has %d entries)", n, len(entries))
+	}
+	return entries[n-1], nil
 }
 
-func savePreviousDirectory(dir string) error {
-	homeDir, err := os.UserHomeDir()
+// printHistory lists the previous directories with the -N to go back to each of them
+func printHistory(history *global.DirHistory, cwd string, display func(path string) string) error {
+	entries, err := history.Entries(cwd)
 	if err != nil {
-		return err
+		return fmt.Errorf("failed to read directory history: %w", err)
 	}
-
-	historyPath := filepath.Join(homeDir, historyFile)
-	return os.WriteFile(historyPath, []byte(dir), 0644)
+	if len(entries) == 0 {
+		fmt.Println("No directory history.")
+		return nil
+	}
+	for i, entry := range entries {
+		fmt.Printf("%4s  %s\n", fmt.Sprintf("-%d", i+1), display(entry))
+	}
+	return nil
 }
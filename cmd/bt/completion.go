@@ -3,8 +3,10 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	"github.com/amaya382/baretree/internal/global"
 	"github.com/amaya382/baretree/internal/repository"
 	"github.com/amaya382/baretree/internal/worktree"
 	"github.com/spf13/cobra"
@@ -62,6 +64,14 @@ func completeWorktreeNames(includeSpecial bool) func(cmd *cobra.Command, args []
 		// Filter and order by prefix match first, then substring match
 		completions = filterWithPrefixPriority(names, toComplete)
 
+		// Offer the most frequently used worktrees first and ask the shell to keep that order
+		directive := cobra.ShellCompDirectiveNoFileComp
+		if globalCfg, err := global.LoadConfig(); err == nil {
+			if score := globalCfg.FrecencyScorer(); score != nil && sortByScore(completions, repoRoot, score) {
+				directive |= cobra.ShellCompDirectiveKeepOrder
+			}
+		}
+
 		// Add special completions (only when no filter or matches special chars)
 		if includeSpecial && (toComplete == "" || toComplete == "@" || toComplete == "-") {
 			if toComplete == "" {
@@ -74,8 +84,25 @@ func completeWorktreeNames(includeSpecial bool) func(cmd *cobra.Command, args []
 			}
 		}
 
-		return completions, cobra.ShellCompDirectiveNoFileComp
+		return completions, directive
+	}
+}
+
+// sortByScore orders worktree names (relative to repoRoot) by descending score, keeping the
+// original order for equal scores. It reports whether any worktree has a positive score.
+func sortByScore(names []string, repoRoot string, score func(path string) float64) bool {
+	scores := make(map[string]float64, len(names))
+	ranked := false
+	for _, name := range names {
+		scores[name] = score(filepath.Join(repoRoot, name))
+		ranked = ranked || scores[name] > 0
+	}
+	if ranked {
+		sort.SliceStable(names, func(i, j int) bool {
+			return scores[names[i]] > scores[names[j]]
+		})
 	}
+	return ranked
 }
 
 // filterWithPrefixPriority filters strings by query with prefix matches first.
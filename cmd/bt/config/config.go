@@ -12,6 +12,7 @@ var Cmd = &cobra.Command{
 
 Subcommands:
   default-branch    Get or set the default branch
+  worktree-template Get or set how branch names map to worktree directories
   export            Export configuration to TOML format
   import            Import configuration from TOML format
 
@@ -19,6 +20,7 @@ Examples:
   bt config default-branch               # Show current default branch
   bt config default-branch main          # Set default branch to 'main'
   bt config default-branch --unset       # Remove setting (reverts to 'main')
+  bt config worktree-template '{{ .Branch | replace "/" "-" }}'  # Flatten worktree directories
   bt config export                       # Output to stdout
   bt config export -o config.toml        # Write to file
   bt config import config.toml           # Import from file
@@ -21,6 +21,8 @@ var exportCmd = &cobra.Command{
 This exports all baretree-related settings including:
   - Repository settings (default branch)
   - Post-create actions (symlink, copy, command)
+  - Sync-to-root entries
+  - Lifecycle hooks (pre-remove, post-remove, post-rename, post-checkout)
 
 By default, outputs to stdout. Use -o to write to a file.
 
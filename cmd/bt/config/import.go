@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 
 	"github.com/amaya382/baretree/internal/config"
 	"github.com/amaya382/baretree/internal/repository"
@@ -23,13 +24,14 @@ var importCmd = &cobra.Command{
 	Long: `Import baretree configuration from TOML format.
 
 This imports all baretree-related settings including:
-  - Repository settings (default branch)
+  - Repository settings (default branch, worktree naming template)
   - Post-create actions (symlink, copy, command)
+  - Lifecycle hooks (pre-remove, post-remove, post-rename, post-checkout)
 
 Reads from a file or stdin if no file is specified.
 
 By default, replaces the existing configuration.
-Use --merge to add post-create entries without removing existing ones
+Use --merge to add post-create entries and hooks without removing existing ones
 (repository settings are always updated).
 Use --apply to immediately apply post-create file changes to all worktrees.
 
@@ -84,6 +86,16 @@ func runImport(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return fmt.Errorf("failed to parse TOML: %w", err)
 	}
+	if importedCfg.Repository.WorktreeTemplate != "" {
+		if err := worktree.ValidateNamingTemplate(importedCfg.Repository.WorktreeTemplate); err != nil {
+			return err
+		}
+	}
+	if importedCfg.Repository.EditorWorkspace != "" {
+		if err := worktree.ValidateEditorFormat(importedCfg.Repository.EditorWorkspace); err != nil {
+			return err
+		}
+	}
 
 	// Load current config
 	currentCfg, err := config.LoadConfig(repoRoot)
@@ -96,6 +108,12 @@ func runImport(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 	fmt.Println("[repository]")
 	fmt.Printf("  default_branch: %s\n", importedCfg.Repository.DefaultBranch)
+	if importedCfg.Repository.WorktreeTemplate != "" {
+		fmt.Printf("  worktree_template: %s\n", importedCfg.Repository.WorktreeTemplate)
+	}
+	if importedCfg.Repository.EditorWorkspace != "" {
+		fmt.Printf("  editor_workspace: %s\n", importedCfg.Repository.EditorWorkspace)
+	}
 	fmt.Println()
 	fmt.Printf("[postcreate] (%d entries)\n", len(importedCfg.PostCreate))
 	for _, a := range importedCfg.PostCreate {
@@ -110,6 +128,17 @@ func runImport(cmd *cobra.Command, args []string) error {
 		}
 	}
 	fmt.Println()
+	for _, event := range config.HookEvents {
+		hooks := *importedCfg.Hooks(event)
+		if len(hooks) == 0 {
+			continue
+		}
+		fmt.Printf("[%s] (%d entries)\n", hookSection(event), len(hooks))
+		for _, h := range hooks {
+			fmt.Printf("  %s\n", h.Command)
+		}
+		fmt.Println()
+	}
 
 	if importDryRun {
 		fmt.Println("Dry run - no changes made")
@@ -137,9 +166,26 @@ func runImport(cmd *cobra.Command, args []string) error {
 		}
 		fmt.Printf("Repository and worktree settings updated\n")
 		fmt.Printf("Post-create actions: added %d new entry(ies), %d already existed\n", added, len(importedCfg.PostCreate)-added)
+
+		// Merge hooks the same way, keyed by command
+		for _, event := range config.HookEvents {
+			current := currentCfg.Hooks(event)
+			existingHooks := make(map[string]bool)
+			for _, h := range *current {
+				existingHooks[h.Command] = true
+			}
+			for _, h := range *importedCfg.Hooks(event) {
+				if !existingHooks[h.Command] {
+					*current = append(*current, h)
+				}
+			}
+		}
 	} else {
 		// Replace
 		currentCfg.PostCreate = importedCfg.PostCreate
+		for _, event := range config.HookEvents {
+			*currentCfg.Hooks(event) = *importedCfg.Hooks(event)
+		}
 		fmt.Printf("Replaced entire configuration\n")
 	}
 
@@ -180,3 +226,8 @@ func runImport(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// hookSection returns the TOML section name for a hook event (e.g., "pre-remove" -> "preremove")
+func hookSection(event string) string {
+	return strings.ReplaceAll(event, "-", "")
+}
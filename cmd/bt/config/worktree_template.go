@@ -0,0 +1,136 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/amaya382/baretree/internal/config"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var worktreeTemplateUnset bool
+
+var worktreeTemplateCmd = &cobra.Command{
+	Use:   "worktree-template [template]",
+	Short: "Get or set how branch names map to worktree directories",
+	Long: `Get or set the naming template that maps branch names to worktree directories.
+
+By default, the worktree of a branch is the directory with the same path as the
+branch name (users/alice/JIRA-1234-fix-login -> users/alice/JIRA-1234-fix-login/).
+The template is a Go text/template rendering the directory relative to the
+repository root. Available variables:
+  {{.Branch}}   Branch name (users/alice/JIRA-1234-fix-login)
+  {{.Slug}}     Lowercase branch with other characters replaced by '-' (users-alice-jira-1234-fix-login)
+  {{.Base}}     Last component of the branch name (JIRA-1234-fix-login)
+
+Functions (the piped value comes last):
+  lower, upper                Change case
+  replace OLD NEW             Replace all occurrences of OLD with NEW
+  trimPrefix P, trimSuffix S  Remove a prefix or suffix
+  trunc N                     Keep at most N characters
+  regexReplace RE REPL        Replace matches of a regular expression
+
+Without arguments, displays the current template.
+With --unset, worktrees are named after their branch again.
+
+Existing worktrees are not moved; 'bt status' reports them as name mismatches
+and 'bt repair --all' moves them to their new directories.
+
+Examples:
+  bt config worktree-template
+  bt config worktree-template '{{ .Branch | replace "/" "-" }}'
+  bt config worktree-template '{{ .Branch | trimPrefix "users/alice/" | lower | trunc 30 }}'
+  bt config worktree-template --unset`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWorktreeTemplate,
+}
+
+func init() {
+	worktreeTemplateCmd.Flags().BoolVar(&worktreeTemplateUnset, "unset", false, "Remove the naming template (name worktrees after their branch)")
+	Cmd.AddCommand(worktreeTemplateCmd)
+}
+
+func runWorktreeTemplate(cmd *cobra.Command, args []string) error {
+	// Find repository root
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	repoRoot, err := repository.FindRoot(cwd)
+	if err != nil {
+		return fmt.Errorf("not in a baretree repository: %w", err)
+	}
+
+	bareDir, err := repository.GetBareRepoPath(repoRoot)
+	if err != nil {
+		return fmt.Errorf("failed to get bare repo path: %w", err)
+	}
+
+	cfg, err := config.LoadConfig(repoRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if worktreeTemplateUnset && len(args) > 0 {
+		return fmt.Errorf("cannot specify a template with --unset flag")
+	}
+
+	if !worktreeTemplateUnset && len(args) == 0 {
+		// Get mode: display current template
+		if cfg.Repository.WorktreeTemplate == "" {
+			fmt.Println("(none: worktrees are named after their branch)")
+			return nil
+		}
+		fmt.Println(cfg.Repository.WorktreeTemplate)
+		return nil
+	}
+
+	if worktreeTemplateUnset {
+		cfg.Repository.WorktreeTemplate = ""
+	} else {
+		if err := worktree.ValidateNamingTemplate(args[0]); err != nil {
+			return err
+		}
+		cfg.Repository.WorktreeTemplate = args[0]
+	}
+
+	if err := config.SaveConfig(repoRoot, cfg); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	if worktreeTemplateUnset {
+		fmt.Println("Worktree naming template removed (worktrees are named after their branch)")
+	} else {
+		fmt.Printf("Worktree naming template set to '%s'\n", cfg.Repository.WorktreeTemplate)
+	}
+
+	// Report existing worktrees that are not where the template puts them
+	wtMgr := worktree.NewManager(repoRoot, bareDir, cfg)
+	worktrees, err := wtMgr.List()
+	if err != nil {
+		return nil
+	}
+	var mismatched []string
+	for _, wt := range worktrees {
+		if wt.IsBare || wt.Branch == "" || !wtMgr.IsManaged(wt.Path) {
+			continue
+		}
+		relPath, _ := filepath.Rel(repoRoot, wt.Path)
+		if expected := wtMgr.ExpectedWorktreeDir(wt.Branch); relPath != expected {
+			mismatched = append(mismatched, fmt.Sprintf("  %s -> %s", relPath, expected))
+		}
+	}
+	if len(mismatched) > 0 {
+		fmt.Printf("\n%d existing worktree(s) do not match the new naming:\n", len(mismatched))
+		for _, line := range mismatched {
+			fmt.Println(line)
+		}
+		fmt.Println("\nRun 'bt repair --all' to move them.")
+	}
+
+	return nil
+}
@@ -0,0 +1,24 @@
+package editor
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for editor integration
+var Cmd = &cobra.Command{
+	Use:   "editor",
+	Short: "Integrate worktrees with editors (workspace)",
+	Long: `Integrate worktrees with editors.
+
+Subcommands:
+  workspace    Generate a multi-root workspace with one folder per worktree
+
+Examples:
+  bt editor workspace                      # VS Code <repo>.code-workspace
+  bt editor workspace --format jetbrains   # .idea/modules.xml with one module per worktree
+  bt editor workspace --auto               # Keep it current on bt add, rm and rename`,
+}
+
+func init() {
+	Cmd.AddCommand(workspaceCmd)
+}
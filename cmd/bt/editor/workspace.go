@@ -0,0 +1,109 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/amaya382/baretree/internal/config"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var (
+	workspaceFormat string
+	workspaceAuto   bool
+	workspaceNoAuto bool
+)
+
+var workspaceCmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Generate or update a multi-root editor workspace of all worktrees",
+	Long: `Generate or update an editor workspace at the repository root with one folder
+per worktree, the default branch first.
+
+Formats:
+  vscode      <repo>.code-workspace (VS Code multi-root workspace)
+              Settings, launch configurations and folders outside the
+              repository are kept when the file is updated.
+  jetbrains   .idea/modules.xml with one module per worktree
+              Module files are written to .idea/baretree/; other modules are kept.
+
+With --auto, the format is saved in the repository configuration and the
+workspace is updated whenever 'bt add', 'bt remove', 'bt rename' or
+'bt repair' changes the worktrees. --no-auto turns this off again.
+
+Without --format, the format saved with --auto is used, or vscode.
+
+Examples:
+  bt editor workspace
+  bt editor workspace --format jetbrains
+  bt editor workspace --auto
+  bt editor workspace --no-auto`,
+	Args: cobra.NoArgs,
+	RunE: runWorkspace,
+}
+
+func init() {
+	workspaceCmd.Flags().StringVarP(&workspaceFormat, "format", "f", "", "Workspace format: vscode or jetbrains")
+	workspaceCmd.Flags().BoolVar(&workspaceAuto, "auto", false, "Keep the workspace current as worktrees are added, removed and renamed")
+	workspaceCmd.Flags().BoolVar(&workspaceNoAuto, "no-auto", false, "Stop updating the workspace automatically")
+	workspaceCmd.MarkFlagsMutuallyExclusive("auto", "no-auto")
+	_ = workspaceCmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return worktree.EditorFormats, cobra.ShellCompDirectiveNoFileComp
+	})
+}
+
+func runWorkspace(cmd *cobra.Command, args []string) error {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	repoRoot, err := repository.FindRoot(cwd)
+	if err != nil {
+		return fmt.Errorf("not in a baretree repository: %w", err)
+	}
+
+	repoMgr, err := repository.NewManager(repoRoot)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	cfg := repoMgr.Config
+
+	format := workspaceFormat
+	if format == "" {
+		format = cfg.Repository.EditorWorkspace
+	}
+	if format == "" {
+		format = worktree.EditorFormatVSCode
+	}
+	if err := worktree.ValidateEditorFormat(format); err != nil {
+		return err
+	}
+
+	wtMgr := worktree.NewManager(repoRoot, repoMgr.BareDir, cfg)
+	if workspaceNoAuto {
+		cfg.Repository.EditorWorkspace = ""
+		if err := config.SaveConfig(repoRoot, cfg); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Println("✓ Automatic editor workspace updates disabled")
+		return nil
+	}
+
+	path, count, err := wtMgr.WriteEditorWorkspace(format)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Editor workspace written: %s (%d worktree(s))\n", path, count)
+
+	if workspaceAuto {
+		cfg.Repository.EditorWorkspace = format
+		if err := config.SaveConfig(repoRoot, cfg); err != nil {
+			return fmt.Errorf("failed to save config: %w", err)
+		}
+		fmt.Printf("✓ The %s workspace will be updated as worktrees are added, removed and renamed\n", format)
+	}
+	return nil
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var (
+	foreachRepos    bool
+	foreachFilter   string
+	foreachParallel int
+	foreachJSON     bool
+)
+
+var foreachCmd = &cobra.Command{
+	Use:     "foreach [flags] [--] <command> [args...]",
+	Aliases: []string{"exec"},
+	Short:   "Run a command in every worktree",
+	Long: `Run a command in every worktree of the current repository.
+
+With --repos, runs the command in the default worktree of every repository
+under the baretree root directories instead. This can be run from any directory.
+
+A single argument is run as a shell command, so it may use pipes and '&&';
+several arguments are run as a program with its arguments. Each output line is
+prefixed with the worktree's branch (or the repository path with --repos).
+Commands receive BT_REPO_ROOT, BT_WORKTREE_PATH and BT_BRANCH.
+
+--filter selects worktrees whose branch or directory (repositories whose path
+or name with --repos) matches a glob pattern, where '*' does not match '/'.
+
+Every command runs even if others fail; bt exits with a non-zero status if any
+of them failed. --json prints a summary with each command's output instead.
+
+Examples:
+  bt foreach -- git status -s
+  bt foreach --filter 'feature/*' -- make test
+  bt foreach --parallel 4 -- 'npm ci && npm test'
+  bt foreach --repos -- git pull --ff-only
+  bt foreach --repos --filter 'github.com/my-org/*' --json -- git status -s`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runForeach,
+}
+
+func init() {
+	foreachCmd.Flags().BoolVar(&foreachRepos, "repos", false, "Run in the default worktree of every repository under the baretree root")
+	foreachCmd.Flags().StringVar(&foreachFilter, "filter", "", "Only run in worktrees (or repositories) matching a glob pattern")
+	foreachCmd.Flags().IntVarP(&foreachParallel, "parallel", "p", 1, "Number of commands to run concurrently")
+	foreachCmd.Flags().BoolVar(&foreachJSON, "json", false, "Output a JSON summary of the results")
+	// Flags after the command belong to the command (bt foreach git status -s)
+	foreachCmd.Flags().SetInterspersed(false)
+}
+
+type foreachOutput struct {
+	Command   string              `json:"command"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Results   []foreachResultJSON `json:"results"`
+}
+
+type foreachResultJSON struct {
+	Name       string `json:"name"` // branch, or repository path with --repos
+	Path       string `json:"path"`
+	Success    bool   `json:"success"`
+	ExitCode   int    `json:"exit_code"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	Output     string `json:"output"`
+}
+
+func runForeach(cmd *cobra.Command, args []string) error {
+	if foreachParallel <= 0 {
+		return fmt.Errorf("invalid value for --parallel: %d (must be at least 1)", foreachParallel)
+	}
+
+	var targets []worktree.ForeachTarget
+	var err error
+	kind, none := "worktree(s)", "No worktrees matched"
+	if foreachRepos {
+		kind, none = "repository(ies)", "No repositories matched"
+		targets, err = repositoryForeachTargets()
+	} else {
+		targets, err = worktreeForeachTargets()
+	}
+	if err != nil {
+		return err
+	}
+
+	var selected []worktree.ForeachTarget
+	for _, target := range targets {
+		matched, err := worktree.MatchForeachFilter(target, foreachFilter)
+		if err != nil {
+			return err
+		}
+		if matched {
+			selected = append(selected, target)
+		}
+	}
+
+	command := worktree.FormatForeachCommand(args)
+	opts := worktree.ForeachOptions{Parallel: foreachParallel}
+	if foreachJSON {
+		opts.CaptureOutput = true
+	} else {
+		if len(selected) == 0 {
+			fmt.Println(none)
+			return nil
+		}
+		fmt.Printf("Running '%s' in %d %s...\n", command, len(selected), kind)
+		opts.Writer = os.Stdout
+	}
+
+	results := worktree.RunForeach(selected, args, opts)
+
+	failed := 0
+	for _, r := range results {
+		if !r.Success {
+			failed++
+		}
+	}
+
+	if foreachJSON {
+		output := foreachOutput{
+			Command:   command,
+			Succeeded: len(results) - failed,
+			Failed:    failed,
+			Results:   []foreachResultJSON{},
+		}
+		for _, r := range results {
+			output.Results = append(output.Results, foreachResultJSON{
+				Name:       r.Target.Label,
+				Path:       r.Target.Path,
+				Success:    r.Success,
+				ExitCode:   r.ExitCode,
+				Error:      r.Error,
+				DurationMs: r.Duration.Milliseconds(),
+				Output:     r.Output,
+			})
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(output); err != nil {
+			return err
+		}
+	} else {
+		printForeachSummary(results)
+	}
+
+	if failed > 0 {
+		// Failures are already reported in the output; the usage text would only add noise
+		cmd.SilenceUsage = true
+		return fmt.Errorf("command failed in %d of %d %s", failed, len(results), kind)
+	}
+	return nil
+}
+
+// printForeachSummary prints the status and duration of the command in each target
+func printForeachSummary(results []worktree.ForeachResult) {
+	maxLabelLen := 0
+	for _, r := range results {
+		if len(r.Target.Label) > maxLabelLen {
+			maxLabelLen = len(r.Target.Label)
+		}
+	}
+
+	failed := 0
+	fmt.Println("\nSummary:")
+	for _, r := range results {
+		duration := fmt.Sprintf("%.1fs", r.Duration.Seconds())
+		if r.Success {
+			fmt.Printf("  ✓ %-*s  %6s\n", maxLabelLen, r.Target.Label, duration)
+			continue
+		}
+		failed++
+		fmt.Printf("  ✗ %-*s  %6s  %s\n", maxLabelLen, r.Target.Label, duration, r.Error)
+	}
+	fmt.Printf("\n%d succeeded, %d failed\n", len(results)-failed, failed)
+}
+
+// worktreeForeachTargets returns the worktrees of the current repository
+func worktreeForeachTargets() ([]worktree.ForeachTarget, error) {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	repoRoot, err := repository.FindRoot(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("not in a baretree repository: %w", err)
+	}
+
+	bareDir, err := repository.GetBareRepoPath(repoRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bare repo path: %w", err)
+	}
+
+	mgr, err := repository.NewManager(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	wtMgr := worktree.NewManager(repoRoot, bareDir, mgr.Config)
+	targets, err := wtMgr.ForeachTargets()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	return targets, nil
+}
+
+// repositoryForeachTargets returns the default worktree of every repository under the baretree roots.
+// Repositories without one are skipped with a warning.
+func repositoryForeachTargets() ([]worktree.ForeachTarget, error) {
+	cfg, err := global.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repos, err := global.ListRepositories(cfg.Roots)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan repositories: %w", err)
+	}
+
+	var targets []worktree.ForeachTarget
+	for _, repo := range repos {
+		mgr, err := repository.NewManager(repo.Path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", repo.RelativePath, err)
+			continue
+		}
+		wtMgr := worktree.NewManager(repo.Path, mgr.BareDir, mgr.Config)
+
+		defaultBranch := wtMgr.GetDefaultBranch()
+		worktreePath, err := wtMgr.WorktreePath(defaultBranch)
+		if err == nil {
+			if info, statErr := os.Stat(worktreePath); statErr != nil || !info.IsDir() {
+				err = fmt.Errorf("default worktree %s not found", worktreePath)
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %s: %v\n", repo.RelativePath, err)
+			continue
+		}
+
+		targets = append(targets, worktree.ForeachTarget{
+			Label: repo.RelativePath,
+			Path:  worktreePath,
+			Names: []string{repo.Name},
+			Env: []string{
+				"BT_REPO_ROOT=" + repo.Path,
+				"BT_WORKTREE_PATH=" + worktreePath,
+				"BT_BRANCH=" + defaultBranch,
+			},
+		})
+	}
+	return targets, nil
+}
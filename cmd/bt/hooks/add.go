@@ -0,0 +1,69 @@
+package hooks
+
+import (
+	"fmt"
+
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var addCmd = &cobra.Command{
+	Use:   "add <event> <command>",
+	Short: "Add a lifecycle hook",
+	Long: `Add a shell command to run on a worktree lifecycle event.
+
+Events: pre-remove, post-remove, post-rename, post-checkout
+
+Commands of the same event run in the order they were added.
+
+Examples:
+  bt hooks add pre-remove "docker compose down"
+  bt hooks add post-remove 'dropdb "app_$(echo "$BT_BRANCH" | tr / _)"'
+  bt hooks add post-checkout "npm install"`,
+	Args:              cobra.ExactArgs(2),
+	RunE:              runHooksAdd,
+	ValidArgsFunction: completeEvents,
+}
+
+func runHooksAdd(cmd *cobra.Command, args []string) error {
+	event := args[0]
+	command := args[1]
+
+	mgr, err := newManager(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := mgr.AddHook(event, command); err != nil {
+		return err
+	}
+
+	fmt.Printf("+ %s hook added: %s\n", event, command)
+	return nil
+}
+
+// newManager creates a worktree manager for the repository containing the current directory
+func newManager(cmd *cobra.Command) (*worktree.Manager, error) {
+	cwd, err := cmd.Flags().GetString("cwd")
+	if err != nil || cwd == "" {
+		cwd = "."
+	}
+
+	repoRoot, err := repository.FindRoot(cwd)
+	if err != nil {
+		return nil, fmt.Errorf("not in a baretree repository: %w", err)
+	}
+
+	bareDir, err := repository.GetBareRepoPath(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	repoMgr, err := repository.NewManager(repoRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	return worktree.NewManager(repoRoot, bareDir, repoMgr.Config), nil
+}
@@ -0,0 +1,64 @@
+package hooks
+
+import (
+	"os"
+	"strings"
+
+	"github.com/amaya382/baretree/internal/config"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+// completeEvents completes hook event names for the first argument
+func completeEvents(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	var completions []string
+	for _, event := range config.HookEvents {
+		if strings.HasPrefix(event, toComplete) {
+			completions = append(completions, event)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeConfiguredHooks completes event names, then the commands configured for that event
+func completeConfiguredHooks(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeEvents(cmd, args, toComplete)
+	}
+	if len(args) > 1 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	completions := []string{}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	repoRoot, err := repository.FindRoot(cwd)
+	if err != nil {
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	mgr, err := repository.NewManager(repoRoot)
+	if err != nil {
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	hooks := mgr.Config.Hooks(args[0])
+	if hooks == nil {
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+	for _, hook := range *hooks {
+		if strings.HasPrefix(hook.Command, toComplete) {
+			completions = append(completions, hook.Command)
+		}
+	}
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
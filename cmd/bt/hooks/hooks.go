@@ -0,0 +1,57 @@
+package hooks
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for lifecycle hook management
+var Cmd = &cobra.Command{
+	Use:   "hooks",
+	Short: "Manage lifecycle hooks (pre-remove, post-remove, post-rename, post-checkout)",
+	Long: `Manage shell commands that run on worktree lifecycle events.
+
+Events:
+  pre-remove     Before 'bt remove' deletes a worktree (runs in the worktree)
+                 A failing command aborts the removal unless --force is given
+  post-remove    After 'bt remove' deletes a worktree (runs in the repository root)
+  post-rename    After 'bt rename' renames a worktree (runs in the renamed worktree)
+  post-checkout  After 'bt unbare' creates a standalone repository (runs in the new repository)
+
+Hook commands are executed via 'sh -c' and receive these environment variables:
+  BT_HOOK               Lifecycle event name
+  BT_REPO_ROOT          Repository root directory
+  BT_WORKTREE_PATH      Path of the worktree (the new repository for post-checkout)
+  BT_BRANCH             Branch of the worktree
+  BT_DEFAULT_BRANCH     Configured default branch
+  BT_SHARED_DIR         Directory holding managed shared files (.shared)
+  BT_OLD_WORKTREE_PATH  Previous worktree path (post-rename only)
+  BT_OLD_BRANCH         Previous branch name (post-rename only)
+
+Use 'bt post-create' to manage actions that run when a worktree is created.
+
+Examples:
+  bt hooks add pre-remove "docker compose down"
+  bt hooks add post-rename 'echo "renamed $BT_OLD_BRANCH to $BT_BRANCH"'
+  bt hooks list
+  bt hooks remove pre-remove "docker compose down"`,
+}
+
+func init() {
+	// Custom help template with alias information (uses nameWithAlias registered in main.go)
+	Cmd.SetHelpTemplate(`{{with (or .Long .Short)}}{{. | trimTrailingWhitespaces}}
+{{end}}{{if .HasAvailableSubCommands}}
+Available Commands:{{range .Commands}}{{if (or .IsAvailableCommand (eq .Name "help"))}}
+  {{rpad (nameWithAlias .) .NamePadding }} {{.Short}}{{end}}{{end}}{{end}}{{if .HasAvailableLocalFlags}}
+Flags:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableInheritedFlags}}
+
+Global Flags:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}{{end}}{{if .HasAvailableSubCommands}}
+
+Use "{{.CommandPath}} [command] --help" for more information about a command.{{end}}
+`)
+
+	Cmd.AddCommand(addCmd)
+	Cmd.AddCommand(removeCmd)
+	Cmd.AddCommand(listCmd)
+}
@@ -0,0 +1,60 @@
+package hooks
+
+import (
+	"fmt"
+
+	"github.com/amaya382/baretree/internal/config"
+	"github.com/spf13/cobra"
+)
+
+var listCmd = &cobra.Command{
+	Use:     "list [event]",
+	Aliases: []string{"ls"},
+	Short:   "List lifecycle hooks",
+	Long: `List configured lifecycle hooks, optionally limited to a single event.
+
+Examples:
+  bt hooks list
+  bt hooks ls pre-remove`,
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runHooksList,
+	ValidArgsFunction: completeEvents,
+}
+
+func runHooksList(cmd *cobra.Command, args []string) error {
+	mgr, err := newManager(cmd)
+	if err != nil {
+		return err
+	}
+
+	events := config.HookEvents
+	if len(args) > 0 {
+		if mgr.Config.Hooks(args[0]) == nil {
+			return fmt.Errorf("unknown hook event: %s", args[0])
+		}
+		events = []string{args[0]}
+	}
+
+	found := false
+	for _, event := range events {
+		hooks := *mgr.Config.Hooks(event)
+		if len(hooks) == 0 {
+			continue
+		}
+		if found {
+			fmt.Println()
+		}
+		found = true
+
+		fmt.Printf("%s:\n", event)
+		for _, hook := range hooks {
+			fmt.Printf("  %s\n", hook.Command)
+		}
+	}
+
+	if !found {
+		fmt.Println("No hooks configured.")
+	}
+
+	return nil
+}
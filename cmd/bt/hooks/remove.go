@@ -0,0 +1,38 @@
+package hooks
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var removeCmd = &cobra.Command{
+	Use:     "remove <event> <command>",
+	Aliases: []string{"rm"},
+	Short:   "Remove a lifecycle hook",
+	Long: `Remove a shell command from a lifecycle event.
+
+Examples:
+  bt hooks remove pre-remove "docker compose down"
+  bt hooks rm post-checkout "npm install"`,
+	Args:              cobra.ExactArgs(2),
+	RunE:              runHooksRemove,
+	ValidArgsFunction: completeConfiguredHooks,
+}
+
+func runHooksRemove(cmd *cobra.Command, args []string) error {
+	event := args[0]
+	command := args[1]
+
+	mgr, err := newManager(cmd)
+	if err != nil {
+		return err
+	}
+
+	if err := mgr.RemoveHook(event, command); err != nil {
+		return err
+	}
+
+	fmt.Printf("+ %s hook removed: %s\n", event, command)
+	return nil
+}
@@ -13,8 +13,9 @@ import (
 )
 
 var (
-	listJSON  bool
-	listPaths bool
+	listJSON     bool
+	listPaths    bool
+	listAllRepos bool
 )
 
 var listCmd = &cobra.Command{
@@ -25,6 +26,10 @@ var listCmd = &cobra.Command{
 
 Shows worktree path, branch name, HEAD commit, and management status.
 
+With --all-repos, lists the worktrees of every repository under the baretree
+root directories instead, showing the repository, branch, dirty state, and
+age of the last commit. This can be run from any directory.
+
 Indicators (first two columns):
   * = Current worktree (where you are now)
   @ = Default worktree (configured default branch)
@@ -33,16 +38,23 @@ Examples:
   bt list
   bt ls
   bt list --json
-  bt list --paths`,
+  bt list --paths
+  bt ls --all-repos
+  bt ls --all-repos --json`,
 	RunE: runList,
 }
 
 func init() {
 	listCmd.Flags().BoolVar(&listJSON, "json", false, "Output as JSON")
 	listCmd.Flags().BoolVar(&listPaths, "paths", false, "Output only paths (for scripting)")
+	listCmd.Flags().BoolVarP(&listAllRepos, "all-repos", "A", false, "List worktrees of all repositories under the baretree root")
 }
 
 func runList(cmd *cobra.Command, args []string) error {
+	if listAllRepos {
+		return runListAllRepos()
+	}
+
 	// Find repository root
 	cwd, err := os.Getwd()
 	if err != nil {
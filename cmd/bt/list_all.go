@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/amaya382/baretree/internal/global"
+)
+
+// runListAllRepos lists the worktrees of every repository under the baretree roots
+func runListAllRepos() error {
+	cfg, err := global.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repos, err := global.ListRepositories(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to scan repositories: %w", err)
+	}
+
+	var worktrees []global.WorktreeInfo
+	for _, r := range global.ListAllWorktrees(repos, global.DefaultListJobs) {
+		if r.Err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to list worktrees of %s: %v\n", r.Repo.RelativePath, r.Err)
+			continue
+		}
+		worktrees = append(worktrees, r.Worktrees...)
+	}
+
+	if listJSON {
+		// Always emit an array so the output can be consumed without special cases
+		if worktrees == nil {
+			worktrees = []global.WorktreeInfo{}
+		}
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(worktrees)
+	}
+
+	if len(worktrees) == 0 {
+		fmt.Println("No worktrees found")
+		return nil
+	}
+
+	if listPaths {
+		for _, wt := range worktrees {
+			fmt.Println(wt.Path)
+		}
+		return nil
+	}
+
+	// Calculate column widths
+	maxRepoLen := len("REPOSITORY")
+	maxBranchLen := len("BRANCH")
+	for _, wt := range worktrees {
+		if len(wt.Repository) > maxRepoLen {
+			maxRepoLen = len(wt.Repository)
+		}
+		if len(wt.Branch) > maxBranchLen {
+			maxBranchLen = len(wt.Branch)
+		}
+	}
+
+	now := time.Now()
+	fmt.Printf("  %-*s  %-*s  %-5s  %s\n", maxRepoLen, "REPOSITORY", maxBranchLen, "BRANCH", "STATE", "LAST COMMIT")
+	for _, wt := range worktrees {
+		defaultMark := " "
+		if wt.IsDefault {
+			defaultMark = "@"
+		}
+
+		branchName := wt.Branch
+		if branchName == "" {
+			branchName = "(detached)"
+		}
+
+		state := "clean"
+		if wt.Dirty {
+			state = "dirty"
+		}
+
+		fmt.Printf("%s %-*s  %-*s  %-5s  %s\n",
+			defaultMark,
+			maxRepoLen, wt.Repository,
+			maxBranchLen, branchName,
+			state,
+			formatAge(wt.LastCommit, now),
+		)
+	}
+
+	return nil
+}
+
+// formatAge formats the time elapsed since t in a compact human-readable form
+func formatAge(t, now time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(d.Hours()/24/30))
+	default:
+		return fmt.Sprintf("%dy ago", int(d.Hours()/24/365))
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logLimit   int
+	logAll     bool
+	logVerbose bool
+)
+
+var logCmd = &cobra.Command{
+	Use:   "log",
+	Short: "Show the journal of operations that can be undone",
+	Long: `Show the journal of operations performed by bt, most recent first.
+
+Every command that changes worktrees, branches or repositories (bt add, rm,
+rename, repair, prune, unbare, the repo commands that create, remove or migrate
+repositories, the workspace commands, and trash restore and empty) records what
+it changed (paths, branch names and the commits refs pointed to), so 'bt undo'
+can revert it. Inside a repository, only its operations are shown
+unless --all is given.
+
+The journal is kept in $XDG_STATE_HOME/baretree/journal.json
+(~/.local/state/baretree/journal.json) and holds the last 200 operations.
+
+Examples:
+  bt log              # Operations in the current repository
+  bt log -v           # Include the recorded steps
+  bt log --all -n 50  # Last 50 operations in all repositories`,
+	Args: cobra.NoArgs,
+	RunE: runLog,
+}
+
+func init() {
+	logCmd.Flags().IntVarP(&logLimit, "limit", "n", 20, "Maximum number of operations to show (0 for all)")
+	logCmd.Flags().BoolVarP(&logAll, "all", "a", false, "Show operations of all repositories")
+	logCmd.Flags().BoolVarP(&logVerbose, "verbose", "v", false, "Show the steps of each operation")
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	repoRoot := ""
+	if !logAll {
+		repoRoot = currentRepoRoot()
+	}
+	entries, err := global.ListJournal(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No operations recorded")
+		return nil
+	}
+	if logLimit > 0 && len(entries) > logLimit {
+		entries = entries[:logLimit]
+	}
+
+	now := time.Now()
+	for _, e := range entries {
+		status := ""
+		if e.UndoneAt != nil {
+			status = "  (undone)"
+		} else if !e.Reversible() {
+			status = "  (cannot be undone)"
+		}
+		fmt.Printf("#%-4d %-10s %s%s\n", e.ID, formatAge(e.Time, now), e.Command, status)
+		if logVerbose {
+			for _, step := range e.Steps {
+				fmt.Printf("        %s\n", step.Describe())
+			}
+		}
+	}
+	return nil
+}
@@ -6,9 +6,14 @@ import (
 	"strings"
 
 	"github.com/amaya382/baretree/cmd/bt/config"
+	"github.com/amaya382/baretree/cmd/bt/editor"
+	"github.com/amaya382/baretree/cmd/bt/hooks"
 	"github.com/amaya382/baretree/cmd/bt/postcreate"
 	"github.com/amaya382/baretree/cmd/bt/repo"
 	"github.com/amaya382/baretree/cmd/bt/synctoroot"
+	"github.com/amaya382/baretree/cmd/bt/trash"
+	"github.com/amaya382/baretree/cmd/bt/workspace"
+	"github.com/amaya382/baretree/internal/global"
 	"github.com/spf13/cobra"
 )
 
@@ -101,6 +106,7 @@ Use "{{.CommandPath}} [command] --help" for more information about a command.{{e
 	addCmd.GroupID = groupWorktree
 	listCmd.GroupID = groupWorktree
 	removeCmd.GroupID = groupWorktree
+	pruneCmd.GroupID = groupWorktree
 	cdCmd.GroupID = groupWorktree
 	statusCmd.GroupID = groupWorktree
 	renameCmd.GroupID = groupWorktree
@@ -108,11 +114,18 @@ Use "{{.CommandPath}} [command] --help" for more information about a command.{{e
 	showRootCmd.GroupID = groupWorktree
 	postcreate.Cmd.GroupID = groupWorktree
 	synctoroot.Cmd.GroupID = groupWorktree
+	hooks.Cmd.GroupID = groupWorktree
 	unbareCmd.GroupID = groupWorktree
 	config.Cmd.GroupID = groupWorktree
+	foreachCmd.GroupID = groupWorktree
+	editor.Cmd.GroupID = groupWorktree
+	undoCmd.GroupID = groupWorktree
+	logCmd.GroupID = groupWorktree
 
 	// Repository management commands (init, clone, migrate + ghq-like operations)
 	repo.Cmd.GroupID = groupRepo
+	workspace.Cmd.GroupID = groupRepo
+	trash.Cmd.GroupID = groupRepo
 
 	// Miscellaneous commands
 	shellInitCmd.GroupID = groupMisc
@@ -139,18 +152,26 @@ Use "{{.CommandPath}} [command] --help" for more information about a command.{{e
 	rootCmd.AddCommand(addCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(removeCmd)
+	rootCmd.AddCommand(pruneCmd)
 	rootCmd.AddCommand(cdCmd)
 	rootCmd.AddCommand(statusCmd)
 	rootCmd.AddCommand(repairCmd)
 	rootCmd.AddCommand(shellInitCmd)
 	rootCmd.AddCommand(versionCmd)
 	rootCmd.AddCommand(repo.Cmd)
+	rootCmd.AddCommand(workspace.Cmd)
 	rootCmd.AddCommand(postcreate.Cmd)
 	rootCmd.AddCommand(synctoroot.Cmd)
+	rootCmd.AddCommand(hooks.Cmd)
 	rootCmd.AddCommand(renameCmd)
 	rootCmd.AddCommand(unbareCmd)
 	rootCmd.AddCommand(config.Cmd)
 	rootCmd.AddCommand(showRootCmd)
+	rootCmd.AddCommand(foreachCmd)
+	rootCmd.AddCommand(editor.Cmd)
+	rootCmd.AddCommand(trash.Cmd)
+	rootCmd.AddCommand(undoCmd)
+	rootCmd.AddCommand(logCmd)
 
 	// Top-level aliases for repo commands
 	repo.InitAliasCmd.GroupID = groupRepoAlias
@@ -168,8 +189,42 @@ Use "{{.CommandPath}} [command] --help" for more information about a command.{{e
 }
 
 func main() {
+	rootCmd.SetArgs(escapeHistoryArgs(os.Args[1:]))
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
+
+// escapeHistoryArgs moves a history position like "-2" in "bt cd -2", "bt go -2" and
+// "bt repo cd -2" behind "--", so that it is taken as an argument instead of a flag while
+// the other arguments are still parsed as flags
+func escapeHistoryArgs(args []string) []string {
+	pos := 1
+	if len(args) >= 2 && args[0] == "repo" && args[1] == "cd" {
+		pos = 2
+	} else if len(args) == 0 || (args[0] != "cd" && args[0] != "go") {
+		return args
+	}
+
+	for i := pos; i < len(args); i++ {
+		if args[i] == "--" {
+			return args
+		}
+		if _, ok := global.ParseHistoryPosition(args[i]); ok && args[i] != "-" {
+			escaped := append([]string{}, args[:i]...)
+			rest := args[i+1:]
+			for j, arg := range rest {
+				if arg == "--" {
+					// Keep it first among the arguments after an existing "--"
+					escaped = append(escaped, rest[:j+1]...)
+					escaped = append(escaped, args[i])
+					return append(escaped, rest[j+1:]...)
+				}
+			}
+			escaped = append(escaped, rest...)
+			return append(escaped, "--", args[i])
+		}
+	}
+	return args
+}
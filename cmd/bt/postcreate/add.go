@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/amaya382/baretree/internal/config"
 	"github.com/amaya382/baretree/internal/repository"
 	"github.com/amaya382/baretree/internal/worktree"
 	"github.com/spf13/cobra"
@@ -12,6 +13,12 @@ import (
 
 var (
 	addNoManaged bool
+	addName      string
+	addParallel  bool
+	addDependsOn []string
+	addTimeout   string
+	addRetries   int
+	addRequired  bool
 )
 
 var addCmd = &cobra.Command{
@@ -22,30 +29,72 @@ var addCmd = &cobra.Command{
 Types:
   - symlink: Create a symlink to a shared file
   - copy: Copy a file to the new worktree
+  - template: Render a Go text/template file into the new worktree
   - command: Execute a shell command in the new worktree
 
-For symlink/copy types:
+For symlink/copy/template types:
   - The source file must exist in the default branch worktree (usually main).
   - Managed (default): File is moved to .shared/ directory, independent of any worktree
   - Non-managed (--no-managed): File is sourced from the default branch worktree
 
+For template type:
+  - The file is rendered with these variables (e.g., {{.Port}}):
+      .Branch         Branch checked out in the worktree
+      .Slug           Branch sanitized for names (feature/auth -> feature-auth)
+      .Index          Position of the worktree among worktrees sorted by path (from 0)
+      .Port           Deterministic per-branch port in [3000, 4000)
+      .WorktreePath   Path of the worktree
+      .RepoRoot       Repository root directory
+      .RepoName       Base name of the repository root
+      .DefaultBranch  Configured default branch
+  - Functions: {{port 8000}} gives a per-branch port in [8000, 9000),
+    {{add 5000 .Index}} adds two integers
+
 For command type:
   - The source is the command string to execute
   - Commands are executed via 'sh -c' in the new worktree directory
-  - Command failures are treated as warnings (worktree creation continues)
+  - Command failures are treated as warnings (worktree creation continues) unless --required
+  - Commands receive these environment variables:
+      BT_REPO_ROOT       Repository root directory
+      BT_WORKTREE_PATH   Path of the new worktree
+      BT_BRANCH          Branch checked out in the new worktree
+      BT_BASE_BRANCH     Branch or commit the new branch was created from
+      BT_DEFAULT_BRANCH  Configured default branch
+      BT_SHARED_DIR      Directory holding managed shared files (.shared)
+  - Commands run one after another by default. Use --parallel to run a command
+    concurrently with adjacent parallel commands, or --depends-on to wait only for
+    specific commands (referenced by --name or command string). A command is skipped
+    if a command it depends on fails.
+  - When parallel or depends-on is used, output lines are prefixed with the command
+    name and a summary with per-command durations is printed
+  - --timeout kills a command that runs too long (e.g., 30s, 5m) and --retries
+    re-runs a failed command. With --required, a failing command rolls back the new
+    worktree and deletes its branch instead of leaving a half-initialized worktree
 
 Examples:
   bt post-create add symlink .env
   bt post-create add symlink .env --no-managed
   bt post-create add copy config/local.json
+  bt post-create add template .env
   bt post-create add command "direnv allow"
-  bt post-create add command "npm install"`,
+  bt post-create add command "npm install"
+  bt post-create add command 'docker compose -p "$(echo "$BT_BRANCH" | tr / -)" up -d'
+  bt post-create add command "npm install" --name deps --parallel
+  bt post-create add command "docker compose up -d" --name db --parallel
+  bt post-create add command "npm run migrate" --depends-on deps,db
+  bt post-create add command "npm ci" --timeout 5m --retries 2 --required`,
 	Args: cobra.ExactArgs(2),
 	RunE: runPostCreateAdd,
 }
 
 func init() {
-	addCmd.Flags().BoolVar(&addNoManaged, "no-managed", false, "Source file from the default branch worktree instead of .shared/ directory (symlink/copy only)")
+	addCmd.Flags().BoolVar(&addNoManaged, "no-managed", false, "Source file from the default branch worktree instead of .shared/ directory (symlink/copy/template only)")
+	addCmd.Flags().StringVar(&addName, "name", "", "Name used in output and --depends-on references (command only)")
+	addCmd.Flags().BoolVar(&addParallel, "parallel", false, "Run concurrently with adjacent parallel commands (command only)")
+	addCmd.Flags().StringSliceVar(&addDependsOn, "depends-on", nil, "Wait only for the given commands, by name or command string (command only)")
+	addCmd.Flags().StringVar(&addTimeout, "timeout", "", "Kill the command after this duration, e.g. 30s or 5m (command only)")
+	addCmd.Flags().IntVar(&addRetries, "retries", 0, "Number of times to re-run the command after a failure (command only)")
+	addCmd.Flags().BoolVar(&addRequired, "required", false, "Roll back worktree creation if the command fails (command only)")
 }
 
 func runPostCreateAdd(cmd *cobra.Command, args []string) error {
@@ -53,12 +102,15 @@ func runPostCreateAdd(cmd *cobra.Command, args []string) error {
 	source := args[1]
 
 	// Validate type
-	if actionType != "symlink" && actionType != "copy" && actionType != "command" {
-		return fmt.Errorf("invalid type: %s (must be 'symlink', 'copy', or 'command')", actionType)
+	if actionType != "symlink" && actionType != "copy" && actionType != "template" && actionType != "command" {
+		return fmt.Errorf("invalid type: %s (must be 'symlink', 'copy', 'template', or 'command')", actionType)
 	}
 
 	// Clean source for file types
 	if actionType != "command" {
+		if addName != "" || addParallel || len(addDependsOn) > 0 || addTimeout != "" || addRetries != 0 || addRequired {
+			return fmt.Errorf("--name, --parallel, --depends-on, --timeout, --retries and --required are only valid for the command type")
+		}
 		source = filepath.Clean(source)
 	}
 
@@ -100,7 +152,7 @@ func runPostCreateAdd(cmd *cobra.Command, args []string) error {
 	case "command":
 		fmt.Printf("Adding post-create command: %s\n\n", source)
 		fmt.Printf("  This command will be executed in new worktrees after creation.\n")
-	case "symlink", "copy":
+	case "symlink", "copy", "template":
 		if managed {
 			fmt.Printf("Adding post-create action: %s (type: %s, managed)\n\n", source, actionType)
 			fmt.Printf("  Source: %s/%s -> .shared/%s (move)\n", defaultBranch, source, source)
@@ -111,7 +163,20 @@ func runPostCreateAdd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Add post-create action
-	result, err := mgr.AddPostCreate(source, actionType, managed)
+	var result *worktree.PostCreateApplyResult
+	if actionType == "command" {
+		err = mgr.AddPostCreateCommand(config.PostCreateAction{
+			Source:    source,
+			Name:      addName,
+			Parallel:  addParallel,
+			DependsOn: addDependsOn,
+			Timeout:   addTimeout,
+			Retries:   addRetries,
+			Required:  addRequired,
+		})
+	} else {
+		result, err = mgr.AddPostCreate(source, actionType, managed)
+	}
 	if err != nil {
 		var conflictErr *worktree.PostCreateConflictError
 		if errors.As(err, &conflictErr) {
@@ -218,9 +218,12 @@ func printPostCreateInfo(action config.PostCreateAction, defaultBranch string) {
 		fmt.Printf("  Would create symlinks in all worktrees\n")
 	} else {
 		fmt.Printf("  Source: %s/%s\n", defaultBranch, action.Source)
-		if action.Type == "symlink" {
+		switch action.Type {
+		case "symlink":
 			fmt.Printf("  Would create symlinks in other worktrees\n")
-		} else {
+		case "template":
+			fmt.Printf("  Would render to other worktrees\n")
+		default:
 			fmt.Printf("  Would copy to other worktrees\n")
 		}
 	}
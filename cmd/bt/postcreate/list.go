@@ -2,7 +2,9 @@ package postcreate
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/amaya382/baretree/internal/config"
 	"github.com/amaya382/baretree/internal/repository"
 	"github.com/spf13/cobra"
 )
@@ -63,8 +65,8 @@ func runPostCreateList(cmd *cobra.Command, args []string) error {
 		var modeStr string
 		switch action.Type {
 		case "command":
-			modeStr = ""
-		case "symlink", "copy":
+			modeStr = commandOptions(action)
+		case "symlink", "copy", "template":
 			if action.Managed {
 				modeStr = "managed"
 			} else {
@@ -72,7 +74,7 @@ func runPostCreateList(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		fmt.Printf("  [%-7s] %-*s  %s\n",
+		fmt.Printf("  [%-8s] %-*s  %s\n",
 			action.Type,
 			maxSourceLen, action.Source,
 			modeStr,
@@ -81,3 +83,30 @@ func runPostCreateList(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// commandOptions formats the execution options of a command action (e.g., "name=deps, parallel")
+func commandOptions(action config.PostCreateAction) string {
+	var opts []string
+	if action.Name != "" {
+		opts = append(opts, "name="+action.Name)
+	}
+	if action.Parallel {
+		opts = append(opts, "parallel")
+	}
+	if len(action.DependsOn) > 0 {
+		opts = append(opts, "depends-on="+strings.Join(action.DependsOn, ","))
+	}
+	if action.Timeout != "" {
+		opts = append(opts, "timeout="+action.Timeout)
+	}
+	if action.Retries > 0 {
+		opts = append(opts, fmt.Sprintf("retries=%d", action.Retries))
+	}
+	if action.Required {
+		opts = append(opts, "required")
+	}
+	if len(opts) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(opts, ", ") + ")"
+}
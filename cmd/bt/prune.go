@@ -0,0 +1,368 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amaya382/baretree/internal/config"
+	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pruneDryRun    bool
+	pruneYes       bool
+	pruneForce     bool
+	pruneNoFetch   bool
+	pruneStaleDays int
+)
+
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove worktrees of merged, deleted or stale branches",
+	Long: `Find worktrees (and branches without a worktree) that are no longer needed,
+and remove them together with their branches.
+
+A worktree is a candidate if any of these apply:
+  - merged:        its branch is merged into the default branch
+  - upstream-gone: its branch's upstream was deleted on the remote (after fetch)
+  - stale:         no commits or file changes for --stale-days days
+  - broken:        its directory was moved or deleted
+
+A branch that still points at the commit it was created from is not considered
+merged. The default branch is never pruned.
+
+Candidates are listed with their reasons and you choose which ones to remove
+(e.g. "1,3-4" or "all"); use --yes to remove all candidates without asking. Worktrees with
+uncommitted changes and unmerged branches are kept unless --force is given.
+Pre-remove and post-remove hooks run as with 'bt remove'.
+
+Examples:
+  bt prune --dry-run
+  bt prune
+  bt prune --stale-days 30
+  bt prune --yes --no-fetch`,
+	Args: cobra.NoArgs,
+	RunE: runPrune,
+}
+
+func init() {
+	pruneCmd.Flags().BoolVarP(&pruneDryRun, "dry-run", "n", false, "List candidates without removing anything")
+	pruneCmd.Flags().BoolVarP(&pruneYes, "yes", "y", false, "Remove all candidates without asking")
+	pruneCmd.Flags().BoolVarP(&pruneForce, "force", "f", false, "Remove worktrees with uncommitted changes and unmerged branches")
+	pruneCmd.Flags().BoolVar(&pruneNoFetch, "no-fetch", false, "Do not fetch from remotes before checking upstreams")
+	pruneCmd.Flags().IntVar(&pruneStaleDays, "stale-days", 0, "Also prune worktrees without commits or file changes for this many days")
+}
+
+func runPrune(cmd *cobra.Command, args []string) error {
+	if pruneStaleDays < 0 {
+		return fmt.Errorf("--stale-days must not be negative")
+	}
+
+	// Find repository root
+	cwd, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	repoRoot, err := repository.FindRoot(cwd)
+	if err != nil {
+		return fmt.Errorf("not in a baretree repository: %w", err)
+	}
+
+	// Get bare repository path
+	bareDir, err := repository.GetBareRepoPath(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	// Load config and create manager
+	mgr, err := repository.NewManager(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	wtMgr := worktree.NewManager(repoRoot, bareDir, mgr.Config)
+
+	// Fetch so that branches deleted on the remote show up as upstream-gone
+	if !pruneNoFetch && wtMgr.Executor.HasRemotes() {
+		fmt.Println("Fetching from remotes...")
+		if _, err := wtMgr.Executor.Execute("fetch", "--all", "--prune"); err != nil {
+			fmt.Printf("Warning: failed to fetch: %v\n", err)
+		}
+	}
+
+	worktrees, err := wtMgr.List()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	// Broken worktrees are pruned as a whole; check the remaining ones against the criteria.
+	// They are keyed by their last known path, which is also their candidate path.
+	brokenWorktrees := detectBrokenWorktrees(bareDir)
+	broken := make(map[string]brokenWorktree)
+	for _, bw := range brokenWorktrees {
+		broken[bw.oldPath] = bw
+	}
+	var healthy []git.Worktree
+	for _, wt := range worktrees {
+		if _, ok := broken[wt.Path]; !ok {
+			healthy = append(healthy, wt)
+		}
+	}
+
+	candidates, err := wtMgr.FindPruneCandidates(healthy, findOrphanBranches(wtMgr, worktrees), worktree.PruneOptions{
+		StaleDays: pruneStaleDays,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to find prune candidates: %w", err)
+	}
+
+	defaultBranch := wtMgr.GetDefaultBranch()
+	for _, bw := range brokenWorktrees {
+		branch := bw.branch
+		if bw.detached {
+			branch = ""
+		} else if branch == defaultBranch {
+			continue
+		}
+		candidates = append(candidates, worktree.PruneCandidate{
+			Branch:  branch,
+			Path:    bw.oldPath,
+			Reasons: []string{worktree.PruneReasonBroken},
+		})
+	}
+
+	// Never prune the worktree we are in
+	cwdAbs, _ := filepath.Abs(cwd)
+	var skippedCurrent string
+	filtered := candidates[:0]
+	for _, c := range candidates {
+		if c.Path != "" && isPathWithin(cwdAbs, c.Path) {
+			skippedCurrent = c.Branch
+			continue
+		}
+		filtered = append(filtered, c)
+	}
+	candidates = filtered
+
+	if skippedCurrent != "" {
+		fmt.Printf("Skipping '%s' (current worktree)\n", skippedCurrent)
+	}
+
+	if len(candidates) == 0 {
+		fmt.Println("Nothing to prune.")
+		return nil
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Branch < candidates[j].Branch
+	})
+
+	printPruneCandidates(candidates, repoRoot, broken)
+
+	if pruneDryRun {
+		fmt.Println("\nDry run: nothing removed.")
+		return nil
+	}
+
+	selected := candidates
+	if !pruneYes {
+		fmt.Printf("\nSelect entries to remove (e.g. 1,3-4, 'all', or empty to cancel): ")
+		reader := bufio.NewReader(os.Stdin)
+		response, _ := reader.ReadString('\n')
+		indexes, err := parseSelection(response, len(candidates))
+		if err != nil {
+			return err
+		}
+		if len(indexes) == 0 {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+
+		selected = nil
+		for _, i := range indexes {
+			selected = append(selected, candidates[i])
+		}
+	}
+
+	fmt.Println()
+	op := global.NewOperation(repoRoot)
+	defer op.SaveOrWarn(os.Stdout)
+	failed := 0
+	for _, c := range selected {
+		var bw *brokenWorktree
+		if b, ok := broken[c.Path]; ok {
+			bw = &b
+		}
+		if err := pruneCandidate(wtMgr, op, c, repoRoot, bw); err != nil {
+			fmt.Printf("✗ %s: %v\n", candidateName(c), err)
+			failed++
+		}
+	}
+
+	if err := wtMgr.RefreshEditorWorkspace(); err != nil {
+		fmt.Printf("Warning: failed to update editor workspace: %v\n", err)
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("failed to prune %d of %d entries", failed, len(selected))
+	}
+	return nil
+}
+
+// candidateName returns the branch of a candidate, or "(detached)" for a detached worktree
+func candidateName(c worktree.PruneCandidate) string {
+	if c.Branch == "" {
+		return "(detached)"
+	}
+	return c.Branch
+}
+
+// printPruneCandidates prints numbered candidates with their reasons
+func printPruneCandidates(candidates []worktree.PruneCandidate, repoRoot string, broken map[string]brokenWorktree) {
+	maxBranchLen := len("BRANCH")
+	maxPathLen := len("PATH")
+	paths := make([]string, len(candidates))
+	for i, c := range candidates {
+		paths[i] = "-"
+		if c.Path != "" {
+			paths[i] = c.Path
+			if rel, err := filepath.Rel(repoRoot, c.Path); err == nil && !strings.HasPrefix(rel, "..") {
+				paths[i] = rel
+			}
+		}
+		if len(candidateName(c)) > maxBranchLen {
+			maxBranchLen = len(candidateName(c))
+		}
+		if len(paths[i]) > maxPathLen {
+			maxPathLen = len(paths[i])
+		}
+	}
+
+	now := time.Now()
+	fmt.Println("Prune candidates:")
+	fmt.Printf("  %3s  %-*s  %-*s  %-12s  %s\n", "#", maxBranchLen, "BRANCH", maxPathLen, "PATH", "LAST ACTIVE", "REASONS")
+	for i, c := range candidates {
+		reasons := strings.Join(c.Reasons, ", ")
+		if c.Dirty {
+			reasons += " (uncommitted changes)"
+		}
+		lastActive := "-"
+		if _, ok := broken[c.Path]; !ok {
+			lastActive = formatAge(c.LastActivity, now)
+		}
+		fmt.Printf("  %3d  %-*s  %-*s  %-12s  %s\n", i+1, maxBranchLen, candidateName(c), maxPathLen, paths[i], lastActive, reasons)
+	}
+}
+
+// pruneCandidate removes a candidate's worktree (if any) and its branch, recording both in op.
+// For a broken worktree (bw is non-nil), only its administrative files are removed.
+func pruneCandidate(wtMgr *worktree.Manager, op *global.Operation, c worktree.PruneCandidate, repoRoot string, bw *brokenWorktree) error {
+	branchRef := "refs/heads/" + c.Branch
+	oldSHA := global.ResolveRef(repoRoot, branchRef)
+
+	if bw != nil {
+		// Only this worktree: 'git worktree prune' would also drop other broken worktrees
+		// that 'bt repair' could still fix
+		if err := os.RemoveAll(filepath.Join(wtMgr.BareDir, "worktrees", bw.name)); err != nil {
+			return fmt.Errorf("failed to remove broken worktree: %w", err)
+		}
+		fmt.Printf("✓ Broken worktree removed: %s\n", c.Path)
+		if bw.detached {
+			return nil
+		}
+	} else if c.Path != "" {
+		// Run pre-remove hooks; a failure vetoes the removal unless forced
+		hookCtx := worktree.HookContext{WorktreePath: c.Path, Branch: c.Branch}
+		if _, err := wtMgr.RunHooks(config.HookPreRemove, hookCtx, os.Stdout); err != nil {
+			if !pruneForce {
+				return fmt.Errorf("removal aborted: %w", err)
+			}
+			fmt.Printf("Warning: %v (continuing because of --force)\n", err)
+		}
+
+		head, _ := git.NewExecutor(c.Path).Execute("rev-parse", "HEAD")
+		if err := wtMgr.Remove(c.Path, pruneForce); err != nil {
+			return err
+		}
+		op.Record(global.JournalStep{Action: global.StepRemoveWorktree, Path: c.Path, Branch: c.Branch, Head: head})
+		fmt.Printf("✓ Worktree removed: %s\n", c.Path)
+
+		hookCtx.Dir = repoRoot
+		_, _ = wtMgr.RunHooks(config.HookPostRemove, hookCtx, os.Stdout)
+	}
+
+	// Merged branches can be deleted safely; others (gone or stale) may hold unmerged work
+	deleteFlag := "-d"
+	if pruneForce || containsReason(c.Reasons, worktree.PruneReasonMerged) {
+		deleteFlag = "-D"
+	}
+	if _, err := wtMgr.Executor.Execute("branch", deleteFlag, c.Branch); err != nil {
+		return fmt.Errorf("failed to delete branch (use --force to delete unmerged branches): %w", err)
+	}
+	op.RecordRef(repoRoot, branchRef, oldSHA)
+	fmt.Printf("✓ Branch '%s' deleted\n", c.Branch)
+	return nil
+}
+
+// containsReason reports whether reasons includes reason
+func containsReason(reasons []string, reason string) bool {
+	for _, r := range reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// parseSelection parses a selection like "1,3-4" or "all" into zero-based indexes (in order, without duplicates)
+func parseSelection(input string, count int) ([]int, error) {
+	input = strings.TrimSpace(strings.ToLower(input))
+	if input == "" {
+		return nil, nil
+	}
+	if input == "all" || input == "a" {
+		indexes := make([]int, count)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes, nil
+	}
+
+	seen := make(map[int]bool)
+	var indexes []int
+	for _, part := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		from, to, isRange := strings.Cut(part, "-")
+		start, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection: %s", part)
+		}
+		end := start
+		if isRange {
+			if end, err = strconv.Atoi(to); err != nil {
+				return nil, fmt.Errorf("invalid selection: %s", part)
+			}
+		}
+		if start < 1 || end > count || start > end {
+			return nil, fmt.Errorf("selection out of range: %s (1-%d)", part, count)
+		}
+		for i := start; i <= end; i++ {
+			if !seen[i-1] {
+				seen[i-1] = true
+				indexes = append(indexes, i-1)
+			}
+		}
+	}
+	return indexes, nil
+}
@@ -6,6 +6,9 @@ import (
 	"os"
 	"strings"
 
+	"github.com/amaya382/baretree/internal/config"
+	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/global"
 	"github.com/amaya382/baretree/internal/repository"
 	"github.com/amaya382/baretree/internal/worktree"
 	"github.com/spf13/cobra"
@@ -14,6 +17,7 @@ import (
 var (
 	removeForce      bool
 	removeWithBranch bool
+	removePermanent  bool
 )
 
 var removeCmd = &cobra.Command{
@@ -27,18 +31,34 @@ The worktree name can be:
   - Directory name (e.g., feature/auth)
   - Path to worktree
 
+Before anything is removed, the worktree is checked for work that exists
+nowhere else: uncommitted changes, untracked files, commits missing from every
+remote (from every other branch if there are no remotes) and stashes made on
+its branch. If any is found, a report is printed and the removal is refused
+unless --force is given.
+
+Configured pre-remove hooks run in the worktree before it is removed; if one
+fails, the removal is aborted (use --force to remove anyway). Post-remove hooks
+run in the repository root afterwards. See 'bt hooks --help'.
+
+The worktree is moved into the trash rather than deleted, together with its
+uncommitted and untracked files and staged changes, so it can be brought back
+with 'bt trash restore'. Use --permanent to delete it right away.
+
 Examples:
   bt remove feature/auth
   bt rm feature/auth --with-branch
-  bt rm feature/auth --force`,
+  bt rm feature/auth --force
+  bt rm feature/auth --permanent`,
 	Args:              cobra.ExactArgs(1),
 	RunE:              runRemove,
 	ValidArgsFunction: completeWorktreeNames(false),
 }
 
 func init() {
-	removeCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "Force removal even with uncommitted changes")
+	removeCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "Force removal even with uncommitted changes or unpushed work")
 	removeCmd.Flags().BoolVarP(&removeWithBranch, "with-branch", "b", false, "Also delete the branch")
+	removeCmd.Flags().BoolVar(&removePermanent, "permanent", false, "Delete the worktree instead of moving it to the trash")
 }
 
 func runRemove(cmd *cobra.Command, args []string) error {
@@ -75,25 +95,79 @@ func runRemove(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to resolve worktree: %w", err)
 	}
 
-	// Get branch name before removal
+	// Get branch name before removal ("" for a detached HEAD)
 	branchName, _ := wtMgr.GetBranchName(worktreePath)
+	if branchName == "HEAD" {
+		branchName = ""
+	}
 
 	// Check if we're currently in the worktree
 	if strings.HasPrefix(cwd, worktreePath) {
 		return fmt.Errorf("cannot remove worktree while inside it")
 	}
 
-	fmt.Printf("Removing worktree at %s...\n", worktreePath)
+	// Refuse to lose work that exists nowhere else unless forced
+	check, err := wtMgr.CheckRemoval(worktreePath)
+	if err != nil {
+		if !removeForce {
+			return fmt.Errorf("failed to check worktree for unsaved work (use --force to remove anyway): %w", err)
+		}
+		fmt.Printf("Warning: failed to check worktree for unsaved work: %v\n", err)
+	} else if check.HasRisks() {
+		if !removeForce {
+			fmt.Printf("Worktree '%s' has work that exists nowhere else:\n", check.Name())
+			check.WriteReport(os.Stdout, "  ")
+			cmd.SilenceUsage = true
+			return fmt.Errorf("refusing to remove worktree '%s' (use --force to remove anyway)", check.Name())
+		}
+		fmt.Printf("Warning: removing worktree '%s' with work that exists nowhere else (--force):\n", check.Name())
+		check.WriteReport(os.Stdout, "  ")
+	}
+
+	// Run pre-remove hooks; a failure vetoes the removal unless forced
+	hookCtx := worktree.HookContext{WorktreePath: worktreePath, Branch: branchName}
+	if _, err := wtMgr.RunHooks(config.HookPreRemove, hookCtx, os.Stdout); err != nil {
+		if !removeForce {
+			return fmt.Errorf("removal aborted: %w", err)
+		}
+		fmt.Printf("Warning: %v (continuing because of --force)\n", err)
+	}
+
+	op := global.NewOperation(repoRoot)
+	defer op.SaveOrWarn(os.Stdout)
+
+	if removePermanent {
+		fmt.Printf("Removing worktree at %s...\n", worktreePath)
+
+		head, _ := git.NewExecutor(worktreePath).Execute("rev-parse", "HEAD")
+		if err := wtMgr.Remove(worktreePath, removeForce); err != nil {
+			return fmt.Errorf("failed to remove worktree: %w", err)
+		}
+		op.Record(global.JournalStep{Action: global.StepRemoveWorktree, Path: worktreePath, Branch: branchName, Head: head})
+
+		fmt.Printf("✓ Worktree removed\n")
+	} else {
+		fmt.Printf("Moving worktree at %s to the trash...\n", worktreePath)
+
+		entry, err := global.TrashWorktree(wtMgr, worktreePath)
+		if err != nil {
+			return fmt.Errorf("failed to remove worktree: %w", err)
+		}
+		op.Record(global.JournalStep{Action: global.StepTrash, Path: worktreePath, TrashID: entry.ID})
+
+		fmt.Printf("✓ Worktree moved to the trash (restore with 'bt trash restore %s')\n", entry.ID)
+	}
 
-	// Remove worktree
-	if err := wtMgr.Remove(worktreePath, removeForce); err != nil {
-		return fmt.Errorf("failed to remove worktree: %w", err)
+	if err := wtMgr.RefreshEditorWorkspace(); err != nil {
+		fmt.Printf("Warning: failed to update editor workspace: %v\n", err)
 	}
 
-	fmt.Printf("✓ Worktree removed\n")
+	// Run post-remove hooks from the repository root (the worktree no longer exists)
+	hookCtx.Dir = repoRoot
+	_, _ = wtMgr.RunHooks(config.HookPostRemove, hookCtx, os.Stdout)
 
 	// Ask about branch deletion if not forced
-	if branchName != "" && branchName != "detached" {
+	if branchName != "" {
 		deleteBranch := removeWithBranch
 
 		if !removeWithBranch && !removeForce {
@@ -112,9 +186,12 @@ func runRemove(cmd *cobra.Command, args []string) error {
 				forceFlag = "-d"
 			}
 
+			branchRef := "refs/heads/" + branchName
+			oldSHA := global.ResolveRef(repoRoot, branchRef)
 			if _, err := mgr.Executor.Execute("branch", forceFlag, branchName); err != nil {
 				fmt.Printf("Warning: failed to delete branch '%s': %v\n", branchName, err)
 			} else {
+				op.RecordRef(repoRoot, branchRef, oldSHA)
 				fmt.Printf("✓ Branch '%s' deleted\n", branchName)
 			}
 		}
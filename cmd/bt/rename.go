@@ -5,8 +5,11 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/amaya382/baretree/internal/config"
 	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/global"
 	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +29,9 @@ This command renames:
 Note: The worktree name, branch name, and directory name must be consistent.
 If they are not, use 'bt repair' to fix the inconsistency first.
 
+Configured post-rename hooks run in the renamed worktree afterwards.
+See 'bt hooks --help'.
+
 Examples:
   bt rename feature/new-name           # Rename current worktree
   bt rename feature/old feature/new    # Rename specified worktree`,
@@ -54,6 +60,13 @@ func runRename(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	// Load config and create manager (for post-rename hooks)
+	mgr, err := repository.NewManager(repoRoot)
+	if err != nil {
+		return err
+	}
+
+	wtMgr := worktree.NewManager(repoRoot, bareDir, mgr.Config)
 	executor := git.NewExecutor(bareDir)
 
 	// Determine old and new names
@@ -72,11 +85,6 @@ func runRename(cmd *cobra.Command, args []string) error {
 		newName = args[1]
 	}
 
-	// Validate names
-	if oldName == newName {
-		return fmt.Errorf("old and new names are the same")
-	}
-
 	// Get worktree info
 	output, err := executor.Execute("worktree", "list", "--porcelain")
 	if err != nil {
@@ -85,16 +93,25 @@ func runRename(cmd *cobra.Command, args []string) error {
 
 	worktrees := git.ParseWorktreeList(output)
 
-	// Find the worktree to rename
+	// Find the worktree to rename, by branch name or by directory
+	// (they differ when a worktree naming template is configured)
 	var targetWorktree *git.Worktree
-	oldWorktreePath := filepath.Join(repoRoot, oldName)
+	oldDirPath := filepath.Join(repoRoot, oldName)
 
 	for i := range worktrees {
-		if worktrees[i].Path == oldWorktreePath {
+		if !worktrees[i].IsBare && worktrees[i].Branch == oldName {
 			targetWorktree = &worktrees[i]
 			break
 		}
 	}
+	if targetWorktree == nil {
+		for i := range worktrees {
+			if worktrees[i].Path == oldDirPath {
+				targetWorktree = &worktrees[i]
+				break
+			}
+		}
+	}
 
 	if targetWorktree == nil {
 		return fmt.Errorf("worktree not found: %s", oldName)
@@ -104,15 +121,27 @@ func runRename(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot rename bare repository")
 	}
 
-	// Check consistency: worktree path should match branch name
-	expectedBranch := oldName
-	if targetWorktree.Branch != expectedBranch && targetWorktree.Branch != "" {
-		return fmt.Errorf("worktree name (%s) and branch name (%s) are inconsistent\nUse 'bt repair' to fix this inconsistency first", oldName, targetWorktree.Branch)
+	// Check consistency: worktree path should be the directory of its branch
+	oldWorktreePath := targetWorktree.Path
+	if targetWorktree.Branch != "" {
+		oldName = targetWorktree.Branch
+		if expectedPath, err := wtMgr.WorktreePath(oldName); err == nil && expectedPath != oldWorktreePath {
+			relPath, _ := filepath.Rel(repoRoot, oldWorktreePath)
+			return fmt.Errorf("worktree name (%s) and branch name (%s) are inconsistent\nUse 'bt repair' to fix this inconsistency first", relPath, oldName)
+		}
+	}
+	if oldName == newName {
+		return fmt.Errorf("old and new names are the same")
 	}
 
 	// Check if new worktree path already exists
-	newWorktreePath := filepath.Join(repoRoot, newName)
-	if _, err := os.Stat(newWorktreePath); err == nil {
+	newWorktreePath, err := wtMgr.WorktreePath(newName)
+	if err != nil {
+		return err
+	}
+	// The naming template may map both names to the same directory (e.g., when truncating)
+	moveDir := newWorktreePath != oldWorktreePath
+	if _, err := os.Stat(newWorktreePath); err == nil && moveDir {
 		return fmt.Errorf("destination already exists: %s", newWorktreePath)
 	}
 
@@ -129,6 +158,30 @@ func runRename(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to rename branch: %w", err)
 	}
 
+	// Recorded once the rename is complete; failed renames are rolled back
+	op := global.NewOperation(repoRoot)
+	op.Record(global.JournalStep{Action: global.StepRenameBranch, OldBranch: oldName, Branch: newName})
+
+	if !moveDir {
+		fmt.Printf("\n✓ Successfully renamed worktree\n")
+		fmt.Printf("  Old: %s\n", oldName)
+		fmt.Printf("  New: %s\n", newName)
+		fmt.Printf("  Path: %s (unchanged)\n", newWorktreePath)
+		op.SaveOrWarn(os.Stdout)
+
+		if err := wtMgr.RefreshEditorWorkspace(); err != nil {
+			fmt.Printf("Warning: failed to update editor workspace: %v\n", err)
+		}
+
+		_, _ = wtMgr.RunHooks(config.HookPostRename, worktree.HookContext{
+			WorktreePath:    newWorktreePath,
+			Branch:          newName,
+			OldWorktreePath: oldWorktreePath,
+			OldBranch:       oldName,
+		}, os.Stdout)
+		return nil
+	}
+
 	// Step 2: Move the worktree directory
 	fmt.Printf("  Moving directory...\n")
 
@@ -163,6 +216,9 @@ func runRename(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to update worktree registration: %w", err)
 	}
 
+	op.Record(global.JournalStep{Action: global.StepMoveWorktree, OldPath: oldWorktreePath, Path: newWorktreePath})
+	op.SaveOrWarn(os.Stdout)
+
 	// Clean up empty parent directories of old path
 	err = cleanupEmptyDirs(filepath.Dir(oldWorktreePath), repoRoot)
 
@@ -171,6 +227,18 @@ func runRename(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  New: %s\n", newName)
 	fmt.Printf("  Path: %s\n", newWorktreePath)
 
+	if err := wtMgr.RefreshEditorWorkspace(); err != nil {
+		fmt.Printf("Warning: failed to update editor workspace: %v\n", err)
+	}
+
+	// Run post-rename hooks (failures are reported but the rename is kept)
+	_, _ = wtMgr.RunHooks(config.HookPostRename, worktree.HookContext{
+		WorktreePath:    newWorktreePath,
+		Branch:          newName,
+		OldWorktreePath: oldWorktreePath,
+		OldBranch:       oldName,
+	}, os.Stdout)
+
 	return err
 }
 
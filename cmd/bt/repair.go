@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/global"
 	"github.com/amaya382/baretree/internal/repository"
 	"github.com/amaya382/baretree/internal/worktree"
 	"github.com/spf13/cobra"
@@ -114,7 +115,7 @@ func runRepair(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
-		target := analyzeWorktreeForRepair(wt, repoRoot)
+		target := analyzeWorktreeForRepair(wt, repoRoot, wtMgr.ExpectedWorktreeDir(wt.Branch))
 		if target != nil {
 			targets = append(targets, *target)
 		}
@@ -154,7 +155,7 @@ func runRepair(cmd *cobra.Command, args []string) error {
 					continue
 				}
 				relPath, _ := filepath.Rel(repoRoot, wt.Path)
-				if (relPath == name || wt.Branch == name) && isWorktreeManaged(wt, repoRoot) {
+				if (relPath == name || wt.Branch == name) && isWorktreeManaged(wt, repoRoot, wtMgr.ExpectedWorktreeDir(wt.Branch)) {
 					fmt.Printf("Worktree '%s' is already managed\n", name)
 					return nil
 				}
@@ -203,58 +204,70 @@ func runRepair(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Perform repair
+	// Perform repair, recording each completed repair in the journal
+	op := global.NewOperation(repoRoot)
+	defer op.SaveOrWarn(os.Stdout)
 	for _, t := range targets {
 		fmt.Printf("Repairing '%s'...\n", t.Branch)
 
 		if err := t.repair(repoRoot, bareDir, executor, wtMgr, repairSource); err != nil {
 			return fmt.Errorf("failed to repair %s: %w", t.Branch, err)
 		}
+		op.Record(t.journalStep(repoRoot, repairSource))
 
 		fmt.Printf("  Done\n")
 	}
 
 	fmt.Printf("\nSuccessfully repaired %d worktree(s)\n", len(targets))
+
+	if err := wtMgr.RefreshEditorWorkspace(); err != nil {
+		fmt.Printf("Warning: failed to update editor workspace: %v\n", err)
+	}
 	return nil
 }
 
 type repairTarget struct {
-	Path     string // Current absolute path
-	Branch   string // Branch name
-	DirName  string // Current directory name (relative to repo root, may be empty for external)
-	Issue    string // Description of the issue
-	External bool   // Whether the worktree is outside repo root
+	Path      string // Current absolute path
+	Branch    string // Branch name
+	DirName   string // Current directory name (relative to repo root, may be empty for external)
+	TargetDir string // Directory the branch belongs in (relative to repo root, see 'bt config worktree-template')
+	Issue     string // Description of the issue
+	External  bool   // Whether the worktree is outside repo root
 }
 
 type brokenWorktree struct {
-	name    string // Worktree directory name in .git/worktrees/
-	branch  string // Branch name
-	oldPath string // Last known path
+	name     string // Worktree directory name in .git/worktrees/
+	branch   string // Branch name
+	oldPath  string // Last known path
+	detached bool   // HEAD is not on a branch; branch holds the directory name
 }
 
-// analyzeWorktreeForRepair checks if a worktree needs repair and returns target info
-func analyzeWorktreeForRepair(wt git.Worktree, repoRoot string) *repairTarget {
+// analyzeWorktreeForRepair checks if a worktree needs repair and returns target info.
+// expectedDir is the directory the worktree's branch belongs in, relative to repoRoot.
+func analyzeWorktreeForRepair(wt git.Worktree, repoRoot, expectedDir string) *repairTarget {
 	relPath, err := filepath.Rel(repoRoot, wt.Path)
 
 	// External worktree (outside repo root)
 	if err != nil || strings.HasPrefix(relPath, "..") {
 		return &repairTarget{
-			Path:     wt.Path,
-			Branch:   wt.Branch,
-			DirName:  "",
-			Issue:    "Outside repository root",
-			External: true,
+			Path:      wt.Path,
+			Branch:    wt.Branch,
+			DirName:   "",
+			TargetDir: expectedDir,
+			Issue:     "Outside repository root",
+			External:  true,
 		}
 	}
 
 	// Internal worktree with name mismatch
-	if relPath != wt.Branch {
+	if relPath != expectedDir {
 		return &repairTarget{
-			Path:     wt.Path,
-			Branch:   wt.Branch,
-			DirName:  relPath,
-			Issue:    fmt.Sprintf("Directory '%s' doesn't match branch '%s'", relPath, wt.Branch),
-			External: false,
+			Path:      wt.Path,
+			Branch:    wt.Branch,
+			DirName:   relPath,
+			TargetDir: expectedDir,
+			Issue:     fmt.Sprintf("Directory '%s' doesn't match branch '%s'", relPath, wt.Branch),
+			External:  false,
 		}
 	}
 
@@ -262,34 +275,42 @@ func analyzeWorktreeForRepair(wt git.Worktree, repoRoot string) *repairTarget {
 }
 
 // isWorktreeManaged checks if a worktree is properly managed
-func isWorktreeManaged(wt git.Worktree, repoRoot string) bool {
+func isWorktreeManaged(wt git.Worktree, repoRoot, expectedDir string) bool {
 	relPath, err := filepath.Rel(repoRoot, wt.Path)
 	if err != nil || strings.HasPrefix(relPath, "..") {
 		return false
 	}
-	return relPath == wt.Branch
+	return relPath == expectedDir
 }
 
 func (t *repairTarget) describeAction(source string) string {
 	if t.External {
-		return fmt.Sprintf("Move to %s/", t.Branch)
+		return fmt.Sprintf("Move to %s/", t.TargetDir)
 	}
 
 	if source == "branch" {
-		return fmt.Sprintf("Rename directory '%s' -> '%s'", t.DirName, t.Branch)
+		return fmt.Sprintf("Rename directory '%s' -> '%s'", t.DirName, t.TargetDir)
 	}
 	return fmt.Sprintf("Rename branch '%s' -> '%s'", t.Branch, t.DirName)
 }
 
+// journalStep returns the journal step of a completed repair
+func (t *repairTarget) journalStep(repoRoot, source string) global.JournalStep {
+	if t.External || source == "branch" {
+		return global.JournalStep{Action: global.StepMoveWorktree, OldPath: t.Path, Path: filepath.Join(repoRoot, t.TargetDir)}
+	}
+	return global.JournalStep{Action: global.StepRenameBranch, OldBranch: t.Branch, Branch: t.DirName}
+}
+
 func (t *repairTarget) repair(repoRoot, bareDir string, executor *git.Executor, wtMgr *worktree.Manager, source string) error {
 	if t.External {
 		return t.repairExternal(repoRoot, bareDir, executor, wtMgr)
 	}
-	return t.repairInternal(repoRoot, bareDir, executor, source)
+	return t.repairInternal(repoRoot, bareDir, executor, wtMgr, source)
 }
 
 func (t *repairTarget) repairExternal(repoRoot, bareDir string, executor *git.Executor, wtMgr *worktree.Manager) error {
-	targetPath := filepath.Join(repoRoot, t.Branch)
+	targetPath := filepath.Join(repoRoot, t.TargetDir)
 
 	// Check if target already exists
 	if _, err := os.Stat(targetPath); err == nil {
@@ -323,15 +344,15 @@ func (t *repairTarget) repairExternal(repoRoot, bareDir string, executor *git.Ex
 	return nil
 }
 
-func (t *repairTarget) repairInternal(repoRoot, bareDir string, executor *git.Executor, source string) error {
+func (t *repairTarget) repairInternal(repoRoot, bareDir string, executor *git.Executor, wtMgr *worktree.Manager, source string) error {
 	if source == "branch" {
 		return t.renameDirToBranch(repoRoot, executor)
 	}
-	return t.renameBranchToDir(executor)
+	return t.renameBranchToDir(executor, wtMgr)
 }
 
 func (t *repairTarget) renameDirToBranch(repoRoot string, executor *git.Executor) error {
-	newPath := filepath.Join(repoRoot, t.Branch)
+	newPath := filepath.Join(repoRoot, t.TargetDir)
 
 	if _, err := os.Stat(newPath); err == nil {
 		return fmt.Errorf("target directory already exists: %s", newPath)
@@ -355,7 +376,12 @@ func (t *repairTarget) renameDirToBranch(repoRoot string, executor *git.Executor
 	return cleanupEmptyDirs(filepath.Dir(t.Path), repoRoot)
 }
 
-func (t *repairTarget) renameBranchToDir(executor *git.Executor) error {
+func (t *repairTarget) renameBranchToDir(executor *git.Executor, wtMgr *worktree.Manager) error {
+	// With a naming template, the directory name is not necessarily a name the template maps to itself
+	if expected := wtMgr.ExpectedWorktreeDir(t.DirName); expected != t.DirName {
+		return fmt.Errorf("branch '%s' would belong in '%s' by the worktree naming template; use --source=branch", t.DirName, expected)
+	}
+
 	if _, err := executor.Execute("show-ref", "--verify", "--quiet", "refs/heads/"+t.DirName); err == nil {
 		return fmt.Errorf("target branch already exists: %s", t.DirName)
 	}
@@ -508,7 +534,7 @@ func runFixPaths(repoRoot, bareDir string, executor *git.Executor, wtMgr *worktr
 
 			// Try to find the relative path by looking for common patterns
 			// The worktree name is stored in the HEAD file or can be inferred from gitdir
-			newPath := inferNewWorktreePath(repoRoot, bareDir, entry.Name(), oldWorktreePath)
+			newPath := inferNewWorktreePath(repoRoot, bareDir, wtMgr, entry.Name(), oldWorktreePath)
 			if newPath == "" {
 				// Could not find the worktree - it was likely moved to an unknown location
 				branchName := getBranchNameFromWorktree(bareDir, entry.Name())
@@ -585,6 +611,9 @@ func runFixPaths(repoRoot, bareDir string, executor *git.Executor, wtMgr *worktr
 
 	// For external paths, move them back into baretree structure
 	if len(externalPaths) > 0 {
+		op := global.NewOperation(repoRoot)
+		defer op.SaveOrWarn(os.Stdout)
+
 		fmt.Println()
 		fmt.Println("Moving worktrees into baretree structure...")
 
@@ -599,7 +628,11 @@ func runFixPaths(repoRoot, bareDir string, executor *git.Executor, wtMgr *worktr
 					continue
 				}
 
-				targetPath := filepath.Join(repoRoot, branchName)
+				targetPath, err := wtMgr.WorktreePath(branchName)
+				if err != nil {
+					fmt.Printf("  Warning: %v\n", err)
+					continue
+				}
 
 				if dryRun {
 					fmt.Printf("  Would move %s -> %s\n", extPath, targetPath)
@@ -640,6 +673,7 @@ func runFixPaths(repoRoot, bareDir string, executor *git.Executor, wtMgr *worktr
 					fmt.Printf("  Warning: failed to apply post-create config: %v\n", err)
 				}
 
+				op.Record(global.JournalStep{Action: global.StepMoveWorktree, OldPath: extPath, Path: targetPath})
 				fmt.Printf("  Moved %s -> %s\n", extPath, branchName)
 			}
 		}
@@ -673,7 +707,7 @@ func getBranchNameFromPath(bareDir, worktreePath string) string {
 
 // inferNewWorktreePath tries to determine the new worktree path based on the
 // current repository root and available information.
-func inferNewWorktreePath(repoRoot, bareDir, worktreeName, oldWorktreePath string) string {
+func inferNewWorktreePath(repoRoot, bareDir string, wtMgr *worktree.Manager, worktreeName, oldWorktreePath string) string {
 	// First, try to read the HEAD file to get the branch name
 	headFile := filepath.Join(bareDir, "worktrees", worktreeName, "HEAD")
 	content, err := os.ReadFile(headFile)
@@ -682,7 +716,11 @@ func inferNewWorktreePath(repoRoot, bareDir, worktreeName, oldWorktreePath strin
 		// HEAD content is like "ref: refs/heads/feature/test" or a commit hash
 		if strings.HasPrefix(headContent, "ref: refs/heads/") {
 			branchName := strings.TrimPrefix(headContent, "ref: refs/heads/")
-			newPath := filepath.Join(repoRoot, branchName)
+			newPath := filepath.Join(repoRoot, wtMgr.ExpectedWorktreeDir(branchName))
+			if _, err := os.Stat(newPath); err == nil {
+				return newPath
+			}
+			newPath = filepath.Join(repoRoot, branchName)
 			if _, err := os.Stat(newPath); err == nil {
 				return newPath
 			}
@@ -718,17 +756,25 @@ func inferNewWorktreePath(repoRoot, bareDir, worktreeName, oldWorktreePath strin
 
 // getBranchNameFromWorktree extracts the branch name from a worktree's HEAD file
 func getBranchNameFromWorktree(bareDir, worktreeName string) string {
+	if branch, ok := worktreeHeadBranch(bareDir, worktreeName); ok {
+		return branch
+	}
+	// Detached HEAD or unreadable HEAD, return directory name
+	return worktreeName
+}
+
+// worktreeHeadBranch returns the branch checked out according to a worktree's HEAD file,
+// and false if the HEAD is detached or cannot be read
+func worktreeHeadBranch(bareDir, worktreeName string) (string, bool) {
 	headFile := filepath.Join(bareDir, "worktrees", worktreeName, "HEAD")
 	content, err := os.ReadFile(headFile)
 	if err != nil {
-		return worktreeName // Fallback to directory name
+		return "", false
 	}
 
 	headContent := strings.TrimSpace(string(content))
 	if strings.HasPrefix(headContent, "ref: refs/heads/") {
-		return strings.TrimPrefix(headContent, "ref: refs/heads/")
+		return strings.TrimPrefix(headContent, "ref: refs/heads/"), true
 	}
-
-	// Detached HEAD, return directory name
-	return worktreeName
+	return "", false
 }
@@ -96,8 +96,10 @@ Examples:
   bt go baretree                    # Match by repo name
   bt go amaya382/baretree           # Match by org/repo
   bt go github.com/amaya382/baretree # Match by full path
-  bt go -                           # Go to previous repository`,
-	Args:              cobra.ExactArgs(1),
+  bt go -                           # Go to previous repository
+  bt go -2                          # Go to the repository before that
+  bt go --history                   # List previous repositories`,
+	Args:              repoCdArgs,
 	RunE:              runRepoCd,
 	ValidArgsFunction: completeRepositoryNames(true),
 }
@@ -137,6 +139,8 @@ func init() {
 	GetAliasCmd.Flags().BoolVar(&getShallow, "shallow", false, "Perform a shallow clone")
 	GetAliasCmd.Flags().BoolVarP(&getUpdate, "update", "u", false, "Update existing repository")
 
+	GoAliasCmd.Flags().BoolVar(&repoCdHistory, "history", false, "List previously visited repositories (go back with -N)")
+
 	ReposAliasCmd.Flags().BoolVarP(&listPaths, "paths", "p", false, "Show full paths")
 	ReposAliasCmd.Flags().BoolVarP(&listJSON, "json", "j", false, "Output as JSON")
 }
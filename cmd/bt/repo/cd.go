@@ -10,7 +10,7 @@ import (
 	"github.com/spf13/cobra"
 )
 
-const repoHistoryFile = ".baretree_repo_history"
+var repoCdHistory bool
 
 var cdCmd = &cobra.Command{
 	Use:   "cd <repository>",
@@ -23,42 +23,80 @@ The repository can be specified as:
   - Repository name only: baretree
   - Organization/repository: amaya382/baretree
   - Full path: github.com/amaya382/baretree
-  - - (dash) to go to previous repository
+  - - (dash) to go to previous repository, -N to the N-th previous one
 
 Resolution order (for partial matches):
   1. Exact match on full relative path
   2. Exact match on org/repo
   3. Exact match on repo name
   4. Partial match (contains query)
+  5. Fuzzy match (characters of the query in order, e.g. "bt" for baretree)
+
+If several repositories match, the one visited most often and most recently
+(frecency) is chosen. Disable recording and ranking with:
+  git config --global baretree.frecency false
 
 Examples:
   bt repo cd baretree                    # Match by repo name
   bt repo cd amaya382/baretree           # Match by org/repo
   bt repo cd github.com/amaya382/baretree # Match by full path
-  bt repo cd -                           # Go to previous repository`,
-	Args:              cobra.ExactArgs(1),
+  bt repo cd -                           # Go to previous repository
+  bt repo cd -2                          # Go to the repository before that
+  bt repo cd --history                   # List previous repositories`,
+	Args:              repoCdArgs,
 	RunE:              runRepoCd,
 	ValidArgsFunction: completeRepositoryNames(true),
 }
 
 func init() {
+	cdCmd.Flags().BoolVar(&repoCdHistory, "history", false, "List previously visited repositories (go back with -N)")
 	cdCmd.GroupID = groupCross
 	Cmd.AddCommand(cdCmd)
 }
 
+// repoCdArgs requires a repository argument unless --history is given
+func repoCdArgs(cmd *cobra.Command, args []string) error {
+	if repoCdHistory {
+		return cobra.NoArgs(cmd, args)
+	}
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
 func runRepoCd(cmd *cobra.Command, args []string) error {
+	cwd, _ := os.Getwd()
+
+	history, err := global.RepositoryHistory()
+	if err != nil {
+		return err
+	}
+
+	// Load global config
+	cfg, err := global.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if repoCdHistory {
+		return printRepoHistory(history, cwd, cfg.Roots)
+	}
 	query := args[0]
 
-	// Handle special case: previous directory
-	if query == "-" {
-		prevDir, err := getRepoPreviousDirectory()
+	// Handle special case: previous directories (- or -N)
+	if n, ok := global.ParseHistoryPosition(query); ok {
+		entries, err := history.Entries(cwd)
 		if err != nil {
-			return fmt.Errorf("no previous repository: %w", err)
+			return fmt.Errorf("failed to read directory history: %w", err)
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("no previous repository")
+		}
+		if n > len(entries) {
+			return fmt.Errorf("no previous repository at -%d (history has %d entries)", n, len(entries))
 		}
+		prevDir := entries[n-1]
 
 		// Save current directory before changing
-		cwd, _ := os.Getwd()
-		if err := saveRepoPreviousDirectory(cwd); err != nil {
+		if err := history.Move(cwd, prevDir); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to save directory history: %v\n", err)
 		}
 
@@ -66,12 +104,6 @@ func runRepoCd(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Load global config
-	cfg, err := global.LoadConfig()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
-
 	// Get root directories
 	roots := cfg.Roots
 	if len(roots) == 0 {
@@ -79,7 +111,7 @@ func runRepoCd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Scan for repositories
-	repos, err := global.ScanRepositories(roots)
+	repos, err := global.ListRepositories(roots)
 	if err != nil {
 		return fmt.Errorf("failed to scan repositories: %w", err)
 	}
@@ -88,8 +120,8 @@ func runRepoCd(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no repositories found")
 	}
 
-	// Find matching repository
-	match, ambiguousMatches, err := resolveRepository(repos, query)
+	// Find matching repository; ambiguous and fuzzy queries pick the most frequently used one
+	match, ambiguousMatches, err := global.ResolveRepositoryFuzzy(repos, query, cfg.FrecencyScorer())
 	if err != nil {
 		if len(ambiguousMatches) > 0 {
 			fmt.Fprintf(os.Stderr, "Ambiguous repository name '%s'. Did you mean one of these?\n\n", query)
@@ -103,102 +135,41 @@ func runRepoCd(cmd *cobra.Command, args []string) error {
 	}
 
 	// Save current directory before changing
-	cwd, _ := os.Getwd()
-	if err := saveRepoPreviousDirectory(cwd); err != nil {
+	if err := history.Move(cwd, match.Path); err != nil {
 		fmt.Fprintf(os.Stderr, "Warning: failed to save directory history: %v\n", err)
 	}
 
+	if cfg.Frecency {
+		if err := global.RecordVisit(match.Path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record visit: %v\n", err)
+		}
+	}
+
 	// Output the repository root path (shell function will use this)
 	fmt.Println(match.Path)
 	return nil
 }
 
-// resolveRepository finds a repository matching the query.
-// Resolution order:
-// 1. Exact match on full relative path (github.com/org/repo)
-// 2. Exact match on org/repo
-// 3. Exact match on repo name (returns error if multiple matches)
-// 4. Partial match (returns error if multiple matches)
-// Returns the matching repository, list of ambiguous matches (if any), and error.
-func resolveRepository(repos []global.RepoInfo, query string) (*global.RepoInfo, []global.RepoInfo, error) {
-	query = strings.ToLower(query)
-	queryParts := strings.Split(query, "/")
-
-	// 1. Exact match on full relative path
-	for i := range repos {
-		if strings.ToLower(repos[i].RelativePath) == query {
-			return &repos[i], nil, nil
-		}
+// printRepoHistory lists the previous directories with the -N to go back to each of them,
+// shown relative to their root when under one
+func printRepoHistory(history *global.DirHistory, cwd string, roots []string) error {
+	entries, err := history.Entries(cwd)
+	if err != nil {
+		return fmt.Errorf("failed to read directory history: %w", err)
 	}
-
-	// 2. Exact match on org/repo (last two components)
-	if len(queryParts) == 2 {
-		for i := range repos {
-			relParts := strings.Split(repos[i].RelativePath, string(filepath.Separator))
-			if len(relParts) >= 2 {
-				orgRepo := strings.ToLower(relParts[len(relParts)-2] + "/" + relParts[len(relParts)-1])
-				if orgRepo == query {
-					return &repos[i], nil, nil
-				}
-			}
-		}
+	if len(entries) == 0 {
+		fmt.Println("No repository history.")
+		return nil
 	}
-
-	// 3. Exact match on repo name only - collect all matches
-	if len(queryParts) == 1 {
-		var matches []global.RepoInfo
-		for i := range repos {
-			if strings.ToLower(repos[i].Name) == query {
-				matches = append(matches, repos[i])
+	for i, entry := range entries {
+		display := entry
+		for _, root := range roots {
+			if rel, err := filepath.Rel(root, entry); err == nil && !strings.HasPrefix(rel, "..") {
+				display = rel
+				break
 			}
 		}
-		if len(matches) == 1 {
-			return &matches[0], nil, nil
-		}
-		if len(matches) > 1 {
-			return nil, matches, fmt.Errorf("ambiguous repository name '%s': %d matches found", query, len(matches))
-		}
+		fmt.Printf("%4s  %s\n", fmt.Sprintf("-%d", i+1), display)
 	}
-
-	// 4. Partial match (contains query) - collect all matches
-	var matches []global.RepoInfo
-	for i := range repos {
-		if strings.Contains(strings.ToLower(repos[i].RelativePath), query) ||
-			strings.Contains(strings.ToLower(repos[i].Name), query) {
-			matches = append(matches, repos[i])
-		}
-	}
-	if len(matches) == 1 {
-		return &matches[0], nil, nil
-	}
-	if len(matches) > 1 {
-		return nil, matches, fmt.Errorf("ambiguous repository name '%s': %d matches found", query, len(matches))
-	}
-
-	return nil, nil, fmt.Errorf("repository not found: %s", query)
-}
-
-func getRepoPreviousDirectory() (string, error) {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return "", err
-	}
-
-	historyPath := filepath.Join(homeDir, repoHistoryFile)
-	data, err := os.ReadFile(historyPath)
-	if err != nil {
-		return "", err
-	}
-
-	return strings.TrimSpace(string(data)), nil
-}
-
-func saveRepoPreviousDirectory(dir string) error {
-	homeDir, err := os.UserHomeDir()
-	if err != nil {
-		return err
-	}
-
-	historyPath := filepath.Join(homeDir, repoHistoryFile)
-	return os.WriteFile(historyPath, []byte(dir), 0644)
+	return nil
 }
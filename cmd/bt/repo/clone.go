@@ -7,6 +7,7 @@ import (
 
 	"github.com/amaya382/baretree/internal/config"
 	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/global"
 	"github.com/amaya382/baretree/internal/repository"
 	"github.com/amaya382/baretree/internal/url"
 	"github.com/spf13/cobra"
@@ -104,6 +105,12 @@ func runClone(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to create default worktree: %w", err)
 	}
 
+	updateRepoIndex(absDestination)
+
+	op := global.NewOperation(absDestination)
+	op.Record(global.JournalStep{Action: global.StepCreateRepository, Path: absDestination})
+	op.SaveOrWarn(os.Stdout)
+
 	fmt.Printf("\n✓ Successfully cloned repository\n")
 	fmt.Printf("  Repository root: %s\n", absDestination)
 	fmt.Printf("  Bare repository: %s\n", barePath)
@@ -24,7 +24,7 @@ func completeRepositoryNames(includePrevious bool) func(cmd *cobra.Command, args
 		}
 
 		// Scan for repositories
-		repos, err := global.ScanRepositories(roots)
+		repos, err := global.ListRepositories(roots)
 		if err != nil {
 			return completions, cobra.ShellCompDirectiveNoFileComp
 		}
@@ -32,6 +32,12 @@ func completeRepositoryNames(includePrevious bool) func(cmd *cobra.Command, args
 		// Filter repositories by partial match if toComplete is provided
 		filteredRepos := global.FilterRepositories(repos, toComplete)
 
+		// Offer the most frequently used repositories first and ask the shell to keep that order
+		directive := cobra.ShellCompDirectiveNoFileComp
+		if global.SortRepositoriesByScore(filteredRepos, cfg.FrecencyScorer()) {
+			directive |= cobra.ShellCompDirectiveKeepOrder
+		}
+
 		for _, repo := range filteredRepos {
 			// Add relative path for completion
 			completions = append(completions, repo.RelativePath)
@@ -42,6 +48,6 @@ func completeRepositoryNames(includePrevious bool) func(cmd *cobra.Command, args
 			completions = append(completions, "-")
 		}
 
-		return completions, cobra.ShellCompDirectiveNoFileComp
+		return completions, directive
 	}
 }
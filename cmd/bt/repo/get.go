@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/amaya382/baretree/internal/config"
 	"github.com/amaya382/baretree/internal/git"
@@ -25,19 +26,32 @@ var getCmd = &cobra.Command{
 	Long: `Clone a repository into the baretree root directory with ghq-style path structure.
 
 The repository is cloned into {root}/{host}/{user}/{repo}/ with baretree structure.
+Nested groups are kept in the path and ports are left out of it. Local
+repositories are cloned into {root}/local/{path}/.
 
 Supports various input formats:
   - SSH URL: git@github.com:user/repo.git
+  - SSH URL with port: ssh://git@host:2222/user/repo.git
   - HTTPS URL: https://github.com/user/repo.git
-  - Short path: github.com/user/repo
-  - User/repo: user/repo (uses github.com as default host)
-  - Repo only: repo (uses configured user and github.com)
+  - File URL or local path: file:///srv/git/repo.git, /srv/git/repo.git
+  - Short path: github.com/user/repo (nested groups: gitlab.com/org/team/repo)
+  - User/repo: user/repo (uses the default host)
+  - Repo only: repo (uses the default host and configured user)
+
+Full URLs are cloned as given. For short paths the clone URL is built from
+git-config settings:
+  - baretree.host                   Default host (default: github.com)
+  - baretree.protocol               Clone protocol: ssh or https (default: ssh)
+  - baretree.user                   Default user for "repo only" paths
+  - baretree.host.<host>.protocol   Protocol override for a host
+  - baretree.host.<host>.user       Default user override for a host
 
 Examples:
   bt repo get github.com/amaya382/baretree
   bt repo get git@github.com:amaya382/baretree.git
   bt repo get amaya382/dotfiles
-  bt repo get --branch develop github.com/user/repo`,
+  bt repo get --branch develop github.com/user/repo
+  git config --global baretree.host.gitlab.example.com.protocol https`,
 	Args: cobra.ExactArgs(1),
 	RunE: runGet,
 }
@@ -55,7 +69,8 @@ func runGet(cmd *cobra.Command, args []string) error {
 	}
 
 	// Parse repository path
-	repoPath, err := url.Parse(args[0], "github.com", cfg.User)
+	host := cfg.DefaultHostName()
+	repoPath, err := url.Parse(args[0], host, cfg.UserFor(host))
 	if err != nil {
 		return fmt.Errorf("failed to parse repository: %w", err)
 	}
@@ -71,14 +86,42 @@ func runGet(cmd *cobra.Command, args []string) error {
 		return updateRepository(destination)
 	}
 
-	// Build clone URL
-	cloneURL := buildCloneURL(repoPath)
+	// Keep a full URL as given; build one from the host settings for short paths
+	cloneURL := strings.TrimSpace(args[0])
+	if !url.IsURL(cloneURL) {
+		cloneURL, err = repoPath.CloneURL(cfg.ProtocolFor(repoPath.Host))
+		if err != nil {
+			return err
+		}
+	}
 
 	fmt.Printf("Cloning %s into %s...\n", cloneURL, destination)
 
+	defaultWorktreePath, err := cloneBaretree(cloneURL, destination, getBranch, getShallow)
+	if err != nil {
+		return err
+	}
+
+	op := global.NewOperation(destination)
+	op.Record(global.JournalStep{Action: global.StepCreateRepository, Path: destination})
+	op.SaveOrWarn(os.Stdout)
+
+	fmt.Printf("\n✓ Successfully cloned repository\n")
+	fmt.Printf("  Repository: %s\n", destination)
+	fmt.Printf("  Worktree: %s\n", defaultWorktreePath)
+	fmt.Printf("\nTo navigate to this repository:\n")
+	fmt.Printf("  bt go %s\n", repoPath.String())
+
+	return nil
+}
+
+// cloneBaretree clones cloneURL as a bare repository into destination and sets up the
+// baretree structure with a worktree for the default branch (or branch, if not empty).
+// Returns the path of the default branch worktree.
+func cloneBaretree(cloneURL, destination, branch string, shallow bool) (string, error) {
 	// Create destination directory
 	if err := os.MkdirAll(destination, 0755); err != nil {
-		return fmt.Errorf("failed to create destination directory: %w", err)
+		return "", fmt.Errorf("failed to create destination directory: %w", err)
 	}
 
 	// Clone as bare repository
@@ -86,7 +129,7 @@ func runGet(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Creating bare repository at %s...\n", barePath)
 
 	cloneArgs := []string{"--bare"}
-	if getShallow {
+	if shallow {
 		cloneArgs = append(cloneArgs, "--depth", "1")
 	}
 	cloneArgs = append(cloneArgs, cloneURL, barePath)
@@ -94,19 +137,18 @@ func runGet(cmd *cobra.Command, args []string) error {
 	if err := git.Clone(cloneArgs...); err != nil {
 		// Cleanup on failure
 		os.RemoveAll(destination)
-		return fmt.Errorf("failed to clone repository: %w", err)
+		return "", fmt.Errorf("failed to clone repository: %w", err)
 	}
 
 	// Configure fetch refspec (bare clone does not set this up automatically)
 	if err := git.ConfigureRemoteRefspec(barePath); err != nil {
-		return fmt.Errorf("failed to configure remote refspec: %w", err)
+		return "", fmt.Errorf("failed to configure remote refspec: %w", err)
 	}
 
 	// Determine default branch
-	var defaultBranch string
-	if getBranch != "" {
-		defaultBranch = getBranch
-	} else {
+	defaultBranch := branch
+	if defaultBranch == "" {
+		var err error
 		defaultBranch, err = git.GetDefaultBranch(barePath)
 		if err != nil {
 			fmt.Printf("Warning: failed to detect default branch, using 'main': %v\n", err)
@@ -118,7 +160,7 @@ func runGet(cmd *cobra.Command, args []string) error {
 
 	// Initialize baretree config
 	if err := repository.InitializeBareRepo(destination, defaultBranch); err != nil {
-		return fmt.Errorf("failed to initialize baretree config: %w", err)
+		return "", fmt.Errorf("failed to initialize baretree config: %w", err)
 	}
 
 	// Create default worktree
@@ -127,21 +169,11 @@ func runGet(cmd *cobra.Command, args []string) error {
 
 	executor := git.NewExecutor(barePath)
 	if _, err := executor.Execute("worktree", "add", defaultWorktreePath, defaultBranch); err != nil {
-		return fmt.Errorf("failed to create default worktree: %w", err)
+		return "", fmt.Errorf("failed to create default worktree: %w", err)
 	}
 
-	fmt.Printf("\n✓ Successfully cloned repository\n")
-	fmt.Printf("  Repository: %s\n", destination)
-	fmt.Printf("  Worktree: %s\n", defaultWorktreePath)
-	fmt.Printf("\nTo navigate to this repository:\n")
-	fmt.Printf("  bt go %s\n", repoPath.String())
-
-	return nil
-}
-
-func buildCloneURL(repoPath *url.RepoPath) string {
-	// Use SSH URL format for github.com, gitlab.com, etc.
-	return fmt.Sprintf("git@%s:%s/%s.git", repoPath.Host, repoPath.User, repoPath.Repo)
+	updateRepoIndex(destination)
+	return defaultWorktreePath, nil
 }
 
 func updateRepository(destination string) error {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/amaya382/baretree/internal/config"
 	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/global"
 	"github.com/amaya382/baretree/internal/repository"
 	"github.com/spf13/cobra"
 )
@@ -180,6 +181,16 @@ func runInit(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	updateRepoIndex(absTarget)
+
+	op := global.NewOperation(absTarget)
+	if len(existingFiles) > 0 {
+		op.Record(global.JournalStep{Action: global.StepIrreversible, Note: "converted " + absTarget + " to a baretree repository in place"})
+	} else {
+		op.Record(global.JournalStep{Action: global.StepCreateRepository, Path: absTarget})
+	}
+	op.SaveOrWarn(os.Stdout)
+
 	fmt.Printf("\n✓ Successfully initialized baretree repository\n")
 	fmt.Printf("  Repository root: %s\n", absTarget)
 	fmt.Printf("  Bare repository: %s\n", barePath)
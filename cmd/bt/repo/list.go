@@ -21,6 +21,8 @@ var listCmd = &cobra.Command{
 	Long: `List all baretree repositories under the configured root directory.
 
 By default, shows the relative path (e.g., github.com/user/repo).
+Repositories visited most often and most recently with bt go come first
+(unless baretree.frecency is false).
 Use --paths to show full absolute paths.
 Use --json for JSON output.
 
@@ -46,7 +48,7 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	repos, err := global.ScanRepositories(cfg.Roots)
+	repos, err := global.ListRepositories(cfg.Roots)
 	if err != nil {
 		return fmt.Errorf("failed to scan repositories: %w", err)
 	}
@@ -56,6 +58,9 @@ func runList(cmd *cobra.Command, args []string) error {
 		repos = global.FilterRepositories(repos, args[0])
 	}
 
+	// Most frequently and recently used repositories first
+	global.SortRepositoriesByScore(repos, cfg.FrecencyScorer())
+
 	if listJSON {
 		return outputJSON(repos)
 	}
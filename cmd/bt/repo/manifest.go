@@ -0,0 +1,369 @@
+package repo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/amaya382/baretree/internal/config"
+	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/amaya382/baretree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var (
+	manifestExportFile   string
+	manifestExportFormat string
+	manifestApplyDryRun  bool
+	manifestApplyNoConf  bool
+)
+
+var manifestCmd = &cobra.Command{
+	Use:   "manifest",
+	Short: "Export or restore all repositories under root with a manifest",
+	Long: `Export or restore the whole managed tree with a manifest file.
+
+A manifest lists every repository under the baretree root directories with
+its remote URLs, default branch, worktree branches and baretree configuration
+(post-create actions, sync-to-root entries and hooks).
+
+Subcommands:
+  export    Write a manifest of all repositories (TOML or JSON)
+  apply     Clone missing repositories, create listed worktrees and import configuration
+
+Examples:
+  bt repo manifest export -o baretree-manifest.toml
+  bt repo manifest export --format json > manifest.json
+  bt repo manifest apply baretree-manifest.toml`,
+}
+
+var manifestExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a manifest of all repositories under root",
+	Long: `Export a manifest of all repositories under the baretree root directories.
+
+For every repository the manifest records:
+  - path: Repository path relative to its root (e.g., github.com/user/repo)
+  - default_branch: Configured default branch
+  - remote: Remote names and URLs
+  - worktrees: Branches checked out in worktrees other than the default branch
+  - config: baretree configuration (same as 'bt config export')
+
+The format is TOML by default; JSON is used with --format json or when the
+output file ends in .json. By default, outputs to stdout.
+
+Examples:
+  bt repo manifest export
+  bt repo manifest export -o baretree-manifest.toml
+  bt repo manifest export --format json`,
+	Args: cobra.NoArgs,
+	RunE: runManifestExport,
+}
+
+var manifestApplyCmd = &cobra.Command{
+	Use:   "apply [file]",
+	Short: "Restore repositories, worktrees and configuration from a manifest",
+	Long: `Restore repositories, worktrees and configuration from a manifest.
+
+For every repository in the manifest:
+  1. Clone it into the primary root if it does not exist under any root
+  2. Add remotes that are missing
+  3. Import its baretree configuration (unless --no-config)
+  4. Create worktrees for listed branches that have none yet
+
+Applying the same manifest again only does what is still missing, so it is
+safe to re-run after a failure. Reads from a file or stdin if no file is
+specified; TOML and JSON manifests are both accepted.
+
+Examples:
+  bt repo manifest apply baretree-manifest.toml
+  bt repo manifest apply manifest.json --dry-run
+  cat baretree-manifest.toml | bt repo manifest apply`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runManifestApply,
+}
+
+func init() {
+	manifestExportCmd.Flags().StringVarP(&manifestExportFile, "output", "o", "", "Output file (default: stdout)")
+	manifestExportCmd.Flags().StringVar(&manifestExportFormat, "format", "", "Output format: toml or json (default: from file extension, else toml)")
+	manifestApplyCmd.Flags().BoolVar(&manifestApplyDryRun, "dry-run", false, "Show what would be done without making changes")
+	manifestApplyCmd.Flags().BoolVar(&manifestApplyNoConf, "no-config", false, "Do not import baretree configuration")
+
+	manifestCmd.AddCommand(manifestExportCmd)
+	manifestCmd.AddCommand(manifestApplyCmd)
+	manifestCmd.GroupID = groupCross
+	Cmd.AddCommand(manifestCmd)
+}
+
+func runManifestExport(cmd *cobra.Command, args []string) error {
+	format := manifestExportFormat
+	if format == "" {
+		format = global.ManifestFormatTOML
+		if strings.EqualFold(filepath.Ext(manifestExportFile), ".json") {
+			format = global.ManifestFormatJSON
+		}
+	}
+
+	cfg, err := global.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repos, err := global.ListRepositories(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to scan repositories: %w", err)
+	}
+
+	manifest, errs := global.ExportManifest(repos)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "Warning: skipped %v\n", err)
+	}
+
+	content, err := global.EncodeManifest(manifest, format)
+	if err != nil {
+		return err
+	}
+
+	if manifestExportFile == "" {
+		fmt.Print(content)
+		return nil
+	}
+
+	if err := os.WriteFile(manifestExportFile, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	fmt.Printf("Exported %d repositories to %s\n", len(manifest.Repositories), manifestExportFile)
+	return nil
+}
+
+func runManifestApply(cmd *cobra.Command, args []string) error {
+	// Read input
+	var data []byte
+	var err error
+	if len(args) == 1 {
+		data, err = os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read file: %w", err)
+		}
+	} else {
+		stat, _ := os.Stdin.Stat()
+		if (stat.Mode() & os.ModeCharDevice) != 0 {
+			return fmt.Errorf("no input file specified and stdin is empty")
+		}
+		data, err = io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read stdin: %w", err)
+		}
+	}
+
+	manifest, err := global.DecodeManifest(data)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := global.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repos, err := global.ListRepositories(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to scan repositories: %w", err)
+	}
+	existing := make(map[string]string)
+	for _, r := range repos {
+		existing[filepath.ToSlash(r.RelativePath)] = r.Path
+	}
+
+	if manifestApplyDryRun {
+		fmt.Println("Dry run - no changes will be made")
+	}
+	fmt.Printf("Applying manifest with %d repositories...\n", len(manifest.Repositories))
+
+	op := global.NewOperation("")
+	defer op.SaveOrWarn(os.Stdout)
+
+	failed := 0
+	for _, entry := range manifest.Repositories {
+		fmt.Printf("\n%s\n", entry.Path)
+
+		repoPath, ok := existing[entry.Path]
+		if !ok {
+			repoPath = filepath.Join(cfg.PrimaryRoot(), filepath.FromSlash(entry.Path))
+		}
+
+		if err := applyManifestRepository(op, entry, repoPath, !ok); err != nil {
+			fmt.Printf("  ✗ %v\n", err)
+			failed++
+		}
+	}
+
+	fmt.Println()
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("failed to apply %d of %d repositories", failed, len(manifest.Repositories))
+	}
+	fmt.Println("✓ Manifest applied")
+	return nil
+}
+
+// applyManifestRepository brings a single repository in line with its manifest entry, recording
+// the cloned repository and created worktrees in op
+func applyManifestRepository(op *global.Operation, entry global.ManifestRepository, repoPath string, missing bool) error {
+	cloneRemote := entry.CloneRemote()
+	if missing {
+		if cloneRemote == nil {
+			return fmt.Errorf("repository does not exist and has no remote to clone from")
+		}
+		if manifestApplyDryRun {
+			fmt.Printf("  would clone %s into %s\n", cloneRemote.URL, repoPath)
+			for _, branch := range entry.Worktrees {
+				fmt.Printf("  would create worktree for %s\n", branch)
+			}
+			return nil
+		}
+
+		fmt.Printf("  Cloning %s into %s...\n", cloneRemote.URL, repoPath)
+		if _, err := cloneBaretree(cloneRemote.URL, repoPath, entry.DefaultBranch, false); err != nil {
+			return err
+		}
+		op.Record(global.JournalStep{Action: global.StepCreateRepository, Repository: repoPath, Path: repoPath})
+		// Keep the remote name from the manifest
+		if cloneRemote.Name != "origin" {
+			if _, err := git.NewExecutor(filepath.Join(repoPath, config.BareDir)).Execute("remote", "rename", "origin", cloneRemote.Name); err != nil {
+				return fmt.Errorf("failed to rename remote: %w", err)
+			}
+		}
+		fmt.Printf("  ✓ Cloned\n")
+	}
+
+	changed := missing
+	bareDir := filepath.Join(repoPath, config.BareDir)
+	executor := git.NewExecutor(bareDir)
+
+	// Add missing remotes
+	remotes, err := executor.ListRemotes()
+	if err != nil {
+		return fmt.Errorf("failed to list remotes: %w", err)
+	}
+	currentRemotes := make(map[string]bool)
+	for _, r := range remotes {
+		currentRemotes[r] = true
+	}
+	for _, remote := range entry.Remotes {
+		if currentRemotes[remote.Name] {
+			continue
+		}
+		changed = true
+		if manifestApplyDryRun {
+			fmt.Printf("  would add remote %s (%s)\n", remote.Name, remote.URL)
+			continue
+		}
+		if _, err := executor.Execute("remote", "add", remote.Name, remote.URL); err != nil {
+			return fmt.Errorf("failed to add remote %s: %w", remote.Name, err)
+		}
+		if err := executor.Fetch(remote.Name); err != nil {
+			fmt.Printf("  Warning: failed to fetch %s: %v\n", remote.Name, err)
+		}
+		fmt.Printf("  ✓ Remote added: %s\n", remote.Name)
+	}
+
+	// Import configuration before creating worktrees so that post-create actions apply to them
+	cfg, err := config.LoadConfig(repoPath)
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if entry.Config != nil && !manifestApplyNoConf {
+		imported := *entry.Config
+		if imported.Repository.DefaultBranch == "" {
+			imported.Repository.DefaultBranch = entry.DefaultBranch
+		}
+		if !sameConfig(cfg, &imported) {
+			changed = true
+			if manifestApplyDryRun {
+				fmt.Printf("  would import configuration\n")
+			} else {
+				if err := config.SaveConfig(repoPath, &imported); err != nil {
+					return fmt.Errorf("failed to import configuration: %w", err)
+				}
+				cfg = &imported
+				if !missing {
+					op.Record(global.JournalStep{Action: global.StepIrreversible, Repository: repoPath, Note: "imported configuration into " + repoPath})
+				}
+				fmt.Printf("  ✓ Configuration imported\n")
+			}
+		}
+	}
+
+	// Create missing worktrees
+	wtMgr := worktree.NewManager(repoPath, bareDir, cfg)
+	worktrees, err := wtMgr.List()
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	checkedOut := make(map[string]bool)
+	for _, wt := range worktrees {
+		checkedOut[wt.Branch] = true
+	}
+
+	var failedBranches []string
+	created := false
+	for _, branch := range entry.Worktrees {
+		if checkedOut[branch] {
+			continue
+		}
+		changed = true
+
+		branchInfo, err := wtMgr.ResolveBranch(branch)
+		if err != nil || (!branchInfo.IsLocal && !branchInfo.IsRemote) {
+			failedBranches = append(failedBranches, branch)
+			fmt.Printf("  ✗ Branch '%s' not found locally or on any remote\n", branch)
+			continue
+		}
+
+		if manifestApplyDryRun {
+			fmt.Printf("  would create worktree for %s\n", branch)
+			continue
+		}
+
+		opts := worktree.AddOptions{}
+		if !branchInfo.IsLocal {
+			opts.TrackRef = branchInfo.RemoteRef
+		}
+		branchRef := "refs/heads/" + branchInfo.Name
+		oldSHA := global.ResolveRef(repoPath, branchRef)
+		path, _, err := wtMgr.AddWithOptions(branchInfo.Name, opts, os.Stdout)
+		if err != nil {
+			failedBranches = append(failedBranches, branch)
+			fmt.Printf("  ✗ Failed to create worktree for %s: %v\n", branch, err)
+			continue
+		}
+		op.RecordRef(repoPath, branchRef, oldSHA)
+		op.Record(global.JournalStep{Action: global.StepAddWorktree, Repository: repoPath, Path: path, Branch: branchInfo.Name})
+		created = true
+		fmt.Printf("  ✓ Worktree created: %s\n", path)
+	}
+	if created {
+		if err := wtMgr.RefreshEditorWorkspace(); err != nil {
+			fmt.Printf("  Warning: failed to update editor workspace: %v\n", err)
+		}
+	}
+
+	if len(failedBranches) > 0 {
+		return fmt.Errorf("failed to create worktrees: %s", strings.Join(failedBranches, ", "))
+	}
+	if !changed {
+		fmt.Printf("  ✓ Up to date\n")
+	}
+	return nil
+}
+
+// sameConfig reports whether two configurations are equivalent (compared by their TOML export)
+func sameConfig(a, b *config.Config) bool {
+	exportA, errA := config.ExportConfigToTOML(a)
+	exportB, errB := config.ExportConfigToTOML(b)
+	return errA == nil && errB == nil && exportA == exportB
+}
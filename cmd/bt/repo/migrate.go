@@ -42,6 +42,8 @@ You must specify one of --in-place, --destination, or --to-managed:
 The --to-managed option:
   - Automatically detects the destination path from git remote URL
   - Use --path to manually specify the path (e.g., github.com/user/repo)
+  - Nested groups are kept (gitlab.com/org/team/repo); ports are not part of the path
+  - Local and file:// remotes are placed under local/ (e.g., local/srv/git/repo)
   - Works with both regular Git repositories and existing baretree repositories
   - Existing baretree repositories are moved without re-conversion
 
@@ -156,25 +158,10 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Get existing worktrees before migration
-	srcExecutor := git.NewExecutor(absSource)
-	output, err := srcExecutor.Execute("worktree", "list", "--porcelain")
+	// Get worktrees outside the source directory before migration
+	externalWorktrees, err := findExternalWorktrees(absSource)
 	if err != nil {
-		return fmt.Errorf("failed to list worktrees: %w", err)
-	}
-	existingWorktrees := git.ParseWorktreeList(output)
-
-	// Filter external worktrees (worktrees outside the source directory)
-	var externalWorktrees []git.Worktree
-	for _, wt := range existingWorktrees {
-		if wt.IsBare {
-			continue
-		}
-		// Check if worktree is external (outside absSource)
-		relPath, err := filepath.Rel(absSource, wt.Path)
-		if err != nil || strings.HasPrefix(relPath, "..") {
-			externalWorktrees = append(externalWorktrees, wt)
-		}
+		return err
 	}
 
 	fmt.Printf("Migrating repository: %s\n", absSource)
@@ -191,7 +178,54 @@ func runMigrate(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	return performMigration(absSource, absDestination, currentBranch, migrateInPlace, externalWorktrees)
+	if err := performMigration(absSource, absDestination, currentBranch, migrateInPlace, externalWorktrees); err != nil {
+		return err
+	}
+	updateRepoIndex(absSource, absDestination)
+	recordMigration(absSource, absDestination, migrateInPlace, migrateRemoveSource, len(externalWorktrees) > 0)
+	return nil
+}
+
+// findExternalWorktrees returns the worktrees of the repository at absSource that are outside of it
+func findExternalWorktrees(absSource string) ([]git.Worktree, error) {
+	output, err := git.NewExecutor(absSource).Execute("worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	var externalWorktrees []git.Worktree
+	for _, wt := range git.ParseWorktreeList(output) {
+		if wt.IsBare {
+			continue
+		}
+		// Check if worktree is external (outside absSource)
+		relPath, err := filepath.Rel(absSource, wt.Path)
+		if err != nil || strings.HasPrefix(relPath, "..") {
+			externalWorktrees = append(externalWorktrees, wt)
+		}
+	}
+	return externalWorktrees, nil
+}
+
+// recordMigration records a completed migration in the journal. Only a copy to a new
+// destination can be undone (by moving the destination to the trash); converting in place,
+// moving external worktrees and removing the source cannot.
+func recordMigration(absSource, absDestination string, inPlace, removeSource, movedWorktrees bool) {
+	op := global.NewOperation(absDestination)
+	if inPlace {
+		op.Record(global.JournalStep{Action: global.StepIrreversible, Note: "converted " + absSource + " to a baretree repository in place"})
+		op.SaveOrWarn(os.Stdout)
+		return
+	}
+
+	op.Record(global.JournalStep{Action: global.StepCreateRepository, Path: absDestination})
+	if movedWorktrees {
+		op.Record(global.JournalStep{Action: global.StepIrreversible, Note: "moved external worktrees of " + absSource + " into " + absDestination})
+	}
+	if removeSource {
+		op.Record(global.JournalStep{Action: global.StepIrreversible, Note: "deleted " + absSource})
+	}
+	op.SaveOrWarn(os.Stdout)
 }
 
 func performMigration(absSource, absDestination, currentBranch string, inPlace bool, externalWorktrees []git.Worktree) error {
@@ -763,7 +797,8 @@ func runMigrateToRoot(absSource string) error {
 	var repoPath *url.RepoPath
 	if migrateRepoPath != "" {
 		// Use explicitly provided path
-		repoPath, err = url.Parse(migrateRepoPath, "github.com", cfg.User)
+		host := cfg.DefaultHostName()
+		repoPath, err = url.Parse(migrateRepoPath, host, cfg.UserFor(host))
 		if err != nil {
 			return fmt.Errorf("failed to parse --path: %w", err)
 		}
@@ -793,13 +828,23 @@ func runMigrateToRoot(absSource string) error {
 	fmt.Printf("  Destination: %s\n", absDestination)
 	fmt.Printf("  Repository: %s\n", repoPath.String())
 
+	movedWorktrees := false
 	if isBaretree {
 		// Already a baretree repository - just move it
-		return moveBaretreeRepo(absSource, absDestination, migrateRemoveSource)
+		err = moveBaretreeRepo(absSource, absDestination, migrateRemoveSource)
+	} else {
+		// Regular git repository - migrate and move
+		if externalWorktrees, listErr := findExternalWorktrees(absSource); listErr == nil {
+			movedWorktrees = len(externalWorktrees) > 0
+		}
+		err = migrateToManagedImpl(absSource, absDestination, migrateRemoveSource)
 	}
-
-	// Regular git repository - migrate and move
-	return migrateToManagedImpl(absSource, absDestination, migrateRemoveSource)
+	if err != nil {
+		return err
+	}
+	updateRepoIndex(absSource, absDestination)
+	recordMigration(absSource, absDestination, false, migrateRemoveSource, movedWorktrees)
+	return nil
 }
 
 // findBareDir finds the bare repository directory in a baretree repo
@@ -835,7 +880,13 @@ func detectRepoPathFromRemote(gitPath string) (*url.RepoPath, error) {
 		}
 	}
 
-	return url.ParseRemoteURL(strings.TrimSpace(remoteURL))
+	// Relative local remotes are relative to the repository, not the current directory
+	remoteURL = strings.TrimSpace(remoteURL)
+	if url.IsLocalPath(remoteURL) && !filepath.IsAbs(remoteURL) {
+		remoteURL = filepath.Join(gitPath, remoteURL)
+	}
+
+	return url.ParseRemoteURL(remoteURL)
 }
 
 // isSubPath checks if child is a subpath of parent
@@ -986,24 +1037,10 @@ func migrateToManagedImpl(absSource, absDestination string, removeSource bool) e
 		return fmt.Errorf("failed to get current branch: %w", err)
 	}
 
-	// Get existing worktrees before migration
-	output, err := executor.Execute("worktree", "list", "--porcelain")
+	// Get worktrees outside the source directory before migration
+	externalWorktrees, err := findExternalWorktrees(absSource)
 	if err != nil {
-		return fmt.Errorf("failed to list worktrees: %w", err)
-	}
-	existingWorktrees := git.ParseWorktreeList(output)
-
-	// Filter external worktrees (worktrees outside the source directory)
-	var externalWorktrees []git.Worktree
-	for _, wt := range existingWorktrees {
-		if wt.IsBare {
-			continue
-		}
-		// Check if worktree is external (outside absSource)
-		relPath, err := filepath.Rel(absSource, wt.Path)
-		if err != nil || strings.HasPrefix(relPath, "..") {
-			externalWorktrees = append(externalWorktrees, wt)
-		}
+		return err
 	}
 
 	fmt.Printf("Current branch: %s\n", currentBranch)
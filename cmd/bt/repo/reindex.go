@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/spf13/cobra"
+)
+
+var reindexCmd = &cobra.Command{
+	Use:   "reindex",
+	Short: "Rebuild the repository index used by bt go, bt repos and completion",
+	Long: `Rescan all baretree root directories and rebuild the repository index.
+
+Commands that look up repositories (bt go, bt repos, bt repo remove,
+shell completion, ...) read them from an index in the cache directory
+($XDG_CACHE_HOME/baretree/repos.json) instead of walking every root each time.
+The index is refreshed automatically when directories under a root change and
+updated by bt get, bt init, bt clone, bt migrate and bt repo remove, so this is
+only needed if it ever gets out of date (e.g. on filesystems with coarse
+modification times).
+
+Examples:
+  bt repo reindex`,
+	Args: cobra.NoArgs,
+	RunE: runReindex,
+}
+
+func init() {
+	reindexCmd.GroupID = groupCross
+	Cmd.AddCommand(reindexCmd)
+}
+
+func runReindex(cmd *cobra.Command, args []string) error {
+	cfg, err := global.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repos, err := global.RebuildIndex(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to rebuild repository index: %w", err)
+	}
+
+	indexPath, _ := global.IndexPath()
+	fmt.Printf("✓ Indexed %d repositories (%s)\n", len(repos), indexPath)
+	return nil
+}
+
+// updateRepoIndex records repositories created at or removed from the given paths in the
+// repository index. A failure only makes the next lookup rescan, so it is reported as a warning.
+func updateRepoIndex(paths ...string) {
+	cfg, err := global.LoadConfig()
+	if err != nil {
+		return
+	}
+	for _, path := range paths {
+		if err := global.UpdateIndex(cfg.Roots, path); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to update repository index: %v\n", err)
+		}
+	}
+}
@@ -8,11 +8,15 @@ import (
 	"strings"
 
 	"github.com/amaya382/baretree/internal/global"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
 	"github.com/spf13/cobra"
 )
 
 var (
-	repoRemoveForce bool
+	repoRemoveForce     bool
+	repoRemoveYes       bool
+	repoRemovePermanent bool
 )
 
 var removeCmd = &cobra.Command{
@@ -26,21 +30,36 @@ The repository can be specified as:
   - Organization/repository: amaya382/baretree
   - Full path: github.com/amaya382/baretree
 
-This will permanently delete the entire repository directory including:
+This will remove the entire repository directory including:
   - All worktrees
   - The bare repository (.git)
   - All local branches and history
+  - Shared files (.shared/)
+
+The directory is moved into the trash, from which it can be brought back with
+'bt trash restore'. Use --permanent to delete it right away.
+
+Before the prompt, every worktree is checked for uncommitted changes, untracked
+files and commits missing from every remote, and the repository for stashes,
+unpushed branches without a worktree. If anything would be lost, a report is
+printed and the removal is refused unless --force is given. A repository without
+remotes is removed after a warning as long as nothing else is at risk.
+
+--force only overrides the safety checks; use --yes to skip the prompt.
 
 Examples:
   bt repo remove baretree
-  bt repo rm amaya382/baretree
-  bt repo rm github.com/amaya382/baretree --force`,
+  bt repo rm amaya382/baretree --yes
+  bt repo rm github.com/amaya382/baretree --force
+  bt repo rm baretree --permanent`,
 	Args: cobra.ExactArgs(1),
 	RunE: runRepoRemove,
 }
 
 func init() {
-	removeCmd.Flags().BoolVarP(&repoRemoveForce, "force", "f", false, "Skip confirmation prompt")
+	removeCmd.Flags().BoolVarP(&repoRemoveForce, "force", "f", false, "Remove even if work that exists nowhere else would be lost")
+	removeCmd.Flags().BoolVarP(&repoRemoveYes, "yes", "y", false, "Skip the confirmation prompt")
+	removeCmd.Flags().BoolVar(&repoRemovePermanent, "permanent", false, "Delete the repository instead of moving it to the trash")
 	removeCmd.GroupID = groupCross
 	Cmd.AddCommand(removeCmd)
 }
@@ -61,7 +80,7 @@ func runRepoRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Scan for repositories
-	repos, err := global.ScanRepositories(roots)
+	repos, err := global.ListRepositories(roots)
 	if err != nil {
 		return fmt.Errorf("failed to scan repositories: %w", err)
 	}
@@ -71,7 +90,7 @@ func runRepoRemove(cmd *cobra.Command, args []string) error {
 	}
 
 	// Find matching repository
-	match, ambiguousMatches, err := resolveRepository(repos, query)
+	match, ambiguousMatches, err := global.ResolveRepository(repos, query)
 	if err != nil {
 		if len(ambiguousMatches) > 0 {
 			fmt.Fprintf(os.Stderr, "Ambiguous repository name '%s'. Did you mean one of these?\n\n", query)
@@ -94,9 +113,33 @@ func runRepoRemove(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("cannot remove repository while inside it: %s", match.Path)
 	}
 
-	// Confirm deletion unless --force is specified
-	if !repoRemoveForce {
-		fmt.Printf("This will permanently delete the repository:\n")
+	// Refuse to lose work that exists nowhere else unless forced
+	check, err := checkRepositoryRemoval(match.Path)
+	if err != nil {
+		if !repoRemoveForce {
+			return fmt.Errorf("failed to check repository for unsaved work (use --force to remove anyway): %w", err)
+		}
+		fmt.Printf("Warning: failed to check repository for unsaved work: %v\n", err)
+	} else if check.HasRisks() {
+		if !repoRemoveForce {
+			fmt.Printf("Repository %s has work that exists nowhere else:\n", match.RelativePath)
+			check.WriteReport(os.Stdout)
+			cmd.SilenceUsage = true
+			return fmt.Errorf("refusing to remove repository %s (use --force to remove anyway)", match.RelativePath)
+		}
+		fmt.Printf("Warning: removing repository %s with work that exists nowhere else (--force):\n", match.RelativePath)
+		check.WriteReport(os.Stdout)
+	} else if check.NoRemotes {
+		fmt.Printf("Warning: %s has no remotes: its entire history exists only in this repository\n", match.RelativePath)
+	}
+
+	// Confirm deletion unless --yes is specified
+	if !repoRemoveYes {
+		if repoRemovePermanent {
+			fmt.Printf("This will permanently delete the repository:\n")
+		} else {
+			fmt.Printf("This will move the repository to the trash:\n")
+		}
 		fmt.Printf("  Path: %s\n", match.Path)
 		fmt.Printf("  Name: %s\n\n", match.RelativePath)
 		fmt.Printf("Are you sure? [y/N]: ")
@@ -113,19 +156,48 @@ func runRepoRemove(cmd *cobra.Command, args []string) error {
 
 	fmt.Printf("Removing repository %s...\n", match.RelativePath)
 
-	// Remove the repository directory
-	if err := os.RemoveAll(match.Path); err != nil {
+	// Remove the repository directory, keeping it in the trash unless --permanent
+	var entry *global.TrashEntry
+	if repoRemovePermanent {
+		err = os.RemoveAll(match.Path)
+	} else {
+		entry, err = global.TrashRepository(*match)
+	}
+	if err != nil {
 		return fmt.Errorf("failed to remove repository: %w", err)
 	}
 
+	op := global.NewOperation(match.Path)
+	if entry != nil {
+		op.Record(global.JournalStep{Action: global.StepTrash, Path: match.Path, TrashID: entry.ID})
+	} else {
+		op.Record(global.JournalStep{Action: global.StepIrreversible, Note: "permanently deleted " + match.Path})
+	}
+	op.SaveOrWarn(os.Stdout)
+
 	// Try to clean up empty parent directories
 	cleanupEmptyParents(match.Path, roots)
+	updateRepoIndex(match.Path)
 
-	fmt.Printf("✓ Repository removed: %s\n", match.RelativePath)
+	if entry != nil {
+		fmt.Printf("✓ Repository moved to the trash: %s (restore with 'bt trash restore %s')\n", match.RelativePath, entry.ID)
+	} else {
+		fmt.Printf("✓ Repository removed: %s\n", match.RelativePath)
+	}
 
 	return nil
 }
 
+// checkRepositoryRemoval inspects the repository at repoPath for work that removing it would lose
+func checkRepositoryRemoval(repoPath string) (*worktree.RepositoryRemovalCheck, error) {
+	mgr, err := repository.NewManager(repoPath)
+	if err != nil {
+		return nil, err
+	}
+	wtMgr := worktree.NewManager(repoPath, mgr.BareDir, mgr.Config)
+	return wtMgr.CheckRepositoryRemoval()
+}
+
 // cleanupEmptyParents removes empty parent directories up to (but not including) the root
 func cleanupEmptyParents(path string, roots []string) {
 	parent := filepath.Dir(path)
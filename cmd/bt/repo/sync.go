@@ -0,0 +1,147 @@
+package repo
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncJobs    int
+	syncTimeout time.Duration
+	syncNoPrune bool
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [query]",
+	Short: "Fetch all repositories under root concurrently",
+	Long: `Fetch updates for every baretree repository under the configured root directories.
+
+Repositories are fetched concurrently ('git fetch --all --prune') with a bounded
+number of workers. Each repository has its own timeout, so a single hung remote
+does not block the others. Progress is printed as each repository finishes,
+followed by a summary of successes and failures.
+
+An optional query limits the sync to matching repositories (same matching as
+'bt repo list').
+
+Examples:
+  bt repo sync
+  bt repo sync github.com/my-org
+  bt repo sync --jobs 16 --timeout 2m
+  bt repo sync --no-prune`,
+	Args:              cobra.MaximumNArgs(1),
+	RunE:              runSync,
+	ValidArgsFunction: completeRepositoryNames(false),
+}
+
+func init() {
+	syncCmd.Flags().IntVarP(&syncJobs, "jobs", "j", global.DefaultSyncJobs, "Number of repositories to fetch concurrently")
+	syncCmd.Flags().DurationVar(&syncTimeout, "timeout", global.DefaultSyncTimeout, "Timeout for fetching a single repository (0 for no limit)")
+	syncCmd.Flags().BoolVar(&syncNoPrune, "no-prune", false, "Do not prune deleted remote branches")
+	syncCmd.GroupID = groupCross
+	Cmd.AddCommand(syncCmd)
+}
+
+func runSync(cmd *cobra.Command, args []string) error {
+	if syncJobs <= 0 {
+		return fmt.Errorf("invalid value for --jobs: %d (must be at least 1)", syncJobs)
+	}
+
+	cfg, err := global.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repos, err := global.ListRepositories(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to scan repositories: %w", err)
+	}
+
+	if len(args) > 0 {
+		repos = global.FilterRepositories(repos, args[0])
+	}
+
+	if len(repos) == 0 {
+		fmt.Println("No repositories found")
+		return nil
+	}
+
+	timeoutStr := "none"
+	if syncTimeout > 0 {
+		timeoutStr = syncTimeout.String()
+	}
+	fmt.Printf("Syncing %d repositories (jobs: %d, timeout: %s)...\n", len(repos), syncJobs, timeoutStr)
+
+	// Print progress as each repository finishes
+	done := 0
+	width := len(fmt.Sprint(len(repos)))
+	results := global.SyncRepositories(repos, global.SyncOptions{
+		Jobs:    syncJobs,
+		Timeout: syncTimeout,
+		Prune:   !syncNoPrune,
+	}, func(r global.SyncResult) {
+		done++
+		mark := "✓"
+		if r.Err != nil {
+			mark = "✗"
+		}
+		fmt.Printf("  [%*d/%d] %s %s (%s)\n", width, done, len(repos), mark, r.Repo.RelativePath, formatSyncDuration(r.Duration))
+	})
+
+	// Summary table
+	maxRepoLen := len("REPOSITORY")
+	for _, r := range results {
+		if len(r.Repo.RelativePath) > maxRepoLen {
+			maxRepoLen = len(r.Repo.RelativePath)
+		}
+	}
+
+	var failed int
+	fmt.Println("\nSummary:")
+	fmt.Printf("  %-*s  %-7s  %8s  %s\n", maxRepoLen, "REPOSITORY", "STATUS", "DURATION", "ERROR")
+	for _, r := range results {
+		status := "ok"
+		errStr := ""
+		if r.Err != nil {
+			failed++
+			status = "failed"
+			errStr = summarizeSyncError(r)
+		}
+		line := fmt.Sprintf("  %-*s  %-7s  %8s  %s", maxRepoLen, r.Repo.RelativePath, status, formatSyncDuration(r.Duration), errStr)
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+
+	fmt.Printf("\n%d succeeded, %d failed\n", len(results)-failed, failed)
+
+	if failed > 0 {
+		// Failures are already reported in the summary; the usage text would only add noise
+		cmd.SilenceUsage = true
+		return fmt.Errorf("failed to sync %d repository(ies)", failed)
+	}
+	return nil
+}
+
+// formatSyncDuration formats a duration with one decimal of seconds
+func formatSyncDuration(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// summarizeSyncError returns a single-line description of a sync failure
+func summarizeSyncError(r global.SyncResult) string {
+	if r.TimedOut {
+		return fmt.Sprintf("timed out after %s", syncTimeout)
+	}
+	// Git errors include the stderr output; prefer its "fatal:"/"error:" line
+	lines := strings.Split(strings.TrimSpace(r.Err.Error()), "\n")
+	for _, line := range lines {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "stderr: ")
+		if strings.HasPrefix(line, "fatal: ") || strings.HasPrefix(line, "error: ") {
+			return line
+		}
+	}
+	return lines[0]
+}
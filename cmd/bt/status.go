@@ -1,17 +1,21 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 
+	"github.com/amaya382/baretree/internal/git"
 	"github.com/amaya382/baretree/internal/repository"
 	"github.com/amaya382/baretree/internal/worktree"
 	"github.com/spf13/cobra"
 )
 
+var statusJSON bool
+
 var statusCmd = &cobra.Command{
 	Use:   "status",
 	Short: "Show baretree repository status (worktrees, config, post-create actions)",
@@ -24,11 +28,77 @@ Shows:
   - Warnings for unmanaged worktrees
   - Configured post-create actions
 
-Example:
-  bt status`,
+With --json, the same diagnosis is printed as a JSON document for editor
+plugins and scripts. The schema is documented in docs/status-json.md.
+
+Examples:
+  bt status
+  bt status --json`,
 	RunE: runStatus,
 }
 
+func init() {
+	statusCmd.Flags().BoolVar(&statusJSON, "json", false, "Output as JSON")
+}
+
+// statusSchemaVersion is the version of the 'bt status --json' schema.
+// It is incremented when fields are removed or change meaning; new fields may be added at any time.
+const statusSchemaVersion = 1
+
+// Worktree states in the status report
+const (
+	worktreeStateManaged = "managed"
+	worktreeStateWarning = "warning"
+	worktreeStateBroken  = "broken"
+)
+
+// statusReport is the diagnosis shown by 'bt status' (see docs/status-json.md)
+type statusReport struct {
+	SchemaVersion int                `json:"schema_version"`
+	Repository    statusRepository   `json:"repository"`
+	Worktrees     []statusWorktree   `json:"worktrees"`
+	Orphans       []string           `json:"orphan_branches"` // local branches without a worktree
+	PostCreate    []statusPostCreate `json:"post_create"`
+	SyncToRoot    []statusSyncToRoot `json:"sync_to_root"`
+}
+
+type statusRepository struct {
+	Root                 string `json:"root"`
+	BareDir              string `json:"bare_dir"`
+	DefaultBranch        string `json:"default_branch"`
+	DefaultBranchPath    string `json:"default_branch_path"`
+	DefaultBranchMissing bool   `json:"default_branch_missing"`
+}
+
+type statusWorktree struct {
+	Branch       string   `json:"branch"`
+	Path         string   `json:"path"`
+	RelativePath string   `json:"relative_path"`
+	Head         string   `json:"head,omitempty"`
+	Current      bool     `json:"current"`
+	Default      bool     `json:"default"`
+	Detached     bool     `json:"detached"`
+	State        string   `json:"state"`
+	Issues       []string `json:"issues"` // "outside-root", "nested", "name-mismatch", "broken"
+
+	sortOrder int
+}
+
+type statusPostCreate struct {
+	Type           string   `json:"type"`
+	Source         string   `json:"source"`
+	Managed        bool     `json:"managed"`
+	SourceWorktree string   `json:"source_worktree,omitempty"`
+	Applied        []string `json:"applied"`
+	Missing        []string `json:"missing"`
+}
+
+type statusSyncToRoot struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	State  string `json:"state"` // "ok", "missing-source", "not-applied", "wrong-target", "unknown"
+}
+
 func runStatus(cmd *cobra.Command, args []string) error {
 	// Find repository root
 	cwd, err := os.Getwd()
@@ -55,36 +125,62 @@ func runStatus(cmd *cobra.Command, args []string) error {
 
 	wtMgr := worktree.NewManager(repoRoot, bareDir, mgr.Config)
 
+	report, err := collectStatus(wtMgr, cwd)
+	if err != nil {
+		return err
+	}
+
+	if statusJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+
+	printStatus(report)
+	return nil
+}
+
+// collectStatus diagnoses the repository: worktrees and their issues, branches without
+// worktrees, and the state of post-create actions and sync-to-root entries
+func collectStatus(wtMgr *worktree.Manager, cwd string) (*statusReport, error) {
+	cfg := wtMgr.Config
+	repoRoot := wtMgr.RepoRoot
+
 	// Get all worktrees
 	worktrees, err := wtMgr.List()
 	if err != nil {
-		return fmt.Errorf("failed to list worktrees: %w", err)
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
 	}
 
-	// Print repository information
-	fmt.Println("Repository Information:")
-	fmt.Printf("  Root:          %s\n", repoRoot)
-	fmt.Printf("  Bare repo:     %s\n", bareDir)
-	fmt.Printf("  Default branch: %s\n", mgr.Config.Repository.DefaultBranch)
-	fmt.Println()
+	defaultBranch := cfg.Repository.DefaultBranch
+	if defaultBranch == "" {
+		defaultBranch = "main"
+	}
 
-	// Check if default branch worktree exists
-	defaultBranchPath := filepath.Join(repoRoot, mgr.Config.Repository.DefaultBranch)
-	defaultBranchMissing := false
-	if _, err := os.Stat(defaultBranchPath); os.IsNotExist(err) {
-		defaultBranchMissing = true
+	report := &statusReport{
+		SchemaVersion: statusSchemaVersion,
+		Repository: statusRepository{
+			Root:              repoRoot,
+			BareDir:           wtMgr.BareDir,
+			DefaultBranch:     defaultBranch,
+			DefaultBranchPath: filepath.Join(repoRoot, wtMgr.ExpectedWorktreeDir(defaultBranch)),
+		},
+		Worktrees:  []statusWorktree{},
+		Orphans:    []string{},
+		PostCreate: []statusPostCreate{},
+		SyncToRoot: []statusSyncToRoot{},
 	}
 
-	// Check for broken worktrees (moved to unknown location) before listing
-	brokenWorktrees := detectBrokenWorktrees(bareDir)
-	brokenBranches := make(map[string]brokenWorktree)
-	for _, bw := range brokenWorktrees {
-		brokenBranches[bw.branch] = bw
+	// Check if default branch worktree exists
+	if _, err := os.Stat(report.Repository.DefaultBranchPath); os.IsNotExist(err) {
+		report.Repository.DefaultBranchMissing = true
 	}
 
-	// Print worktrees
-	fmt.Println("Worktrees:")
-	var warningWorktrees []worktreeWarning
+	// Check for broken worktrees (moved to unknown location)
+	brokenBranches := make(map[string]bool)
+	for _, bw := range detectBrokenWorktrees(wtMgr.BareDir) {
+		brokenBranches[bw.branch] = true
+	}
 
 	// Determine which worktree we're currently in
 	cwdAbs, _ := filepath.Abs(cwd)
@@ -97,196 +193,246 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// Track branches that have worktrees
-	worktreeBranches := make(map[string]bool)
-
-	// Collect orphan branches early so they are included in column width calculation
-	var orphanBranches []string
-	localBranches, err := wtMgr.ListLocalBranches()
-	if err == nil {
-		wtBranchSet := make(map[string]bool)
-		for _, wt := range worktrees {
-			if wt.Branch != "" {
-				wtBranchSet[wt.Branch] = true
-			}
-		}
-		for _, branch := range localBranches {
-			if !wtBranchSet[branch] {
-				orphanBranches = append(orphanBranches, branch)
-			}
-		}
-	}
-
-	// Calculate dynamic column widths
-	maxBranchLen := len("BRANCH")
-	maxPathLen := len("PATH")
+	// Sort order: 0: default branch, 1: managed, 2: has warnings
 	for _, wt := range worktrees {
+		wtPathAbs, _ := filepath.Abs(wt.Path)
+		relPath, _ := filepath.Rel(repoRoot, wt.Path)
+
 		branchName := wt.Branch
 		if branchName == "" {
 			branchName = "(detached)"
 		}
-		if len(branchName) > maxBranchLen {
-			maxBranchLen = len(branchName)
+
+		entry := statusWorktree{
+			Branch:       wt.Branch,
+			Path:         wt.Path,
+			RelativePath: relPath,
+			Head:         wt.Head,
+			Current:      isPathWithin(cwdAbs, wtPathAbs),
+			Detached:     wt.Branch == "",
+			Default:      wt.Branch != "" && wt.Branch == defaultBranch,
+			Issues:       []string{},
 		}
-		relPath, _ := filepath.Rel(repoRoot, wt.Path)
-		if len(relPath) > maxPathLen {
-			maxPathLen = len(relPath)
+
+		// Check if this worktree is broken (path doesn't exist)
+		if brokenBranches[branchName] {
+			entry.State = worktreeStateBroken
+			entry.Issues = append(entry.Issues, "broken")
+			entry.sortOrder = 2
+			report.Worktrees = append(report.Worktrees, entry)
+			continue
 		}
-	}
-	for _, branch := range orphanBranches {
-		if len(branch) > maxBranchLen {
-			maxBranchLen = len(branch)
+
+		// Determine detailed status flags
+		if !wtMgr.IsManaged(wt.Path) {
+			entry.Issues = append(entry.Issues, "outside-root")
+		}
+		if wtMgr.IsNestedInWorktree(wt.Path, allWorktreePaths) {
+			entry.Issues = append(entry.Issues, "nested")
+		}
+		if !entry.Detached && relPath != wtMgr.ExpectedWorktreeDir(branchName) {
+			entry.Issues = append(entry.Issues, "name-mismatch")
 		}
-	}
 
-	// Build display rows with sort order:
-	//   0: default branch, 1: managed, 2: has warnings, 3: orphan branches
-	type displayRow struct {
-		sortOrder int
-		prefix    string
-		branch    string
-		path      string
-		status    string
-		symbol    string
-	}
+		entry.State = worktreeStateManaged
+		entry.sortOrder = 1
+		if len(entry.Issues) > 0 {
+			entry.State = worktreeStateWarning
+			entry.sortOrder = 2
+		}
+		if entry.Default {
+			entry.sortOrder = 0 // default branch always first
+		}
 
-	defaultBranch := mgr.Config.Repository.DefaultBranch
-	if defaultBranch == "" {
-		defaultBranch = "main"
+		report.Worktrees = append(report.Worktrees, entry)
 	}
 
-	var rows []displayRow
+	// Stable sort by sortOrder
+	sort.SliceStable(report.Worktrees, func(i, j int) bool {
+		return report.Worktrees[i].sortOrder < report.Worktrees[j].sortOrder
+	})
 
-	for _, wt := range worktrees {
-		prefix := " "
-		wtPathAbs, _ := filepath.Abs(wt.Path)
-		if isPathWithin(cwdAbs, wtPathAbs) {
-			prefix = "@"
-		}
+	report.Orphans = append(report.Orphans, findOrphanBranches(wtMgr, worktrees)...)
 
-		branchName := wt.Branch
-		if branchName == "" {
-			branchName = "(detached)"
+	// Post-create actions with the worktrees they are applied to
+	if len(cfg.PostCreate) > 0 {
+		statuses, err := wtMgr.GetPostCreateStatus()
+		if err != nil {
+			// Fallback to configuration only
+			for _, action := range cfg.PostCreate {
+				report.PostCreate = append(report.PostCreate, statusPostCreate{
+					Type:    action.Type,
+					Source:  action.Source,
+					Managed: action.Managed,
+					Applied: []string{},
+					Missing: []string{},
+				})
+			}
 		} else {
-			worktreeBranches[branchName] = true
+			for _, status := range statuses {
+				entry := statusPostCreate{
+					Type:           status.Type,
+					Source:         status.Source,
+					Managed:        status.Managed,
+					SourceWorktree: status.SourceWorktree,
+					Applied:        status.Applied,
+					Missing:        status.Missing,
+				}
+				if entry.Applied == nil {
+					entry.Applied = []string{}
+				}
+				if entry.Missing == nil {
+					entry.Missing = []string{}
+				}
+				report.PostCreate = append(report.PostCreate, entry)
+			}
 		}
+	}
 
-		relPath, _ := filepath.Rel(repoRoot, wt.Path)
-
-		// Check if this worktree is broken (path doesn't exist)
-		if _, isBroken := brokenBranches[branchName]; isBroken {
-			warningWorktrees = append(warningWorktrees, worktreeWarning{
-				path:    wt.Path,
-				branch:  branchName,
-				reasons: []string{"broken"},
-			})
-			rows = append(rows, displayRow{
-				sortOrder: 2, prefix: prefix, branch: branchName,
-				path: relPath, status: "[Broken]", symbol: " ⚠️",
-			})
-			continue
+	// Sync-to-root entries with their symlink state
+	if len(cfg.SyncToRoot) > 0 {
+		statuses, err := wtMgr.GetSyncToRootStatus()
+		if err != nil {
+			// Fallback to configuration only
+			for _, action := range cfg.SyncToRoot {
+				target := action.Target
+				if target == "" {
+					target = action.Source
+				}
+				report.SyncToRoot = append(report.SyncToRoot, statusSyncToRoot{
+					Source: action.Source,
+					Target: target,
+					State:  "unknown",
+				})
+			}
+		} else {
+			for _, status := range statuses {
+				state := "ok"
+				if !status.SourceExists {
+					state = "missing-source"
+				} else if !status.TargetExists {
+					state = "not-applied"
+				} else if !status.IsCorrect {
+					state = "wrong-target"
+				}
+				report.SyncToRoot = append(report.SyncToRoot, statusSyncToRoot{
+					Source: status.Source,
+					Target: status.Target,
+					State:  state,
+				})
+			}
 		}
+	}
 
-		// Determine detailed status flags
-		var statusParts []string
-		var reasons []string
+	return report, nil
+}
+
+// printStatus prints the status report in human-readable form
+func printStatus(report *statusReport) {
+	repo := report.Repository
 
-		isInRepoRoot := wtMgr.IsManaged(wt.Path)
-		isNested := wtMgr.IsNestedInWorktree(wt.Path, allWorktreePaths)
+	// Print repository information
+	fmt.Println("Repository Information:")
+	fmt.Printf("  Root:          %s\n", repo.Root)
+	fmt.Printf("  Bare repo:     %s\n", repo.BareDir)
+	fmt.Printf("  Default branch: %s\n", repo.DefaultBranch)
+	fmt.Println()
 
-		nameMismatch := branchName != "(detached)" && relPath != branchName
+	// Print worktrees
+	fmt.Println("Worktrees:")
 
-		if !isInRepoRoot {
-			statusParts = append(statusParts, "Outside root")
-			reasons = append(reasons, "outside-root")
+	// Calculate dynamic column widths
+	maxBranchLen := len("BRANCH")
+	maxPathLen := len("PATH")
+	for _, wt := range report.Worktrees {
+		if len(statusBranchName(wt)) > maxBranchLen {
+			maxBranchLen = len(statusBranchName(wt))
 		}
-		if isNested {
-			statusParts = append(statusParts, "Nested")
-			reasons = append(reasons, "nested")
+		if len(wt.RelativePath) > maxPathLen {
+			maxPathLen = len(wt.RelativePath)
 		}
-		if nameMismatch {
-			statusParts = append(statusParts, "Name mismatch")
-			reasons = append(reasons, "name-mismatch")
+	}
+	for _, branch := range report.Orphans {
+		if len(branch) > maxBranchLen {
+			maxBranchLen = len(branch)
 		}
+	}
 
-		status := "[Managed]"
-		statusSymbol := ""
-		order := 1 // managed
-		if len(statusParts) > 0 {
-			status = "[" + strings.Join(statusParts, ", ") + "]"
-			statusSymbol = " ⚠️"
-			order = 2 // has warnings
-			warningWorktrees = append(warningWorktrees, worktreeWarning{
-				path:    wt.Path,
-				branch:  branchName,
-				relPath: relPath,
-				reasons: reasons,
-			})
+	fmt.Printf("     %-*s  %-*s  %s\n", maxBranchLen, "BRANCH", maxPathLen, "PATH", "STATUS")
+	for _, wt := range report.Worktrees {
+		prefix := " "
+		if wt.Current {
+			prefix = "@"
 		}
 
-		if branchName == defaultBranch {
-			order = 0 // default branch always first
+		status := "[Managed]"
+		symbol := ""
+		switch wt.State {
+		case worktreeStateBroken:
+			status = "[Broken]"
+			symbol = " ⚠️"
+		case worktreeStateWarning:
+			var statusParts []string
+			for _, issue := range wt.Issues {
+				switch issue {
+				case "outside-root":
+					statusParts = append(statusParts, "Outside root")
+				case "nested":
+					statusParts = append(statusParts, "Nested")
+				case "name-mismatch":
+					statusParts = append(statusParts, "Name mismatch")
+				}
+			}
+			status = "[" + strings.Join(statusParts, ", ") + "]"
+			symbol = " ⚠️"
 		}
 
-		rows = append(rows, displayRow{
-			sortOrder: order, prefix: prefix, branch: branchName,
-			path: relPath, status: status, symbol: statusSymbol,
-		})
-	}
-
-	// Append orphan branches
-	for _, branch := range orphanBranches {
-		rows = append(rows, displayRow{
-			sortOrder: 3, prefix: " ", branch: branch,
-			path: "-", status: "[No worktree]", symbol: "",
-		})
-	}
-
-	// Stable sort by sortOrder
-	sort.SliceStable(rows, func(i, j int) bool {
-		return rows[i].sortOrder < rows[j].sortOrder
-	})
-
-	// Print header and rows
-	fmt.Printf("     %-*s  %-*s  %s\n", maxBranchLen, "BRANCH", maxPathLen, "PATH", "STATUS")
-	for _, r := range rows {
 		fmt.Printf("  %s %-*s  %-*s  %s%s\n",
-			r.prefix,
-			maxBranchLen, r.branch,
-			maxPathLen, r.path,
-			r.status,
-			r.symbol,
+			prefix,
+			maxBranchLen, statusBranchName(wt),
+			maxPathLen, wt.RelativePath,
+			status,
+			symbol,
 		)
 	}
+	for _, branch := range report.Orphans {
+		fmt.Printf("    %-*s  %-*s  %s\n", maxBranchLen, branch, maxPathLen, "-", "[No worktree]")
+	}
 
 	fmt.Println()
 
 	// Print warnings
-	hasWarnings := len(warningWorktrees) > 0 || len(brokenWorktrees) > 0 || defaultBranchMissing
+	hasWarnings := repo.DefaultBranchMissing
+	for _, wt := range report.Worktrees {
+		if len(wt.Issues) > 0 {
+			hasWarnings = true
+		}
+	}
 	if hasWarnings {
 		fmt.Println("Warnings:")
-		if defaultBranchMissing {
-			fmt.Printf("  - Default branch worktree '%s' does not exist\n", mgr.Config.Repository.DefaultBranch)
-			fmt.Printf("    Expected path: %s\n", defaultBranchPath)
+		if repo.DefaultBranchMissing {
+			fmt.Printf("  - Default branch worktree '%s' does not exist\n", repo.DefaultBranch)
+			fmt.Printf("    Expected path: %s\n", repo.DefaultBranchPath)
 			fmt.Println("    Fix with:")
 			fmt.Println("      - Use 'main' as default: bt config default-branch --unset")
 			fmt.Println("      - Or set your default branch: bt config default-branch <branch>")
 		}
-		for _, ww := range warningWorktrees {
-			for _, reason := range ww.reasons {
-				switch reason {
+		for _, wt := range report.Worktrees {
+			branch := statusBranchName(wt)
+			for _, issue := range wt.Issues {
+				switch issue {
 				case "outside-root":
-					fmt.Printf("  - Worktree '%s' at %s is outside repository root\n", ww.branch, ww.path)
-					fmt.Printf("    Run 'bt repair %s' to move it inside\n", ww.path)
+					fmt.Printf("  - Worktree '%s' at %s is outside repository root\n", branch, wt.Path)
+					fmt.Printf("    Run 'bt repair %s' to move it inside\n", wt.Path)
 				case "nested":
-					fmt.Printf("  - Worktree '%s' at %s is nested inside another worktree\n", ww.branch, ww.relPath)
-					fmt.Printf("    Run 'bt repair %s' to fix it\n", ww.path)
+					fmt.Printf("  - Worktree '%s' at %s is nested inside another worktree\n", branch, wt.RelativePath)
+					fmt.Printf("    Run 'bt repair %s' to fix it\n", wt.Path)
 				case "name-mismatch":
-					fmt.Printf("  - Worktree '%s' path '%s' does not match branch name\n", ww.branch, ww.relPath)
-					fmt.Printf("    Expected path: %s\n", ww.branch)
-					fmt.Printf("    Run 'bt repair %s' to rename it\n", ww.path)
+					fmt.Printf("  - Worktree '%s' path '%s' does not match branch name\n", branch, wt.RelativePath)
+					fmt.Printf("    Expected path: %s\n", branch)
+					fmt.Printf("    Run 'bt repair %s' to rename it\n", wt.Path)
 				case "broken":
-					fmt.Printf("  - Worktree '%s' has broken path (moved or deleted)\n", ww.branch)
+					fmt.Printf("  - Worktree '%s' has broken path (moved or deleted)\n", branch)
 					fmt.Printf("    Run 'bt repair --fix-paths /new/path' to fix it\n")
 				}
 			}
@@ -295,51 +441,33 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	}
 
 	// Print post-create actions configuration with status
-	if len(mgr.Config.PostCreate) > 0 {
+	if len(report.PostCreate) > 0 {
 		fmt.Println("Post-create actions:")
-
-		// Get post-create status
-		statuses, err := wtMgr.GetPostCreateStatus()
-		if err != nil {
-			// Fallback to simple listing
-			for _, action := range mgr.Config.PostCreate {
-				if action.Type == "command" {
-					fmt.Printf("  [command] %s\n", action.Source)
-				} else {
-					modeStr := ""
-					if action.Managed {
-						modeStr = ", managed"
-					}
-					fmt.Printf("  [%s] %s%s\n", action.Type, action.Source, modeStr)
-				}
+		for _, status := range report.PostCreate {
+			if status.Type == "command" {
+				fmt.Printf("  [command] %s\n", status.Source)
+				continue
 			}
-		} else {
-			for _, status := range statuses {
-				if status.Type == "command" {
-					fmt.Printf("  [command] %s\n", status.Source)
-					continue
-				}
 
-				modeStr := ""
-				if status.Managed {
-					modeStr = ", managed"
-				}
-				fmt.Printf("  [%s] %s%s\n", status.Type, status.Source, modeStr)
+			modeStr := ""
+			if status.Managed {
+				modeStr = ", managed"
+			}
+			fmt.Printf("  [%s] %s%s\n", status.Type, status.Source, modeStr)
 
-				// Show source info for non-managed
-				if !status.Managed && status.SourceWorktree != "" {
-					fmt.Printf("    - source: %s\n", status.SourceWorktree)
-				}
+			// Show source info for non-managed
+			if !status.Managed && status.SourceWorktree != "" {
+				fmt.Printf("    - source: %s\n", status.SourceWorktree)
+			}
 
-				// Show applied worktrees
-				if len(status.Applied) > 0 {
-					fmt.Printf("    + applied: %s\n", joinWorktrees(status.Applied))
-				}
+			// Show applied worktrees
+			if len(status.Applied) > 0 {
+				fmt.Printf("    + applied: %s\n", joinWorktrees(status.Applied))
+			}
 
-				// Show missing worktrees
-				if len(status.Missing) > 0 {
-					fmt.Printf("    x missing: %s\n", joinWorktrees(status.Missing))
-				}
+			// Show missing worktrees
+			if len(status.Missing) > 0 {
+				fmt.Printf("    x missing: %s\n", joinWorktrees(status.Missing))
 			}
 		}
 	} else {
@@ -350,44 +478,33 @@ func runStatus(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Print sync-to-root configuration
-	if len(mgr.Config.SyncToRoot) > 0 {
+	if len(report.SyncToRoot) > 0 {
 		fmt.Println("Sync-to-root entries:")
-
-		// Get sync-to-root status
-		statuses, err := wtMgr.GetSyncToRootStatus()
-		if err != nil {
-			// Fallback to simple listing
-			for _, action := range mgr.Config.SyncToRoot {
-				target := action.Target
-				if target == "" {
-					target = action.Source
-				}
-				fmt.Printf("  %s -> %s\n", target, action.Source)
+		for _, status := range report.SyncToRoot {
+			if status.State == "unknown" {
+				fmt.Printf("  %s -> %s\n", status.Target, status.Source)
+				continue
 			}
-		} else {
-			for _, status := range statuses {
-				stateStr := "[OK]"
-				if !status.SourceExists {
-					stateStr = "[MISSING SOURCE]"
-				} else if !status.TargetExists {
-					stateStr = "[NOT APPLIED]"
-				} else if !status.IsCorrect {
-					stateStr = "[WRONG TARGET]"
-				}
 
-				if status.Source == status.Target {
-					fmt.Printf("  %-16s %s\n", stateStr, status.Source)
-				} else {
-					fmt.Printf("  %-16s %s -> %s\n", stateStr, status.Target, status.Source)
-				}
+			stateStr := "[" + strings.ToUpper(strings.ReplaceAll(status.State, "-", " ")) + "]"
+			if status.Source == status.Target {
+				fmt.Printf("  %-16s %s\n", stateStr, status.Source)
+			} else {
+				fmt.Printf("  %-16s %s -> %s\n", stateStr, status.Target, status.Source)
 			}
 		}
 	} else {
 		fmt.Println("No sync-to-root entries configured.")
 		fmt.Println("  Use 'bt sync-to-root add <file>' to sync files to repository root.")
 	}
+}
 
-	return nil
+// statusBranchName returns the branch name for display
+func statusBranchName(wt statusWorktree) string {
+	if wt.Detached {
+		return "(detached)"
+	}
+	return wt.Branch
 }
 
 // joinWorktrees joins worktree names with commas
@@ -402,12 +519,27 @@ func joinWorktrees(names []string) string {
 	return result
 }
 
-// worktreeWarning holds details about a worktree issue for the warnings section
-type worktreeWarning struct {
-	path    string
-	branch  string
-	relPath string
-	reasons []string // "outside-root", "nested", "name-mismatch", "broken"
+// findOrphanBranches returns local branches that are not checked out in any worktree
+func findOrphanBranches(wtMgr *worktree.Manager, worktrees []git.Worktree) []string {
+	localBranches, err := wtMgr.ListLocalBranches()
+	if err != nil {
+		return nil
+	}
+
+	wtBranchSet := make(map[string]bool)
+	for _, wt := range worktrees {
+		if wt.Branch != "" {
+			wtBranchSet[wt.Branch] = true
+		}
+	}
+
+	var orphanBranches []string
+	for _, branch := range localBranches {
+		if !wtBranchSet[branch] {
+			orphanBranches = append(orphanBranches, branch)
+		}
+	}
+	return orphanBranches
 }
 
 // isPathWithin checks if childPath is within parentPath
@@ -455,11 +587,12 @@ func detectBrokenWorktrees(bareDir string) []brokenWorktree {
 
 		// Check if the worktree path still exists
 		if _, err := os.Stat(oldWorktreePath); os.IsNotExist(err) {
-			branchName := getBranchNameFromWorktree(bareDir, entry.Name())
+			_, onBranch := worktreeHeadBranch(bareDir, entry.Name())
 			broken = append(broken, brokenWorktree{
-				name:    entry.Name(),
-				branch:  branchName,
-				oldPath: oldWorktreePath,
+				name:     entry.Name(),
+				branch:   getBranchNameFromWorktree(bareDir, entry.Name()),
+				oldPath:  oldWorktreePath,
+				detached: !onBranch,
 			})
 		}
 	}
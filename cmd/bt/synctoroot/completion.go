@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
 	"github.com/spf13/cobra"
 )
 
@@ -41,7 +42,11 @@ func completeSourceFiles(cmd *cobra.Command, args []string, toComplete string) (
 	}
 
 	// Get default branch worktree path
-	mainWorktree := filepath.Join(repoRoot, defaultBranch)
+	dir, err := worktree.WorktreeDir(mgr.Config.Repository.WorktreeTemplate, defaultBranch)
+	if err != nil {
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+	mainWorktree := filepath.Join(repoRoot, filepath.FromSlash(dir))
 	if _, err := os.Stat(mainWorktree); os.IsNotExist(err) {
 		return completions, cobra.ShellCompDirectiveNoFileComp
 	}
@@ -0,0 +1,70 @@
+package trash
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/spf13/cobra"
+)
+
+var emptyOlderThan string
+
+var emptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "Permanently delete trash entries",
+	Long: `Permanently delete trash entries, or only those removed longer ago than
+--older-than (e.g. 14d, 12h).
+
+Examples:
+  bt trash empty
+  bt trash empty --older-than 14d`,
+	Args: cobra.NoArgs,
+	RunE: runEmpty,
+}
+
+func init() {
+	emptyCmd.Flags().StringVar(&emptyOlderThan, "older-than", "", "Only delete entries removed longer ago than this (e.g. 14d, 12h)")
+}
+
+func runEmpty(cmd *cobra.Command, args []string) error {
+	var olderThan time.Duration
+	if emptyOlderThan != "" {
+		d, err := global.ParseTrashAge(emptyOlderThan)
+		if err != nil {
+			return err
+		}
+		olderThan = d
+	}
+
+	entries, err := global.ListTrash()
+	if err != nil {
+		return err
+	}
+
+	op := global.NewOperation("")
+	defer op.SaveOrWarn(os.Stdout)
+
+	now := time.Now()
+	deleted, failed := 0, 0
+	for i := range entries {
+		if now.Sub(entries[i].DeletedAt) < olderThan {
+			continue
+		}
+		if err := global.DeleteTrashEntry(&entries[i]); err != nil {
+			fmt.Printf("✗ %v\n", err)
+			failed++
+			continue
+		}
+		op.Record(global.JournalStep{Action: global.StepIrreversible, Note: fmt.Sprintf("permanently deleted %s from the trash (%s)", entries[i].OriginalPath, entries[i].ID)})
+		deleted++
+	}
+
+	fmt.Printf("✓ Deleted %d item(s) from the trash\n", deleted)
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("failed to delete %d item(s)", failed)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package trash
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Move a trash entry back to where it was",
+	Long: `Move a worktree or repository from the trash back to where it was.
+
+The entry can be given by its ID, by its name (the most recently removed entry
+with that name is restored) or by a unique prefix of its ID.
+
+A worktree is registered with git again on its branch, which is recreated if
+it was deleted, and its staged changes and metadata (such as the port of its
+templates) are restored. Managed .shared files it used that have been removed
+since are put back. Post-create files (symlinks, copies, directories) that are
+missing are applied again; commands are not run. The repository the worktree belongs to must still exist.
+
+Examples:
+  bt trash restore feature/auth
+  bt trash restore 20240102-150405-feature-auth`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runRestore,
+	ValidArgsFunction: completeEntries,
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	entry, err := global.FindTrashEntry(args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Restoring %s %s to %s...\n", entry.Kind, entry.Name, entry.OriginalPath)
+	var op *global.Operation
+	var branchRef, oldSHA string
+	if entry.Kind == global.TrashKindRepository {
+		op = global.NewOperation(entry.OriginalPath)
+	} else {
+		op = global.NewOperation(entry.RepositoryPath)
+		if entry.Branch != "" {
+			branchRef = "refs/heads/" + entry.Branch
+			oldSHA = global.ResolveRef(entry.RepositoryPath, branchRef)
+		}
+	}
+	if err := global.RestoreTrashEntry(entry, os.Stdout); err != nil {
+		return err
+	}
+
+	// Undoing a restore removes the restored worktree or repository again
+	if entry.Kind == global.TrashKindRepository {
+		op.Record(global.JournalStep{Action: global.StepCreateRepository, Path: entry.OriginalPath})
+		if cfg, err := global.LoadConfig(); err == nil {
+			_ = global.UpdateIndex(cfg.Roots, entry.OriginalPath)
+		}
+	} else {
+		if branchRef != "" {
+			op.RecordRef(entry.RepositoryPath, branchRef, oldSHA)
+		}
+		op.Record(global.JournalStep{Action: global.StepAddWorktree, Path: entry.OriginalPath, Branch: entry.Branch})
+	}
+	op.SaveOrWarn(os.Stdout)
+
+	if entry.Kind != global.TrashKindRepository {
+		if mgr, err := repository.NewManager(entry.RepositoryPath); err == nil {
+			wtMgr := worktree.NewManager(entry.RepositoryPath, mgr.BareDir, mgr.Config)
+			if err := wtMgr.RefreshEditorWorkspace(); err != nil {
+				fmt.Printf("Warning: failed to update editor workspace: %v\n", err)
+			}
+		}
+	}
+
+	fmt.Printf("✓ Restored %s %s\n", entry.Kind, entry.Name)
+	return nil
+}
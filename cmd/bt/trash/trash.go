@@ -0,0 +1,104 @@
+package trash
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for managing removed worktrees and repositories
+var Cmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Restore removed worktrees and repositories (list, restore, empty)",
+	Long: `Restore removed worktrees and repositories.
+
+'bt rm' and 'bt repo rm' move what they remove into the trash instead of
+deleting it (unless --permanent is given). A trashed worktree keeps all its
+files, including uncommitted and untracked ones, its staged changes and its
+commit, even if its branch is deleted afterwards. A trashed repository keeps
+everything, including .shared/.
+
+The trash is kept in $XDG_STATE_HOME/baretree/trash (~/.local/state/baretree/trash).
+
+Subcommands:
+  list       List trash entries
+  restore    Move an entry back to where it was
+  empty      Permanently delete entries
+
+Examples:
+  bt trash list
+  bt trash restore feature/auth
+  bt trash empty --older-than 14d`,
+}
+
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List trash entries",
+	Args:    cobra.NoArgs,
+	RunE:    runList,
+}
+
+func init() {
+	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(restoreCmd)
+	Cmd.AddCommand(emptyCmd)
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	entries, err := global.ListTrash()
+	if err != nil {
+		return err
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("Trash is empty")
+		return nil
+	}
+
+	now := time.Now()
+	maxIDLen, maxNameLen := len("ID"), len("NAME")
+	for _, e := range entries {
+		maxIDLen = max(maxIDLen, len(e.ID))
+		maxNameLen = max(maxNameLen, len(e.Name))
+	}
+
+	fmt.Printf("%-*s  %-10s  %-*s  %-10s  %s\n", maxIDLen, "ID", "KIND", maxNameLen, "NAME", "DELETED", "PATH")
+	for _, e := range entries {
+		fmt.Printf("%-*s  %-10s  %-*s  %-10s  %s\n", maxIDLen, e.ID, e.Kind, maxNameLen, e.Name, formatAge(e.DeletedAt, now), e.OriginalPath)
+	}
+	return nil
+}
+
+// formatAge formats how long ago t was, e.g. "3d ago"
+func formatAge(t, now time.Time) string {
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
+// completeEntries completes trash entry IDs
+func completeEntries(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	entries, err := global.ListTrash()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []string
+	for _, e := range entries {
+		completions = append(completions, e.ID+"\t"+e.Kind+" "+e.Name)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
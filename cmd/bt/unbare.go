@@ -8,7 +8,9 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/amaya382/baretree/internal/config"
 	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/global"
 	"github.com/amaya382/baretree/internal/repository"
 	"github.com/amaya382/baretree/internal/worktree"
 	"github.com/spf13/cobra"
@@ -28,6 +30,9 @@ The worktree can be specified as:
   - Directory name relative to repo root
   - @ for the default branch worktree
 
+Configured post-checkout hooks run in the new repository after conversion.
+See 'bt hooks --help'.
+
 Examples:
   bt unbare feature/auth ~/repos/auth-feature
   bt unbare @ ~/repos/main-copy
@@ -190,6 +195,16 @@ func runUnbare(cmd *cobra.Command, args []string) error {
 	fmt.Printf("  Repository: %s\n", absDestination)
 	fmt.Printf("  Branch: %s\n", branchName)
 
+	op := global.NewOperation(repoRoot)
+	op.Record(global.JournalStep{Action: global.StepCreateRepository, Path: absDestination})
+	op.SaveOrWarn(os.Stdout)
+
+	// Run post-checkout hooks in the new standalone repository
+	_, _ = wtMgr.RunHooks(config.HookPostCheckout, worktree.HookContext{
+		WorktreePath: absDestination,
+		Branch:       branchName,
+	}, os.Stdout)
+
 	return nil
 }
 
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/spf13/cobra"
+)
+
+var undoDryRun bool
+
+var undoCmd = &cobra.Command{
+	Use:   "undo [id]",
+	Short: "Revert the last operation recorded in the journal",
+	Long: `Revert the last operation recorded in the journal (see 'bt log').
+
+Inside a repository, the last operation in that repository is reverted;
+elsewhere, the last operation overall. An older operation can be given by its
+ID from 'bt log'.
+
+Operations are reverted step by step, in reverse order:
+  - Added worktrees are removed (only if they have no uncommitted changes or
+    untracked files) and created branches are deleted
+  - Renamed branches and moved worktrees are renamed and moved back
+  - Removed worktrees and repositories are restored from the trash; worktrees
+    removed with --permanent or by 'bt prune' are re-created from their last commit
+  - Cloned, initialized or restored repositories are moved to the trash
+  - Deleted or updated branches are reset to their old commit
+
+Nothing is changed unless every step can be reverted: if a branch has moved
+on or a path has been reused since, undo refuses. Post-create commands and
+hooks run by the operation are not reverted, remotes added by 'bt repo manifest
+apply' are kept, and undo itself is not recorded.
+
+Examples:
+  bt undo              # Revert the last operation
+  bt undo --dry-run    # Show what would be reverted
+  bt undo 12           # Revert operation #12`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUndo,
+}
+
+func init() {
+	undoCmd.Flags().BoolVar(&undoDryRun, "dry-run", false, "Show what would be reverted without executing")
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	entry, err := findUndoEntry(args)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		fmt.Println("Nothing to undo")
+		return nil
+	}
+
+	fmt.Printf("Undoing #%d: %s (%s)\n", entry.ID, entry.Command, formatAge(entry.Time, time.Now()))
+	if undoDryRun {
+		for i := len(entry.Steps) - 1; i >= 0; i-- {
+			fmt.Printf("  Would revert: %s\n", entry.Steps[i].Describe())
+		}
+		fmt.Println("Dry run - no changes made")
+		return nil
+	}
+
+	if err := global.UndoJournalEntry(entry, os.Stdout); err != nil {
+		cmd.SilenceUsage = true
+		return err
+	}
+
+	fmt.Printf("✓ Undone #%d\n", entry.ID)
+	return nil
+}
+
+// findUndoEntry returns the journal entry with the ID given in args, or the last entry that has
+// not been undone (of the current repository, if any); nil if there is none
+func findUndoEntry(args []string) (*global.JournalEntry, error) {
+	if len(args) == 1 {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid operation ID: %s", args[0])
+		}
+		entries, err := global.ListJournal("")
+		if err != nil {
+			return nil, err
+		}
+		for i := range entries {
+			if entries[i].ID == id {
+				return &entries[i], nil
+			}
+		}
+		return nil, fmt.Errorf("operation not found: %d", id)
+	}
+
+	entries, err := global.ListJournal(currentRepoRoot())
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].UndoneAt == nil {
+			return &entries[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// currentRepoRoot returns the root of the repository containing the current directory,
+// or "" outside a repository
+func currentRepoRoot() string {
+	cwd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	repoRoot, err := repository.FindRoot(cwd)
+	if err != nil {
+		return ""
+	}
+	return repoRoot
+}
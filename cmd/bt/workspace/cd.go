@@ -0,0 +1,113 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/spf13/cobra"
+)
+
+var cdCmd = &cobra.Command{
+	Use:   "cd <workspace> [repository]",
+	Short: "Output the path of a workspace worktree",
+	Long: `Output the path of the worktree of a workspace in one of its repositories,
+for use with shell integration (see 'bt shell-init').
+
+The repository is matched among the workspace's repositories like for 'bt go'.
+Without it, the worktree in the current repository is chosen if you are in
+one of the workspace's repositories, and the first repository's otherwise.
+
+Examples:
+  bt workspace cd feature/x          # Same repository, or the first one
+  bt workspace cd feature/x web      # The web repository's worktree`,
+	Args:              cobra.RangeArgs(1, 2),
+	RunE:              runCd,
+	ValidArgsFunction: completeWorkspaceMembers,
+}
+
+func runCd(cmd *cobra.Command, args []string) error {
+	ws, err := global.GetWorkspace(args[0])
+	if err != nil {
+		return err
+	}
+	if len(ws.Members) == 0 {
+		return fmt.Errorf("workspace '%s' has no repositories", ws.Name)
+	}
+
+	var member *global.WorkspaceMember
+	if len(args) == 2 {
+		member, err = resolveWorkspaceMember(ws, args[1])
+		if err != nil {
+			return err
+		}
+	} else {
+		member = &ws.Members[0]
+		cwd, _ := os.Getwd()
+		for i, m := range ws.Members {
+			if cwd == m.RepositoryPath || strings.HasPrefix(cwd, m.RepositoryPath+string(filepath.Separator)) {
+				member = &ws.Members[i]
+				break
+			}
+		}
+	}
+
+	if info, err := os.Stat(member.WorktreePath); err != nil || !info.IsDir() {
+		return fmt.Errorf("worktree of workspace '%s' in %s no longer exists: %s\nRun 'bt workspace status %s' for details", ws.Name, member.Repository, member.WorktreePath, ws.Name)
+	}
+
+	// Output the worktree path (shell function will use this)
+	fmt.Println(member.WorktreePath)
+	return nil
+}
+
+// resolveWorkspaceMember finds the member of a workspace matching a repository query,
+// using the same matching as 'bt go' among the workspace's repositories
+func resolveWorkspaceMember(ws *global.Workspace, query string) (*global.WorkspaceMember, error) {
+	repos := workspaceRepoInfos(ws)
+	match, _, err := global.ResolveRepository(repos, query)
+	if err != nil {
+		return nil, fmt.Errorf("%w in workspace '%s'", err, ws.Name)
+	}
+	for i := range ws.Members {
+		if ws.Members[i].RepositoryPath == match.Path {
+			return &ws.Members[i], nil
+		}
+	}
+	return nil, fmt.Errorf("repository not found in workspace '%s': %s", ws.Name, query)
+}
+
+// workspaceRepoInfos returns the repositories of a workspace in the form used by resolveRepository
+func workspaceRepoInfos(ws *global.Workspace) []global.RepoInfo {
+	repos := make([]global.RepoInfo, len(ws.Members))
+	for i, m := range ws.Members {
+		repos[i] = global.RepoInfo{
+			Path:         m.RepositoryPath,
+			RelativePath: filepath.FromSlash(m.Repository),
+			Name:         filepath.Base(m.RepositoryPath),
+		}
+	}
+	return repos
+}
+
+// completeWorkspaceMembers completes workspace names, then the repositories of the workspace
+func completeWorkspaceMembers(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return completeWorkspaceNames(cmd, args, toComplete)
+	}
+
+	completions := []string{}
+	if len(args) > 1 {
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+	ws, err := global.GetWorkspace(args[0])
+	if err != nil {
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+	for _, repo := range global.FilterRepositories(workspaceRepoInfos(ws), toComplete) {
+		completions = append(completions, filepath.ToSlash(repo.RelativePath))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
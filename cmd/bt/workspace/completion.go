@@ -0,0 +1,33 @@
+package workspace
+
+import (
+	"path/filepath"
+
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/spf13/cobra"
+)
+
+// completeRepositoryNames returns the repositories under the baretree root for shell
+// completion, most frequently used first
+func completeRepositoryNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	completions := []string{}
+
+	cfg, err := global.LoadConfig()
+	if err != nil || len(cfg.Roots) == 0 {
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+	repos, err := global.ListRepositories(cfg.Roots)
+	if err != nil {
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	filteredRepos := global.FilterRepositories(repos, toComplete)
+	directive := cobra.ShellCompDirectiveNoFileComp
+	if global.SortRepositoriesByScore(filteredRepos, cfg.FrecencyScorer()) {
+		directive |= cobra.ShellCompDirectiveKeepOrder
+	}
+	for _, repo := range filteredRepos {
+		completions = append(completions, filepath.ToSlash(repo.RelativePath))
+	}
+	return completions, directive
+}
@@ -0,0 +1,275 @@
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var (
+	createRepos   []string
+	createBase    string
+	createNoFetch bool
+)
+
+var createCmd = &cobra.Command{
+	Use:   "create <branch> --repos <repo>,<repo>,...",
+	Short: "Create a worktree for a branch in several repositories",
+	Long: `Create a worktree for a branch in each of several repositories.
+
+Repositories are given like for 'bt go' (name, org/name or full path). In each
+repository, the branch is checked out if it exists locally or on a remote, and
+created otherwise (from --base if given). A worktree that already exists for
+the branch becomes part of the workspace as it is.
+
+If the worktree cannot be created in one of the repositories, the worktrees and
+branches already created for the workspace are removed again.
+
+Examples:
+  bt workspace create feature/x --repos api,web,proto
+  bt workspace create feature/x --repos my-org/api,my-org/web --base develop
+  bt workspace create feature/x --repos api,web --no-fetch`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCreate,
+}
+
+func init() {
+	createCmd.Flags().StringSliceVar(&createRepos, "repos", nil, "Repositories to create the worktree in (comma-separated)")
+	createCmd.Flags().StringVar(&createBase, "base", "", "Base branch for branches that do not exist yet")
+	createCmd.Flags().BoolVar(&createNoFetch, "no-fetch", false, "Skip fetching remotes before creating the worktrees")
+	_ = createCmd.MarkFlagRequired("repos")
+	_ = createCmd.RegisterFlagCompletionFunc("repos", completeRepositoryNames)
+}
+
+// workspaceAddition is a worktree added to a workspace, with what has to be undone on rollback
+type workspaceAddition struct {
+	member          global.WorkspaceMember
+	branch          string
+	wtMgr           *worktree.Manager
+	createdWorktree bool
+	createdBranch   bool
+	// oldSHA is the commit the branch pointed to before (empty if it did not exist)
+	oldSHA string
+}
+
+func runCreate(cmd *cobra.Command, args []string) error {
+	name := args[0]
+
+	workspaces, err := global.ListWorkspaces()
+	if err != nil {
+		return err
+	}
+	for _, ws := range workspaces {
+		if ws.Name == name {
+			return fmt.Errorf("workspace '%s' already exists\nUse 'bt workspace status %s' to inspect it", name, name)
+		}
+	}
+
+	cfg, err := global.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	repos, err := global.ListRepositories(cfg.Roots)
+	if err != nil {
+		return fmt.Errorf("failed to scan repositories: %w", err)
+	}
+
+	// Resolve all repositories before touching any of them
+	var members []global.RepoInfo
+	seen := make(map[string]bool)
+	for _, query := range createRepos {
+		query = strings.TrimSpace(query)
+		if query == "" {
+			continue
+		}
+		match, ambiguousMatches, err := global.ResolveRepository(repos, query)
+		if err != nil {
+			if len(ambiguousMatches) > 0 {
+				fmt.Fprintf(os.Stderr, "Ambiguous repository name '%s'. Did you mean one of these?\n\n", query)
+				for _, repo := range ambiguousMatches {
+					fmt.Fprintf(os.Stderr, "  %s\n", repo.RelativePath)
+				}
+				fmt.Fprintln(os.Stderr)
+			}
+			return err
+		}
+		if seen[match.Path] {
+			return fmt.Errorf("repository '%s' is listed more than once", match.RelativePath)
+		}
+		seen[match.Path] = true
+		members = append(members, *match)
+	}
+	if len(members) == 0 {
+		return fmt.Errorf("no repositories specified")
+	}
+
+	fmt.Printf("Creating workspace '%s' in %d repositories...\n", name, len(members))
+
+	var additions []workspaceAddition
+	for _, repo := range members {
+		fmt.Printf("\n[%s]\n", filepath.ToSlash(repo.RelativePath))
+		addition, err := addWorkspaceWorktree(repo, name)
+		if err != nil {
+			fmt.Printf("✗ %v\n", err)
+			rollbackWorkspace(additions)
+			cmd.SilenceUsage = true
+			return fmt.Errorf("failed to create workspace '%s': %s: %w", name, filepath.ToSlash(repo.RelativePath), err)
+		}
+		additions = append(additions, *addition)
+	}
+
+	ws := global.Workspace{Name: name, CreatedAt: time.Now()}
+	for _, a := range additions {
+		ws.Members = append(ws.Members, a.member)
+	}
+	if err := global.SaveWorkspace(ws); err != nil {
+		rollbackWorkspace(additions)
+		return fmt.Errorf("failed to save workspace: %w", err)
+	}
+
+	op := global.NewOperation("")
+	for _, a := range additions {
+		if !a.createdWorktree {
+			continue
+		}
+		op.RecordRef(a.member.RepositoryPath, "refs/heads/"+a.branch, a.oldSHA)
+		op.Record(global.JournalStep{Action: global.StepAddWorktree, Repository: a.member.RepositoryPath, Path: a.member.WorktreePath, Branch: a.branch})
+	}
+	op.SaveOrWarn(os.Stdout)
+
+	for _, a := range additions {
+		if !a.createdWorktree {
+			continue
+		}
+		if err := a.wtMgr.RefreshEditorWorkspace(); err != nil {
+			fmt.Printf("Warning: %s: failed to update editor workspace: %v\n", a.member.Repository, err)
+		}
+	}
+
+	fmt.Printf("\n✓ Workspace '%s' created\n", name)
+	for _, m := range ws.Members {
+		fmt.Printf("  %s: %s\n", m.Repository, m.WorktreePath)
+	}
+	fmt.Printf("\nNext steps:\n")
+	fmt.Printf("  bt workspace cd %s <repository>\n", name)
+	fmt.Printf("  bt workspace status %s\n", name)
+	return nil
+}
+
+// addWorkspaceWorktree creates the worktree of branch in repo, checking out an existing local or
+// remote branch or creating a new one. An existing worktree of the branch is used as it is.
+func addWorkspaceWorktree(repo global.RepoInfo, branch string) (*workspaceAddition, error) {
+	mgr, err := repository.NewManager(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	wtMgr := worktree.NewManager(repo.Path, mgr.BareDir, mgr.Config)
+
+	if !createNoFetch && wtMgr.Executor.HasRemotes() {
+		fmt.Println("Fetching from remotes...")
+		if err := wtMgr.Fetch(""); err != nil {
+			return nil, fmt.Errorf("failed to fetch: %w", err)
+		}
+	}
+
+	branchInfo, err := wtMgr.ResolveBranch(branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve branch: %w", err)
+	}
+
+	var opts worktree.AddOptions
+	switch {
+	case branchInfo.IsLocal:
+		fmt.Printf("Using existing branch '%s'\n", branch)
+	case branchInfo.IsRemote:
+		opts.TrackRef = branchInfo.RemoteRef
+		fmt.Printf("Tracking remote branch '%s'...\n", branchInfo.RemoteRef)
+	default:
+		opts.NewBranch = true
+		if createBase != "" {
+			baseInfo, err := wtMgr.ResolveBranch(createBase)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve base branch '%s': %w", createBase, err)
+			}
+			switch {
+			case baseInfo.IsLocal:
+				opts.BaseBranch = baseInfo.Name
+			case baseInfo.IsRemote:
+				opts.BaseBranch = baseInfo.RemoteRef
+			default:
+				return nil, fmt.Errorf("base branch '%s' not found locally or on any remote", createBase)
+			}
+		}
+	}
+
+	addition := &workspaceAddition{
+		member: global.WorkspaceMember{
+			Repository:     filepath.ToSlash(repo.RelativePath),
+			RepositoryPath: repo.Path,
+		},
+		branch: branch,
+		wtMgr:  wtMgr,
+		oldSHA: global.ResolveRef(repo.Path, "refs/heads/"+branch),
+	}
+
+	worktreePath, _, err := wtMgr.AddWithOptions(branch, opts, os.Stdout)
+	if err != nil {
+		var existsErr *worktree.ErrWorktreeAlreadyExists
+		if !errors.As(err, &existsErr) {
+			return nil, err
+		}
+		fmt.Printf("Using existing worktree at %s\n", existsErr.WorktreePath)
+		addition.member.WorktreePath = existsErr.WorktreePath
+		return addition, nil
+	}
+
+	addition.member.WorktreePath = worktreePath
+	addition.createdWorktree = true
+	addition.createdBranch = opts.NewBranch || opts.TrackRef != ""
+	return addition, nil
+}
+
+// rollbackWorkspace removes the worktrees and branches created for a workspace, newest first.
+// Worktrees that existed before are left alone.
+func rollbackWorkspace(additions []workspaceAddition) {
+	if len(additions) == 0 {
+		return
+	}
+
+	fmt.Println("\nRolling back:")
+	for i := len(additions) - 1; i >= 0; i-- {
+		a := additions[i]
+		if !a.createdWorktree {
+			fmt.Printf("  - %s: kept existing worktree %s\n", a.member.Repository, a.member.WorktreePath)
+			continue
+		}
+
+		if err := a.wtMgr.Remove(a.member.WorktreePath, true); err != nil {
+			fmt.Printf("  ✗ %s: %v\n", a.member.Repository, err)
+			continue
+		}
+		cleanupEmptyParents(a.member.WorktreePath, a.member.RepositoryPath)
+		fmt.Printf("  ✓ %s: worktree removed\n", a.member.Repository)
+		if err := a.wtMgr.RefreshEditorWorkspace(); err != nil {
+			fmt.Printf("  Warning: %s: failed to update editor workspace: %v\n", a.member.Repository, err)
+		}
+
+		if !a.createdBranch {
+			continue
+		}
+		if _, err := a.wtMgr.Executor.Execute("branch", "-D", a.branch); err != nil {
+			fmt.Printf("  ✗ %s: failed to delete branch '%s': %v\n", a.member.Repository, a.branch, err)
+			continue
+		}
+		fmt.Printf("  ✓ %s: branch '%s' deleted\n", a.member.Repository, a.branch)
+	}
+}
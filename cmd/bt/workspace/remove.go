@@ -0,0 +1,195 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/amaya382/baretree/internal/config"
+	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
+	"github.com/spf13/cobra"
+)
+
+var (
+	removeForce      bool
+	removeWithBranch bool
+	removeKeep       bool
+	removePermanent  bool
+)
+
+var removeCmd = &cobra.Command{
+	Use:     "remove <workspace>",
+	Aliases: []string{"rm"},
+	Short:   "Remove the worktrees of a workspace in all its repositories",
+	Long: `Remove the worktree of a workspace in each of its repositories, then forget
+the workspace.
+
+Like 'bt remove', pre-remove hooks can abort the removal in a repository and
+worktrees with uncommitted changes, untracked files, unpushed commits or stashes
+are kept unless --force is given. If a
+worktree cannot be removed, the others are still removed and the workspace is
+kept with the remaining repositories, so the command can be run again.
+
+Like 'bt remove', worktrees are moved into the trash so they can be brought
+back with 'bt trash restore'; use --permanent to delete them right away. With
+--keep-worktrees, only the workspace record is removed.
+
+Examples:
+  bt workspace rm feature/x
+  bt workspace rm feature/x --with-branch
+  bt workspace rm feature/x --force
+  bt workspace rm feature/x --permanent
+  bt workspace rm feature/x --keep-worktrees`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runRemove,
+	ValidArgsFunction: completeWorkspaceNames,
+}
+
+func init() {
+	removeCmd.Flags().BoolVarP(&removeForce, "force", "f", false, "Force removal even with unsaved work or failing pre-remove hooks")
+	removeCmd.Flags().BoolVarP(&removeWithBranch, "with-branch", "b", false, "Also delete the branch in each repository")
+	removeCmd.Flags().BoolVar(&removePermanent, "permanent", false, "Delete the worktrees instead of moving them to the trash")
+	removeCmd.Flags().BoolVar(&removeKeep, "keep-worktrees", false, "Only forget the workspace, keeping its worktrees")
+}
+
+func runRemove(cmd *cobra.Command, args []string) error {
+	ws, err := global.GetWorkspace(args[0])
+	if err != nil {
+		return err
+	}
+
+	if removeKeep {
+		if err := global.RemoveWorkspace(ws.Name); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Workspace '%s' removed (worktrees kept)\n", ws.Name)
+		return nil
+	}
+
+	cwd, _ := os.Getwd()
+	for _, m := range ws.Members {
+		if cwd == m.WorktreePath || strings.HasPrefix(cwd, m.WorktreePath+string(filepath.Separator)) {
+			return fmt.Errorf("cannot remove workspace while inside its worktree %s", m.WorktreePath)
+		}
+	}
+
+	fmt.Printf("Removing workspace '%s' (%d repositories)...\n", ws.Name, len(ws.Members))
+
+	op := global.NewOperation("")
+	defer op.SaveOrWarn(os.Stdout)
+
+	var remaining []global.WorkspaceMember
+	for _, m := range ws.Members {
+		fmt.Printf("\n[%s]\n", m.Repository)
+		if err := removeWorkspaceMember(op, ws.Name, m); err != nil {
+			fmt.Printf("✗ %v\n", err)
+			remaining = append(remaining, m)
+		}
+	}
+
+	if len(remaining) > 0 {
+		ws.Members = remaining
+		if err := global.SaveWorkspace(*ws); err != nil {
+			return fmt.Errorf("failed to update workspace: %w", err)
+		}
+		cmd.SilenceUsage = true
+		return fmt.Errorf("failed to remove workspace '%s' in %d repository(ies); run the command again after fixing them", ws.Name, len(remaining))
+	}
+
+	if err := global.RemoveWorkspace(ws.Name); err != nil {
+		return err
+	}
+	fmt.Printf("\n✓ Workspace '%s' removed\n", ws.Name)
+	return nil
+}
+
+// removeWorkspaceMember removes the worktree of a workspace in one repository, running its
+// lifecycle hooks like 'bt remove' and recording the changes in op. A worktree that no longer
+// exists counts as removed.
+func removeWorkspaceMember(op *global.Operation, branch string, m global.WorkspaceMember) error {
+	mgr, err := repository.NewManager(m.RepositoryPath)
+	if err != nil {
+		return err
+	}
+	wtMgr := worktree.NewManager(m.RepositoryPath, mgr.BareDir, mgr.Config)
+
+	if info, err := os.Stat(m.WorktreePath); err == nil && info.IsDir() {
+		check, err := wtMgr.CheckRemoval(m.WorktreePath)
+		if err != nil {
+			if !removeForce {
+				return fmt.Errorf("failed to check worktree for unsaved work: %w", err)
+			}
+			fmt.Printf("Warning: failed to check worktree for unsaved work: %v\n", err)
+		} else if check.HasRisks() {
+			fmt.Println("Work that exists nowhere else:")
+			check.WriteReport(os.Stdout, "  ")
+			if !removeForce {
+				return fmt.Errorf("refusing to remove worktree %s (use --force to remove anyway)", m.WorktreePath)
+			}
+		}
+
+		hookCtx := worktree.HookContext{WorktreePath: m.WorktreePath, Branch: branch}
+		if _, err := wtMgr.RunHooks(config.HookPreRemove, hookCtx, os.Stdout); err != nil {
+			if !removeForce {
+				return fmt.Errorf("removal aborted: %w", err)
+			}
+			fmt.Printf("Warning: %v (continuing because of --force)\n", err)
+		}
+
+		if removePermanent {
+			head, _ := git.NewExecutor(m.WorktreePath).Execute("rev-parse", "HEAD")
+			if err := wtMgr.Remove(m.WorktreePath, removeForce); err != nil {
+				return err
+			}
+			op.Record(global.JournalStep{Action: global.StepRemoveWorktree, Repository: m.RepositoryPath, Path: m.WorktreePath, Branch: branch, Head: head})
+			cleanupEmptyParents(m.WorktreePath, m.RepositoryPath)
+			fmt.Printf("✓ Worktree removed: %s\n", m.WorktreePath)
+		} else {
+			entry, err := global.TrashWorktree(wtMgr, m.WorktreePath)
+			if err != nil {
+				return fmt.Errorf("failed to remove worktree: %w", err)
+			}
+			op.Record(global.JournalStep{Action: global.StepTrash, Repository: m.RepositoryPath, Path: m.WorktreePath, TrashID: entry.ID})
+			cleanupEmptyParents(m.WorktreePath, m.RepositoryPath)
+			fmt.Printf("✓ Worktree moved to the trash: %s (restore with 'bt trash restore %s')\n", m.WorktreePath, entry.ID)
+		}
+		if err := wtMgr.RefreshEditorWorkspace(); err != nil {
+			fmt.Printf("Warning: failed to update editor workspace: %v\n", err)
+		}
+
+		hookCtx.Dir = m.RepositoryPath
+		_, _ = wtMgr.RunHooks(config.HookPostRemove, hookCtx, os.Stdout)
+	} else {
+		fmt.Printf("- Worktree already gone: %s\n", m.WorktreePath)
+	}
+
+	if removeWithBranch {
+		flag := "-d"
+		if removeForce {
+			flag = "-D"
+		}
+		branchRef := "refs/heads/" + branch
+		oldSHA := global.ResolveRef(m.RepositoryPath, branchRef)
+		if _, err := wtMgr.Executor.Execute("branch", flag, branch); err != nil {
+			fmt.Printf("Warning: failed to delete branch '%s': %v\n", branch, err)
+		} else {
+			op.RecordRef(m.RepositoryPath, branchRef, oldSHA)
+			fmt.Printf("✓ Branch '%s' deleted\n", branch)
+		}
+	}
+	return nil
+}
+
+// cleanupEmptyParents removes the empty parent directories of a removed worktree up to
+// (but not including) the repository root
+func cleanupEmptyParents(path, repoRoot string) {
+	for parent := filepath.Dir(path); strings.HasPrefix(parent, repoRoot+string(filepath.Separator)); parent = filepath.Dir(parent) {
+		if err := os.Remove(parent); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package workspace
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/spf13/cobra"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status <workspace>",
+	Short: "Show the state of a workspace in each of its repositories",
+	Long: `Show the state of the worktree of a workspace in each of its repositories:
+whether it still exists and has the workspace's branch checked out, its HEAD
+commit, and the number of uncommitted changes.
+
+Examples:
+  bt workspace status feature/x`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runStatus,
+	ValidArgsFunction: completeWorkspaceNames,
+}
+
+// workspaceMemberStatus is the state of the worktree of a workspace in one repository
+type workspaceMemberStatus struct {
+	member global.WorkspaceMember
+	ok     bool
+	state  string // "clean", "N changed", "missing", "on <branch>"
+	head   string
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	ws, err := global.GetWorkspace(args[0])
+	if err != nil {
+		return err
+	}
+
+	var statuses []workspaceMemberStatus
+	maxRepoLen := len("REPOSITORY")
+	for _, m := range ws.Members {
+		statuses = append(statuses, inspectWorkspaceMember(ws.Name, m))
+		if len(m.Repository) > maxRepoLen {
+			maxRepoLen = len(m.Repository)
+		}
+	}
+
+	fmt.Printf("Workspace: %s (%d repositories)\n\n", ws.Name, len(ws.Members))
+	fmt.Printf("  %-*s  %-7s  %-10s  %s\n", maxRepoLen, "REPOSITORY", "HEAD", "STATE", "PATH")
+	problems := 0
+	for _, s := range statuses {
+		mark := "✓"
+		if !s.ok {
+			mark = "✗"
+			problems++
+		}
+		fmt.Printf("%s %-*s  %-7s  %-10s  %s\n", mark, maxRepoLen, s.member.Repository, s.head, s.state, s.member.WorktreePath)
+	}
+
+	if problems > 0 {
+		fmt.Printf("\n%d worktree(s) missing or on another branch\n", problems)
+	}
+	return nil
+}
+
+// inspectWorkspaceMember reads the state of the worktree of a workspace member
+func inspectWorkspaceMember(branch string, m global.WorkspaceMember) workspaceMemberStatus {
+	status := workspaceMemberStatus{member: m, head: "-"}
+
+	if info, err := os.Stat(m.WorktreePath); err != nil || !info.IsDir() {
+		status.state = "missing"
+		return status
+	}
+
+	executor := git.NewExecutor(m.WorktreePath)
+	if head, err := executor.Execute("rev-parse", "--short", "HEAD"); err == nil {
+		status.head = head
+	}
+	if current, err := executor.Execute("symbolic-ref", "--short", "HEAD"); err != nil || current != branch {
+		if current == "" {
+			current = "detached"
+		}
+		status.state = "on " + current
+		return status
+	}
+
+	status.ok = true
+	status.state = "clean"
+	if changes, err := executor.Execute("status", "--porcelain"); err == nil && changes != "" {
+		status.state = fmt.Sprintf("%d changed", len(strings.Split(changes, "\n")))
+	}
+	return status
+}
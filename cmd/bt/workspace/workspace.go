@@ -0,0 +1,92 @@
+package workspace
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/spf13/cobra"
+)
+
+// Cmd is the parent command for multi-repository workspaces
+var Cmd = &cobra.Command{
+	Use:   "workspace",
+	Short: "Work on one branch across several repositories (create, cd, status, rm)",
+	Long: `Work on one branch across several repositories.
+
+A workspace is a branch checked out as a worktree in several repositories
+under the baretree root, e.g. a feature spanning the API, the web app and a
+shared proto repository. The set of repositories is recorded, so the
+workspace can be inspected and removed as a whole.
+
+Examples:
+  bt workspace create feature/x --repos api,web,proto
+  bt workspace cd feature/x web
+  bt workspace status feature/x
+  bt workspace list
+  bt workspace rm feature/x --with-branch`,
+}
+
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List workspaces",
+	Args:    cobra.NoArgs,
+	RunE:    runList,
+}
+
+func init() {
+	Cmd.AddCommand(createCmd)
+	Cmd.AddCommand(cdCmd)
+	Cmd.AddCommand(statusCmd)
+	Cmd.AddCommand(removeCmd)
+	Cmd.AddCommand(listCmd)
+}
+
+func runList(cmd *cobra.Command, args []string) error {
+	workspaces, err := global.ListWorkspaces()
+	if err != nil {
+		return err
+	}
+
+	if len(workspaces) == 0 {
+		fmt.Println("No workspaces found")
+		return nil
+	}
+
+	maxNameLen := len("WORKSPACE")
+	for _, ws := range workspaces {
+		if len(ws.Name) > maxNameLen {
+			maxNameLen = len(ws.Name)
+		}
+	}
+
+	fmt.Printf("%-*s  %s\n", maxNameLen, "WORKSPACE", "REPOSITORIES")
+	for _, ws := range workspaces {
+		repos := make([]string, len(ws.Members))
+		for i, m := range ws.Members {
+			repos[i] = m.Repository
+		}
+		fmt.Printf("%-*s  %s\n", maxNameLen, ws.Name, strings.Join(repos, ", "))
+	}
+	return nil
+}
+
+// completeWorkspaceNames returns workspace names for shell completion
+func completeWorkspaceNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	completions := []string{}
+	if len(args) > 0 {
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	workspaces, err := global.ListWorkspaces()
+	if err != nil {
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+	for _, ws := range workspaces {
+		if strings.HasPrefix(ws.Name, toComplete) {
+			completions = append(completions, ws.Name)
+		}
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,95 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestAddRequest tests creating and refreshing worktrees from pull/merge request numbers
+func TestAddRequest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "add-request")
+	originPath := setupRemoteRepo(t, tempDir)
+	workPath := filepath.Join(tempDir, "work")
+
+	// Publish feature/remote as pull request #7 and merge request !3 (under a custom ref)
+	runGitSuccess(t, workPath, "push", "origin", "feature/remote:refs/pull/7/head")
+	runGitSuccess(t, workPath, "push", "origin", "feature/remote:refs/review/3")
+
+	runBtSuccess(t, tempDir, "repo", "clone", originPath, "test-repo")
+	projectDir := filepath.Join(tempDir, "test-repo")
+	bareDir := filepath.Join(projectDir, ".git")
+	prDir := filepath.Join(projectDir, "pr", "7")
+
+	t.Run("add pull request", func(t *testing.T) {
+		stdout := runBtSuccess(t, projectDir, "add", "--pr", "7")
+		assertOutputContains(t, stdout, "Fetching refs/pull/7/head from origin")
+		assertOutputContains(t, stdout, "Worktree created")
+		assertFileExists(t, filepath.Join(prDir, "feature.txt"))
+
+		branch := strings.TrimSpace(runGitSuccess(t, prDir, "rev-parse", "--abbrev-ref", "HEAD"))
+		if branch != "pr/7" {
+			t.Errorf("expected branch pr/7, got %s", branch)
+		}
+	})
+
+	t.Run("add pull request again refreshes it", func(t *testing.T) {
+		stdout := runBtSuccess(t, projectDir, "add", "--pr", "7")
+		assertOutputContains(t, stdout, "already up to date")
+
+		// Push a new commit to the pull request
+		if err := os.WriteFile(filepath.Join(workPath, "review.txt"), []byte("review fix"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGitSuccess(t, workPath, "checkout", "feature/remote")
+		runGitSuccess(t, workPath, "add", ".")
+		runGitSuccess(t, workPath, "commit", "-m", "review fix")
+		runGitSuccess(t, workPath, "push", "origin", "feature/remote:refs/pull/7/head")
+
+		stdout = runBtSuccess(t, projectDir, "add", "--pr", "7")
+		assertOutputContains(t, stdout, "Updated 'pr/7'")
+		assertFileExists(t, filepath.Join(prDir, "review.txt"))
+
+		// The fast-forward is recorded in the journal
+		stdout = runBtSuccess(t, projectDir, "log", "-v", "-n", "1")
+		assertOutputContains(t, stdout, "updated refs/heads/pr/7")
+	})
+
+	t.Run("refresh from another remote fails", func(t *testing.T) {
+		_, stderr := runBtFailure(t, projectDir, "add", "--pr", "7", "--remote", "upstream")
+		assertOutputContains(t, stderr, "'pr/7' is fetched from remote 'origin', not 'upstream'")
+
+		stdout := runBtSuccess(t, projectDir, "add", "--pr", "7", "--remote", "origin")
+		assertOutputContains(t, stdout, "already up to date")
+	})
+
+	t.Run("unknown pull request fails", func(t *testing.T) {
+		_, stderr := runBtFailure(t, projectDir, "add", "--pr", "999")
+		assertOutputContains(t, stderr, "failed to fetch refs/pull/999/head")
+		assertFileNotExists(t, filepath.Join(projectDir, "pr", "999"))
+	})
+
+	t.Run("existing branch is not overwritten", func(t *testing.T) {
+		runGitSuccess(t, bareDir, "branch", "pr/8", "main")
+		_, stderr := runBtFailure(t, projectDir, "add", "--pr", "8")
+		assertOutputContains(t, stderr, "already exists")
+	})
+
+	t.Run("merge request with per-host ref pattern", func(t *testing.T) {
+		runGitSuccess(t, bareDir, "config", "baretree.host.local.mergeRequestRef", "refs/review/{number}")
+
+		stdout := runBtSuccess(t, projectDir, "add", "--mr", "3")
+		assertOutputContains(t, stdout, "Fetching refs/review/3 from origin")
+		assertFileExists(t, filepath.Join(projectDir, "mr", "3", "feature.txt"))
+	})
+
+	t.Run("branch name and request are exclusive", func(t *testing.T) {
+		runBtFailure(t, projectDir, "add", "--pr", "7", "feature/remote")
+		runBtFailure(t, projectDir, "add", "-b", "--pr", "7")
+	})
+}
@@ -0,0 +1,107 @@
+package e2e
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCdHistory tests going back through previously visited worktrees and repositories
+func TestCdHistory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "cd-history")
+	root := filepath.Join(tempDir, "root")
+	env := map[string]string{
+		"BARETREE_ROOT":  root,
+		"XDG_STATE_HOME": filepath.Join(tempDir, "state"),
+	}
+
+	bt := func(t *testing.T, dir string, args ...string) string {
+		t.Helper()
+		stdout, stderr, err := runBtWithEnv(t, dir, env, args...)
+		if err != nil {
+			t.Fatalf("bt %v failed: %v\nstdout: %s\nstderr: %s", args, err, stdout, stderr)
+		}
+		return strings.TrimSpace(stdout)
+	}
+
+	repoA := filepath.Join(root, "github.com", "user", "alpha")
+	repoB := filepath.Join(root, "github.com", "user", "beta")
+	bt(t, tempDir, "init", repoA)
+	bt(t, tempDir, "init", repoB)
+	bt(t, repoA, "add", "-b", "feature-a")
+	bt(t, repoA, "add", "-b", "feature-b")
+
+	main := filepath.Join(repoA, "main")
+	featureA := filepath.Join(repoA, "feature-a")
+	featureB := filepath.Join(repoA, "feature-b")
+
+	// Simulate the shell function: run each bt cd from the directory the previous one printed
+	bt(t, main, "cd", "feature-a")
+	bt(t, featureA, "cd", "feature-b")
+
+	t.Run("cd - goes to the previous worktree", func(t *testing.T) {
+		if got := bt(t, featureB, "cd", "-"); got != featureA {
+			t.Errorf("bt cd - = %s, want %s", got, featureA)
+		}
+	})
+
+	t.Run("cd -N goes further back", func(t *testing.T) {
+		// History is now feature-b, main (we are in feature-a)
+		if got := bt(t, featureA, "cd", "-2"); got != main {
+			t.Errorf("bt cd -2 = %s, want %s", got, main)
+		}
+	})
+
+	t.Run("cd --history lists previous worktrees", func(t *testing.T) {
+		stdout := bt(t, main, "cd", "--history")
+		lines := strings.Split(stdout, "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 history entries, got:\n%s", stdout)
+		}
+		assertOutputContains(t, lines[0], "-1  feature-a")
+		assertOutputContains(t, lines[1], "-2  feature-b")
+	})
+
+	t.Run("cd -N beyond the history fails", func(t *testing.T) {
+		_, stderr, err := runBtWithEnv(t, main, env, "cd", "-9")
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		assertOutputContains(t, stderr, "no previous directory at -9")
+	})
+
+	t.Run("history is kept per repository", func(t *testing.T) {
+		stdout := bt(t, repoB, "cd", "--history")
+		assertOutputContains(t, stdout, "No directory history")
+	})
+
+	t.Run("go -N and go --history", func(t *testing.T) {
+		bt(t, tempDir, "go", "alpha")
+		bt(t, repoA, "go", "beta")
+
+		stdout := bt(t, repoB, "go", "--history")
+		lines := strings.Split(stdout, "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 history entries, got:\n%s", stdout)
+		}
+		assertOutputContains(t, lines[0], "-1  "+filepath.Join("github.com", "user", "alpha"))
+		assertOutputContains(t, lines[1], "-2  "+tempDir)
+
+		if got := bt(t, repoB, "go", "-2"); got != tempDir {
+			t.Errorf("bt go -2 = %s, want %s", got, tempDir)
+		}
+		if got := bt(t, tempDir, "repo", "cd", "-1"); got != repoB {
+			t.Errorf("bt repo cd -1 = %s, want %s", got, repoB)
+		}
+	})
+
+	t.Run("flags after -N are still parsed", func(t *testing.T) {
+		if got := bt(t, main, "cd", "-1", "--root"); got != featureA {
+			t.Errorf("bt cd -1 --root = %s, want %s", got, featureA)
+		}
+	})
+}
@@ -0,0 +1,78 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCdSubdir tests that bt cd keeps the relative subdirectory when switching worktrees
+func TestCdSubdir(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "cd-subdir")
+	repoDir := filepath.Join(tempDir, "project")
+	runBtSuccess(t, tempDir, "init", repoDir)
+
+	mainDir := filepath.Join(repoDir, "main")
+	if err := os.MkdirAll(filepath.Join(mainDir, "services", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(mainDir, "services", "api", "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGitSuccess(t, mainDir, "add", ".")
+	runGitSuccess(t, mainDir, "commit", "-m", "Add api service")
+	runBtSuccess(t, repoDir, "add", "-b", "feature/auth")
+
+	featureDir := filepath.Join(repoDir, "feature", "auth")
+	if err := os.MkdirAll(filepath.Join(featureDir, "services", "api", "handlers"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cd := func(t *testing.T, dir string, args ...string) string {
+		t.Helper()
+		return strings.TrimSpace(runBtSuccess(t, dir, append([]string{"cd"}, args...)...))
+	}
+
+	t.Run("same subdirectory in the target worktree", func(t *testing.T) {
+		got := cd(t, filepath.Join(mainDir, "services", "api"), "feature/auth")
+		if want := filepath.Join(featureDir, "services", "api"); got != want {
+			t.Errorf("bt cd = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("nearest existing parent", func(t *testing.T) {
+		got := cd(t, filepath.Join(featureDir, "services", "api", "handlers"), "main")
+		if want := filepath.Join(mainDir, "services", "api"); got != want {
+			t.Errorf("bt cd = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("root flag goes to the worktree root", func(t *testing.T) {
+		got := cd(t, filepath.Join(mainDir, "services", "api"), "feature/auth", "--root")
+		if got != featureDir {
+			t.Errorf("bt cd --root = %s, want %s", got, featureDir)
+		}
+	})
+
+	t.Run("empty name still goes to the current worktree root", func(t *testing.T) {
+		got := cd(t, filepath.Join(mainDir, "services", "api"))
+		if got != mainDir {
+			t.Errorf("bt cd = %s, want %s", got, mainDir)
+		}
+	})
+
+	t.Run("keepSubdir can be disabled", func(t *testing.T) {
+		runGitSuccess(t, repoDir, "config", "baretree.keepSubdir", "false")
+		defer runGitSuccess(t, repoDir, "config", "--unset", "baretree.keepSubdir")
+
+		got := cd(t, filepath.Join(mainDir, "services", "api"), "feature/auth")
+		if got != featureDir {
+			t.Errorf("bt cd = %s, want %s", got, featureDir)
+		}
+	})
+}
@@ -0,0 +1,104 @@
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestEditorWorkspace tests generating editor workspaces with one folder per worktree
+func TestEditorWorkspace(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "editor-workspace")
+	repoDir := filepath.Join(tempDir, "project")
+	runBtSuccess(t, tempDir, "init", repoDir)
+	workspaceFile := filepath.Join(repoDir, "project.code-workspace")
+
+	readFolders := func(t *testing.T) []string {
+		t.Helper()
+		data, err := os.ReadFile(workspaceFile)
+		if err != nil {
+			t.Fatalf("failed to read workspace file: %v", err)
+		}
+		var doc struct {
+			Folders []struct {
+				Path string `json:"path"`
+			} `json:"folders"`
+		}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("invalid workspace file: %v\n%s", err, data)
+		}
+		var paths []string
+		for _, f := range doc.Folders {
+			paths = append(paths, f.Path)
+		}
+		return paths
+	}
+
+	assertFolders := func(t *testing.T, want ...string) {
+		t.Helper()
+		got := readFolders(t)
+		if len(got) != len(want) {
+			t.Fatalf("folders = %v, want %v", got, want)
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Fatalf("folders = %v, want %v", got, want)
+			}
+		}
+	}
+
+	t.Run("generates a vscode workspace", func(t *testing.T) {
+		runBtSuccess(t, repoDir, "add", "-b", "feature/auth")
+		stdout := runBtSuccess(t, repoDir, "editor", "workspace")
+		assertOutputContains(t, stdout, "project.code-workspace (2 worktree(s))")
+		assertFolders(t, "main", "feature/auth")
+	})
+
+	t.Run("without --auto the workspace is not updated", func(t *testing.T) {
+		runBtSuccess(t, repoDir, "add", "-b", "bugfix/login")
+		assertFolders(t, "main", "feature/auth")
+	})
+
+	t.Run("auto keeps the workspace current", func(t *testing.T) {
+		runBtSuccess(t, repoDir, "editor", "workspace", "--auto")
+		assertFolders(t, "main", "bugfix/login", "feature/auth")
+
+		runBtSuccess(t, repoDir, "rm", "bugfix/login")
+		assertFolders(t, "main", "feature/auth")
+
+		runBtSuccess(t, repoDir, "trash", "restore", "bugfix/login")
+		assertFolders(t, "main", "bugfix/login", "feature/auth")
+
+		runBtSuccess(t, repoDir, "rm", "bugfix/login", "--permanent")
+		assertFolders(t, "main", "feature/auth")
+
+		runBtSuccess(t, repoDir, "rename", "feature/auth", "feature/login")
+		assertFolders(t, "main", "feature/login")
+
+		runBtSuccess(t, repoDir, "add", "-b", "feature/signup")
+		assertFolders(t, "main", "feature/login", "feature/signup")
+	})
+
+	t.Run("no-auto stops updating", func(t *testing.T) {
+		runBtSuccess(t, repoDir, "editor", "workspace", "--no-auto")
+		runBtSuccess(t, repoDir, "rm", "feature/signup")
+		assertFolders(t, "main", "feature/login", "feature/signup")
+	})
+
+	t.Run("generates jetbrains modules", func(t *testing.T) {
+		stdout := runBtSuccess(t, repoDir, "editor", "workspace", "--format", "jetbrains")
+		assertOutputContains(t, stdout, filepath.Join(".idea", "modules.xml"))
+		assertFileExists(t, filepath.Join(repoDir, ".idea", "baretree", "main.iml"))
+		assertFileExists(t, filepath.Join(repoDir, ".idea", "baretree", "feature-login.iml"))
+	})
+
+	t.Run("unknown format is rejected", func(t *testing.T) {
+		_, stderr := runBtFailure(t, repoDir, "editor", "workspace", "--format", "emacs")
+		assertOutputContains(t, stderr, "unknown editor workspace format")
+	})
+}
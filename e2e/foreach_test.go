@@ -0,0 +1,114 @@
+package e2e
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestForeach tests running commands across worktrees and repositories
+func TestForeach(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "foreach")
+	root := filepath.Join(tempDir, "root")
+	env := map[string]string{
+		"BARETREE_ROOT":  root,
+		"XDG_CACHE_HOME": filepath.Join(tempDir, "cache"),
+	}
+
+	repoA := filepath.Join(root, "github.com", "user", "alpha")
+	repoB := filepath.Join(root, "github.com", "other", "beta")
+	for _, args := range [][]string{
+		{"init", repoA},
+		{"init", repoB},
+	} {
+		if stdout, stderr, err := runBtWithEnv(t, tempDir, env, args...); err != nil {
+			t.Fatalf("bt %v failed: %v\nstdout: %s\nstderr: %s", args, err, stdout, stderr)
+		}
+	}
+	runBtSuccess(t, repoA, "add", "-b", "feature/auth")
+	runBtSuccess(t, repoA, "add", "-b", "bugfix/login")
+
+	t.Run("runs in every worktree with labelled output", func(t *testing.T) {
+		stdout := runBtSuccess(t, repoA, "foreach", "--", "git", "branch", "--show-current")
+		assertOutputContains(t, stdout, "in 3 worktree(s)")
+		assertOutputContains(t, stdout, "[main] main")
+		assertOutputContains(t, stdout, "[feature/auth] feature/auth")
+		assertOutputContains(t, stdout, "[bugfix/login] bugfix/login")
+		assertOutputContains(t, stdout, "3 succeeded, 0 failed")
+	})
+
+	t.Run("flags after the command belong to the command", func(t *testing.T) {
+		stdout := runBtSuccess(t, repoA, "foreach", "git", "status", "-s")
+		assertOutputContains(t, stdout, "3 succeeded, 0 failed")
+	})
+
+	t.Run("filter selects worktrees", func(t *testing.T) {
+		stdout := runBtSuccess(t, repoA, "foreach", "--filter", "feature/*", "--", "echo $BT_BRANCH")
+		assertOutputContains(t, stdout, "[feature/auth] feature/auth")
+		assertOutputNotContains(t, stdout, "[main]")
+		assertOutputNotContains(t, stdout, "[bugfix/login]")
+	})
+
+	t.Run("failure gives a non-zero exit code", func(t *testing.T) {
+		stdout, stderr := runBtFailure(t, repoA, "foreach", "--parallel", "3", "--", `test "$BT_BRANCH" != bugfix/login`)
+		assertOutputContains(t, stdout, "[bugfix/login] ✗ exit status 1")
+		assertOutputContains(t, stdout, "2 succeeded, 1 failed")
+		assertOutputContains(t, stderr, "command failed in 1 of 3 worktree(s)")
+	})
+
+	t.Run("json summary", func(t *testing.T) {
+		stdout, _, err := runBt(t, repoA, "foreach", "--json", "--", `echo hello; test "$BT_BRANCH" = main`)
+		if err == nil {
+			t.Fatal("expected non-zero exit code")
+		}
+
+		var summary struct {
+			Succeeded int `json:"succeeded"`
+			Failed    int `json:"failed"`
+			Results   []struct {
+				Name     string `json:"name"`
+				Success  bool   `json:"success"`
+				ExitCode int    `json:"exit_code"`
+				Output   string `json:"output"`
+			} `json:"results"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &summary); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, stdout)
+		}
+		if summary.Succeeded != 1 || summary.Failed != 2 || len(summary.Results) != 3 {
+			t.Fatalf("unexpected summary: %+v", summary)
+		}
+		for _, r := range summary.Results {
+			if r.Output != "hello\n" {
+				t.Errorf("%s: expected captured output, got %q", r.Name, r.Output)
+			}
+			if (r.Name == "main") != r.Success || (!r.Success && r.ExitCode != 1) {
+				t.Errorf("unexpected result: %+v", r)
+			}
+		}
+	})
+
+	t.Run("repos runs in the default worktree of every repository", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "foreach", "--repos", "--", "pwd")
+		if err != nil {
+			t.Fatalf("bt foreach --repos failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertOutputContains(t, stdout, "in 2 repository(ies)")
+		assertOutputContains(t, stdout, "[github.com/user/alpha] "+filepath.Join(repoA, "main"))
+		assertOutputContains(t, stdout, "[github.com/other/beta] "+filepath.Join(repoB, "main"))
+
+		stdout, stderr, err = runBtWithEnv(t, tempDir, env, "foreach", "--repos", "--filter", "github.com/other/*", "--", "pwd")
+		if err != nil {
+			t.Fatalf("bt foreach --repos --filter failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		if strings.Contains(stdout, "alpha") {
+			t.Errorf("expected only beta, got:\n%s", stdout)
+		}
+		assertOutputContains(t, stdout, "1 succeeded, 0 failed")
+	})
+}
@@ -0,0 +1,108 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestFrecency tests fuzzy resolution of repositories and worktrees ranked by frecency
+func TestFrecency(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "frecency")
+	root := filepath.Join(tempDir, "root")
+	env := map[string]string{
+		"BARETREE_ROOT":  root,
+		"XDG_STATE_HOME": filepath.Join(tempDir, "state"),
+	}
+
+	bt := func(t *testing.T, dir string, args ...string) string {
+		t.Helper()
+		stdout, stderr, err := runBtWithEnv(t, dir, env, args...)
+		if err != nil {
+			t.Fatalf("bt %v failed: %v\nstdout: %s\nstderr: %s", args, err, stdout, stderr)
+		}
+		return stdout
+	}
+
+	aliceTool := filepath.Join(root, "github.com", "alice", "tool")
+	bobTool := filepath.Join(root, "github.com", "bob", "tool")
+	baretree := filepath.Join(root, "github.com", "amaya382", "baretree")
+	for _, dir := range []string{aliceTool, bobTool, baretree} {
+		bt(t, tempDir, "init", dir)
+	}
+
+	t.Run("ambiguous repository without history", func(t *testing.T) {
+		_, stderr, err := runBtWithEnv(t, tempDir, env, "go", "tool")
+		if err == nil {
+			t.Fatal("expected ambiguous error")
+		}
+		assertOutputContains(t, stderr, "Ambiguous repository name")
+	})
+
+	t.Run("fuzzy repository match", func(t *testing.T) {
+		stdout := bt(t, tempDir, "go", "brtr")
+		assertOutputContains(t, stdout, baretree)
+	})
+
+	t.Run("most used repository wins", func(t *testing.T) {
+		bt(t, tempDir, "go", "bob/tool")
+		bt(t, tempDir, "go", "bob/tool")
+
+		stdout := bt(t, tempDir, "go", "tool")
+		assertOutputContains(t, stdout, bobTool)
+	})
+
+	t.Run("repos and completion are ordered by frecency", func(t *testing.T) {
+		stdout := bt(t, tempDir, "repos")
+		lines := strings.Split(strings.TrimSpace(stdout), "\n")
+		if lines[0] != filepath.Join("github.com", "bob", "tool") {
+			t.Errorf("expected bob/tool first, got:\n%s", stdout)
+		}
+
+		stdout = bt(t, tempDir, "__complete", "go", "")
+		lines = strings.Split(strings.TrimSpace(stdout), "\n")
+		if lines[0] != filepath.Join("github.com", "bob", "tool") {
+			t.Errorf("expected bob/tool first in completion, got:\n%s", stdout)
+		}
+	})
+
+	t.Run("most used worktree wins", func(t *testing.T) {
+		bt(t, bobTool, "add", "-b", "feature/auth-refresh")
+		bt(t, bobTool, "add", "-b", "feature/auth-login")
+
+		_, stderr, err := runBtWithEnv(t, bobTool, env, "cd", "auth")
+		if err == nil {
+			t.Fatal("expected ambiguous error")
+		}
+		assertOutputContains(t, stderr, "Ambiguous worktree name")
+
+		bt(t, bobTool, "cd", "feature/auth-login")
+		stdout := bt(t, bobTool, "cd", "auth")
+		assertOutputContains(t, stdout, filepath.Join(bobTool, "feature", "auth-login"))
+
+		stdout = bt(t, bobTool, "cd", "refresh")
+		assertOutputContains(t, stdout, filepath.Join(bobTool, "feature", "auth-refresh"))
+	})
+
+	t.Run("frecency can be disabled", func(t *testing.T) {
+		gitconfig := filepath.Join(tempDir, "gitconfig")
+		if err := os.WriteFile(gitconfig, []byte("[baretree]\n\tfrecency = false\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		disabled := map[string]string{"GIT_CONFIG_GLOBAL": gitconfig}
+		for k, v := range env {
+			disabled[k] = v
+		}
+
+		_, stderr, err := runBtWithEnv(t, tempDir, disabled, "go", "tool")
+		if err == nil {
+			t.Fatal("expected ambiguous error with frecency disabled")
+		}
+		assertOutputContains(t, stderr, "Ambiguous repository name")
+	})
+}
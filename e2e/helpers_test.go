@@ -22,11 +22,26 @@ func TestMain(m *testing.M) {
 		panic("failed to build bt binary: " + err.Error())
 	}
 
+	// Keep caches and state written by bt (e.g. the repository index and frecency store)
+	// out of the user's directories
+	cacheDir, err := os.MkdirTemp("", "bt-e2e-cache-")
+	if err != nil {
+		panic("failed to create cache directory: " + err.Error())
+	}
+	os.Setenv("XDG_CACHE_HOME", cacheDir)
+	stateDir, err := os.MkdirTemp("", "bt-e2e-state-")
+	if err != nil {
+		panic("failed to create state directory: " + err.Error())
+	}
+	os.Setenv("XDG_STATE_HOME", stateDir)
+
 	// Run tests
 	code := m.Run()
 
 	// Cleanup
 	os.Remove(btBinary)
+	os.RemoveAll(cacheDir)
+	os.RemoveAll(stateDir)
 
 	os.Exit(code)
 }
@@ -66,6 +81,22 @@ func runBt(t *testing.T, workDir string, args ...string) (stdout, stderr string,
 	return outBuf.String(), errBuf.String(), err
 }
 
+// runBtWithInput executes the bt command with the given standard input
+func runBtWithInput(t *testing.T, workDir, input string, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+
+	cmd := exec.Command(btBinary, args...)
+	cmd.Dir = workDir
+	cmd.Stdin = strings.NewReader(input)
+
+	var outBuf, errBuf bytes.Buffer
+	cmd.Stdout = &outBuf
+	cmd.Stderr = &errBuf
+
+	err = cmd.Run()
+	return outBuf.String(), errBuf.String(), err
+}
+
 // runBtSuccess runs bt and expects it to succeed
 func runBtSuccess(t *testing.T, workDir string, args ...string) string {
 	t.Helper()
@@ -0,0 +1,162 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestHooksManagement tests bt hooks add/list/remove and config export
+func TestHooksManagement(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "hooks-manage")
+	projectDir := filepath.Join(tempDir, "my-project")
+	runBtSuccess(t, tempDir, "init", projectDir)
+
+	t.Run("add hooks", func(t *testing.T) {
+		stdout := runBtSuccess(t, projectDir, "hooks", "add", "pre-remove", "docker compose down")
+		assertOutputContains(t, stdout, "pre-remove hook added")
+		runBtSuccess(t, projectDir, "hooks", "add", "post-rename", "echo renamed")
+
+		values := getGitConfigAll(t, filepath.Join(projectDir, ".git"), "baretree.preremove")
+		if len(values) != 1 || values[0] != "docker compose down" {
+			t.Errorf("unexpected baretree.preremove values: %v", values)
+		}
+	})
+
+	t.Run("duplicate hook is rejected", func(t *testing.T) {
+		_, stderr := runBtFailure(t, projectDir, "hooks", "add", "pre-remove", "docker compose down")
+		assertOutputContains(t, stderr, "already configured")
+	})
+
+	t.Run("unknown event is rejected", func(t *testing.T) {
+		_, stderr := runBtFailure(t, projectDir, "hooks", "add", "pre-create", "true")
+		assertOutputContains(t, stderr, "unknown hook event")
+	})
+
+	t.Run("list hooks", func(t *testing.T) {
+		stdout := runBtSuccess(t, projectDir, "hooks", "list")
+		assertOutputContains(t, stdout, "pre-remove:")
+		assertOutputContains(t, stdout, "docker compose down")
+		assertOutputContains(t, stdout, "post-rename:")
+
+		stdout = runBtSuccess(t, projectDir, "hooks", "list", "post-rename")
+		assertOutputNotContains(t, stdout, "pre-remove")
+	})
+
+	t.Run("export includes hook sections", func(t *testing.T) {
+		stdout := runBtSuccess(t, projectDir, "config", "export")
+		assertOutputContains(t, stdout, "[[preremove]]")
+		assertOutputContains(t, stdout, "[[postrename]]")
+	})
+
+	t.Run("remove hook", func(t *testing.T) {
+		runBtSuccess(t, projectDir, "hooks", "remove", "pre-remove", "docker compose down")
+		stdout := runBtSuccess(t, projectDir, "hooks", "list")
+		assertOutputNotContains(t, stdout, "docker compose down")
+	})
+}
+
+// TestHooksRemoveLifecycle tests pre-remove/post-remove hooks, including vetoing a removal
+func TestHooksRemoveLifecycle(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "hooks-remove")
+	projectDir := filepath.Join(tempDir, "my-project")
+	logFile := filepath.Join(tempDir, "hooks.log")
+	runBtSuccess(t, tempDir, "init", projectDir)
+
+	runBtSuccess(t, projectDir, "hooks", "add", "pre-remove", `echo "pre $BT_BRANCH $(pwd)" >> `+logFile)
+	runBtSuccess(t, projectDir, "hooks", "add", "post-remove", `echo "post $BT_BRANCH $(pwd)" >> `+logFile)
+
+	t.Run("hooks run around removal", func(t *testing.T) {
+		runBtSuccess(t, projectDir, "add", "-b", "feature/a")
+		featureDir := filepath.Join(projectDir, "feature", "a")
+
+		stdout := runBtSuccess(t, projectDir, "rm", "feature/a", "--with-branch")
+		assertOutputContains(t, stdout, "Pre-remove hooks:")
+		assertOutputContains(t, stdout, "Post-remove hooks:")
+		assertFileNotExists(t, featureDir)
+
+		content, err := os.ReadFile(logFile)
+		if err != nil {
+			t.Fatalf("failed to read hook log: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+		if len(lines) != 2 || lines[0] != "pre feature/a "+featureDir || lines[1] != "post feature/a "+projectDir {
+			t.Errorf("unexpected hook log:\n%s", content)
+		}
+	})
+
+	runBtSuccess(t, projectDir, "hooks", "add", "pre-remove", "echo refusing >&2; exit 1")
+	runBtSuccess(t, projectDir, "add", "-b", "feature/b")
+	featureDir := filepath.Join(projectDir, "feature", "b")
+
+	t.Run("failing pre-remove hook vetoes removal", func(t *testing.T) {
+		stdout, stderr := runBtFailure(t, projectDir, "rm", "feature/b", "--with-branch")
+		assertOutputContains(t, stdout, "refusing")
+		assertOutputContains(t, stderr, "removal aborted")
+		assertFileExists(t, featureDir)
+	})
+
+	t.Run("force overrides the veto", func(t *testing.T) {
+		stdout := runBtSuccess(t, projectDir, "rm", "feature/b", "--force")
+		assertOutputContains(t, stdout, "continuing because of --force")
+		assertFileNotExists(t, featureDir)
+	})
+}
+
+// TestHooksPostRename tests that post-rename hooks receive old and new names
+func TestHooksPostRename(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "hooks-rename")
+	projectDir := filepath.Join(tempDir, "my-project")
+	runBtSuccess(t, tempDir, "init", projectDir)
+	runBtSuccess(t, projectDir, "add", "-b", "feature/old")
+
+	runBtSuccess(t, projectDir, "hooks", "add", "post-rename", `echo "$BT_OLD_BRANCH -> $BT_BRANCH" > .renamed`)
+
+	stdout := runBtSuccess(t, projectDir, "rename", "feature/old", "feature/new")
+	assertOutputContains(t, stdout, "Post-rename hooks:")
+	assertFileContent(t, filepath.Join(projectDir, "feature", "new", ".renamed"), "feature/old -> feature/new\n")
+}
+
+// TestHooksPostCheckout tests that post-checkout hooks run in the repository created by unbare
+func TestHooksPostCheckout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "hooks-checkout")
+	projectDir := filepath.Join(tempDir, "my-project")
+	destDir := filepath.Join(tempDir, "standalone")
+	runBtSuccess(t, tempDir, "init", projectDir)
+
+	runBtSuccess(t, projectDir, "hooks", "add", "post-checkout", `echo "$BT_BRANCH" > .checked-out`)
+
+	stdout := runBtSuccess(t, projectDir, "unbare", "main", destDir)
+	assertOutputContains(t, stdout, "Post-checkout hooks:")
+	assertFileContent(t, filepath.Join(destDir, ".checked-out"), "main\n")
+}
+
+// getGitConfigAll returns all values of a multi-valued key in the bare repository config
+func getGitConfigAll(t *testing.T, bareDir, key string) []string {
+	t.Helper()
+	output := runGitSuccess(t, bareDir, "config", "--get-all", key)
+	var values []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line != "" {
+			values = append(values, line)
+		}
+	}
+	return values
+}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -533,6 +534,143 @@ func TestPostCreateCommandWithQuotes(t *testing.T) {
 	})
 }
 
+// TestPostCreateCommandEnvironment tests that commands receive BT_* variables describing the worktree
+func TestPostCreateCommandEnvironment(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "postcreate-env")
+	projectDir := filepath.Join(tempDir, "my-project")
+	runBtSuccess(t, tempDir, "init", projectDir)
+
+	runBtSuccess(t, projectDir, "post-create", "add", "command",
+		`printf '%s\n' "$BT_REPO_ROOT" "$BT_WORKTREE_PATH" "$BT_BRANCH" "$BT_BASE_BRANCH" "$BT_DEFAULT_BRANCH" "$BT_SHARED_DIR" > .bt-env`)
+
+	t.Run("new branch receives worktree context", func(t *testing.T) {
+		runBtSuccess(t, projectDir, "add", "-b", "feature/env")
+		featureDir := filepath.Join(projectDir, "feature", "env")
+
+		expected := strings.Join([]string{
+			projectDir,
+			featureDir,
+			"feature/env",
+			"main",
+			"main",
+			filepath.Join(projectDir, ".shared"),
+		}, "\n") + "\n"
+		assertFileContent(t, filepath.Join(featureDir, ".bt-env"), expected)
+	})
+
+	t.Run("explicit base branch is passed through", func(t *testing.T) {
+		runBtSuccess(t, projectDir, "add", "-b", "feature/child", "--base", "feature/env")
+		content, err := os.ReadFile(filepath.Join(projectDir, "feature", "child", ".bt-env"))
+		if err != nil {
+			t.Fatalf("failed to read .bt-env: %v", err)
+		}
+		lines := strings.Split(string(content), "\n")
+		if len(lines) < 4 || lines[2] != "feature/child" || lines[3] != "feature/env" {
+			t.Errorf("expected branch feature/child based on feature/env, got:\n%s", content)
+		}
+	})
+}
+
+func TestPostCreateCommandParallel(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "postcreate-parallel")
+	projectDir := filepath.Join(tempDir, "my-project")
+	runBtSuccess(t, tempDir, "init", projectDir)
+
+	runBtSuccess(t, projectDir, "post-create", "add", "command", "echo a > a.txt", "--name", "a", "--parallel")
+	runBtSuccess(t, projectDir, "post-create", "add", "command", "exit 1", "--name", "broken", "--parallel")
+	runBtSuccess(t, projectDir, "post-create", "add", "command", "cat a.txt > after-a.txt", "--depends-on", "a")
+	runBtSuccess(t, projectDir, "post-create", "add", "command", "touch after-broken.txt", "--depends-on", "broken")
+
+	t.Run("options are stored and listed", func(t *testing.T) {
+		bareDir := filepath.Join(projectDir, ".git")
+		values := strings.Join(getGitConfigAll(t, bareDir, "baretree.postcreate"), "\n")
+		assertOutputContains(t, values, "echo a > a.txt:command;name=a;parallel")
+		assertOutputContains(t, values, "touch after-broken.txt:command;depends_on=broken")
+
+		stdout := runBtSuccess(t, projectDir, "post-create", "list")
+		assertOutputContains(t, stdout, "(name=a, parallel)")
+		assertOutputContains(t, stdout, "(depends-on=broken)")
+	})
+
+	t.Run("unknown dependency is rejected", func(t *testing.T) {
+		_, stderr := runBtFailure(t, projectDir, "post-create", "add", "command", "true", "--depends-on", "missing")
+		assertOutputContains(t, stderr, "unknown command")
+	})
+
+	t.Run("dependencies are respected on creation", func(t *testing.T) {
+		stdout := runBtSuccess(t, projectDir, "add", "-b", "feature/parallel")
+		featureDir := filepath.Join(projectDir, "feature", "parallel")
+
+		assertFileContent(t, filepath.Join(featureDir, "after-a.txt"), "a\n")
+		assertFileNotExists(t, filepath.Join(featureDir, "after-broken.txt"))
+		assertOutputContains(t, stdout, "Post-create summary:")
+		assertOutputContains(t, stdout, `skipped (dependency "broken" failed)`)
+	})
+
+	t.Run("depended-on command cannot be removed", func(t *testing.T) {
+		runBtFailure(t, projectDir, "post-create", "remove", "exit 1")
+	})
+}
+
+func TestPostCreateCommandRequired(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "postcreate-required")
+	projectDir := filepath.Join(tempDir, "my-project")
+	runBtSuccess(t, tempDir, "init", projectDir)
+
+	runBtSuccess(t, projectDir, "post-create", "add", "command", `[ "$BT_BRANCH" != "feature/broken" ] && [ "$BT_BRANCH" != "existing" ]`,
+		"--name", "check", "--timeout", "10s", "--retries", "1", "--required")
+
+	t.Run("options are stored and listed", func(t *testing.T) {
+		stdout := runBtSuccess(t, projectDir, "post-create", "list")
+		assertOutputContains(t, stdout, "(name=check, timeout=10s, retries=1, required)")
+	})
+
+	t.Run("invalid timeout is rejected", func(t *testing.T) {
+		_, stderr := runBtFailure(t, projectDir, "post-create", "add", "command", "true", "--timeout", "soon")
+		assertOutputContains(t, stderr, "invalid timeout")
+	})
+
+	t.Run("failure rolls back worktree and new branch", func(t *testing.T) {
+		stdout, stderr := runBtFailure(t, projectDir, "add", "-b", "feature/broken")
+		assertOutputContains(t, stdout, "rolling back")
+		assertOutputContains(t, stderr, "required post-create command failed")
+
+		assertFileNotExists(t, filepath.Join(projectDir, "feature", "broken"))
+		branches := runGitSuccess(t, filepath.Join(projectDir, ".git"), "branch", "--list", "feature/broken")
+		if strings.TrimSpace(branches) != "" {
+			t.Errorf("branch feature/broken should be deleted, got %q", branches)
+		}
+	})
+
+	t.Run("failure keeps existing branch", func(t *testing.T) {
+		runGitSuccess(t, filepath.Join(projectDir, ".git"), "branch", "existing", "main")
+		runBtFailure(t, projectDir, "add", "existing")
+
+		assertFileNotExists(t, filepath.Join(projectDir, "existing"))
+		branches := runGitSuccess(t, filepath.Join(projectDir, ".git"), "branch", "--list", "existing")
+		if strings.TrimSpace(branches) == "" {
+			t.Error("existing branch should not be deleted")
+		}
+	})
+
+	t.Run("success keeps worktree", func(t *testing.T) {
+		runBtSuccess(t, projectDir, "add", "-b", "feature/ok")
+		assertFileExists(t, filepath.Join(projectDir, "feature", "ok"))
+	})
+}
+
 // setGitConfig sets a git config value in the bare repository
 func setGitConfig(t *testing.T, bareDir, key, value string) {
 	t.Helper()
@@ -3,6 +3,7 @@ package e2e
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -125,3 +126,65 @@ func TestPostCreateFileCopy(t *testing.T) {
 		}
 	})
 }
+
+// TestPostCreateFileTemplate tests rendering template type post-create files into each worktree
+func TestPostCreateFileTemplate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "postcreate-template")
+	projectDir := filepath.Join(tempDir, "my-project")
+	runBtSuccess(t, tempDir, "init", projectDir)
+	mainDir := filepath.Join(projectDir, "main")
+
+	t.Run("invalid template is rejected", func(t *testing.T) {
+		writeFile(t, filepath.Join(mainDir, "broken.env"), "PORT={{.Prot}}\n")
+		_, stderr := runBtFailure(t, projectDir, "post-create", "add", "template", "broken.env")
+		assertOutputContains(t, stderr, "invalid template")
+		assertFileExists(t, filepath.Join(mainDir, "broken.env"))
+	})
+
+	writeFile(t, filepath.Join(mainDir, ".env"), "PORT={{.Port}}\nCOMPOSE_PROJECT_NAME={{.RepoName}}-{{.Slug}}\n")
+	runBtSuccess(t, projectDir, "post-create", "add", "template", ".env")
+
+	t.Run("template is moved to .shared and rendered into existing worktrees", func(t *testing.T) {
+		template, err := os.ReadFile(filepath.Join(projectDir, ".shared", ".env"))
+		if err != nil {
+			t.Fatalf("template should be moved to .shared: %v", err)
+		}
+		assertOutputContains(t, string(template), "{{.Port}}")
+
+		content, err := os.ReadFile(filepath.Join(mainDir, ".env"))
+		if err != nil {
+			t.Fatalf("failed to read rendered .env: %v", err)
+		}
+		assertOutputContains(t, string(content), "COMPOSE_PROJECT_NAME=my-project-main\n")
+		assertOutputNotContains(t, string(content), "{{")
+	})
+
+	t.Run("each new worktree gets its own values", func(t *testing.T) {
+		runBtSuccess(t, projectDir, "add", "-b", "feature/a")
+		runBtSuccess(t, projectDir, "add", "-b", "feature/b")
+
+		envA, err := os.ReadFile(filepath.Join(projectDir, "feature", "a", ".env"))
+		if err != nil {
+			t.Fatalf("failed to read feature/a .env: %v", err)
+		}
+		envB, err := os.ReadFile(filepath.Join(projectDir, "feature", "b", ".env"))
+		if err != nil {
+			t.Fatalf("failed to read feature/b .env: %v", err)
+		}
+
+		assertOutputContains(t, string(envA), "COMPOSE_PROJECT_NAME=my-project-feature-a\n")
+		assertOutputContains(t, string(envB), "COMPOSE_PROJECT_NAME=my-project-feature-b\n")
+		if strings.SplitN(string(envA), "\n", 2)[0] == strings.SplitN(string(envB), "\n", 2)[0] {
+			t.Errorf("expected distinct ports, got %q and %q", envA, envB)
+		}
+	})
+
+	t.Run("list shows template type", func(t *testing.T) {
+		stdout := runBtSuccess(t, projectDir, "post-create", "list")
+		assertOutputContains(t, stdout, "[template]")
+	})
+}
@@ -0,0 +1,68 @@
+package e2e
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+)
+
+// TestListAllRepos tests listing worktrees across all repositories under the root
+func TestListAllRepos(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "list-all-repos")
+	baretreeRoot := filepath.Join(tempDir, "root")
+	env := map[string]string{"BARETREE_ROOT": baretreeRoot}
+
+	alphaDir := filepath.Join(baretreeRoot, "example.com", "user", "alpha")
+	betaDir := filepath.Join(baretreeRoot, "example.com", "user", "beta")
+	runBtSuccess(t, tempDir, "init", alphaDir)
+	runBtSuccess(t, tempDir, "init", betaDir)
+	runBtSuccess(t, alphaDir, "add", "-b", "feature/login")
+	writeFile(t, filepath.Join(betaDir, "main", "wip.txt"), "wip")
+
+	t.Run("table shows worktrees of every repository", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "ls", "--all-repos")
+		if err != nil {
+			t.Fatalf("bt ls --all-repos failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertOutputContains(t, stdout, "REPOSITORY")
+		assertOutputContains(t, stdout, "example.com/user/alpha")
+		assertOutputContains(t, stdout, "feature/login")
+		assertOutputContains(t, stdout, "example.com/user/beta")
+		assertOutputContains(t, stdout, "dirty")
+	})
+
+	t.Run("json output", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "ls", "--all-repos", "--json")
+		if err != nil {
+			t.Fatalf("bt ls --all-repos --json failed: %v\nstderr: %s", err, stderr)
+		}
+
+		var worktrees []struct {
+			Repository string `json:"repository"`
+			Branch     string `json:"branch"`
+			Dirty      bool   `json:"dirty"`
+			LastCommit string `json:"last_commit"`
+		}
+		if err := json.Unmarshal([]byte(stdout), &worktrees); err != nil {
+			t.Fatalf("invalid JSON output: %v\n%s", err, stdout)
+		}
+		if len(worktrees) != 3 {
+			t.Fatalf("expected 3 worktrees, got %d\n%s", len(worktrees), stdout)
+		}
+
+		dirty := make(map[string]bool)
+		for _, wt := range worktrees {
+			dirty[wt.Repository+":"+wt.Branch] = wt.Dirty
+		}
+		if d, ok := dirty["example.com/user/beta:main"]; !ok || !d {
+			t.Errorf("expected beta main to be dirty, got %v", worktrees)
+		}
+		if d, ok := dirty["example.com/user/alpha:feature/login"]; !ok || d {
+			t.Errorf("expected alpha feature/login to be clean, got %v", worktrees)
+		}
+	})
+}
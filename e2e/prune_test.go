@@ -0,0 +1,130 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPrune(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "prune")
+	projectDir := filepath.Join(tempDir, "my-project")
+	runBtSuccess(t, tempDir, "init", projectDir)
+	mainDir := filepath.Join(projectDir, "main")
+	bareDir := filepath.Join(projectDir, ".git")
+
+	// feature/done: committed and merged into main
+	runBtSuccess(t, projectDir, "add", "-b", "feature/done")
+	doneDir := filepath.Join(projectDir, "feature", "done")
+	runGitSuccess(t, doneDir, "commit", "--allow-empty", "-m", "done")
+	runGitSuccess(t, mainDir, "merge", "--ff-only", "feature/done")
+
+	// feature/wip: unmerged work
+	runBtSuccess(t, projectDir, "add", "-b", "feature/wip")
+	runGitSuccess(t, filepath.Join(projectDir, "feature", "wip"), "commit", "--allow-empty", "-m", "wip")
+
+	// feature/new: no commits of its own yet
+	runBtSuccess(t, projectDir, "add", "-b", "feature/new")
+
+	// old-branch: merged branch without a worktree
+	runGitSuccess(t, bareDir, "branch", "old-branch", "main~1")
+
+	t.Run("dry run lists candidates with reasons", func(t *testing.T) {
+		stdout := runBtSuccess(t, projectDir, "prune", "--dry-run")
+		assertOutputContains(t, stdout, "feature/done")
+		assertOutputContains(t, stdout, "merged")
+		assertOutputContains(t, stdout, "Dry run")
+		assertOutputNotContains(t, stdout, "feature/wip")
+		assertOutputNotContains(t, stdout, "feature/new")
+		assertFileExists(t, doneDir)
+	})
+
+	t.Run("empty selection cancels", func(t *testing.T) {
+		stdout, _, err := runBtWithInput(t, projectDir, "\n", "prune", "--no-fetch")
+		if err != nil {
+			t.Fatalf("bt prune failed: %v", err)
+		}
+		assertOutputContains(t, stdout, "Cancelled")
+		assertFileExists(t, doneDir)
+	})
+
+	t.Run("interactive selection removes only selected entries", func(t *testing.T) {
+		// Candidates are sorted by branch: 1 feature/done, 2 old-branch
+		stdout, _, err := runBtWithInput(t, projectDir, "2\n", "prune", "--no-fetch")
+		if err != nil {
+			t.Fatalf("bt prune failed: %v\n%s", err, stdout)
+		}
+		assertOutputContains(t, stdout, "Branch 'old-branch' deleted")
+		assertFileExists(t, doneDir)
+	})
+
+	t.Run("prune removes worktrees and branches", func(t *testing.T) {
+		stdout := runBtSuccess(t, projectDir, "prune", "--yes")
+		assertOutputContains(t, stdout, "Worktree removed")
+		assertFileNotExists(t, doneDir)
+		assertFileExists(t, filepath.Join(projectDir, "feature", "wip"))
+		assertFileExists(t, filepath.Join(projectDir, "feature", "new"))
+
+		branches := runGitSuccess(t, bareDir, "branch", "--list")
+		if strings.Contains(branches, "feature/done") {
+			t.Errorf("branch feature/done should be deleted, got:\n%s", branches)
+		}
+		if !strings.Contains(branches, "feature/wip") || !strings.Contains(branches, "main") {
+			t.Errorf("unrelated branches should be kept, got:\n%s", branches)
+		}
+	})
+
+	t.Run("nothing left to prune", func(t *testing.T) {
+		stdout := runBtSuccess(t, projectDir, "prune")
+		assertOutputContains(t, stdout, "Nothing to prune")
+	})
+}
+
+// TestPruneBrokenWorktrees tests that pruning a broken worktree leaves other broken worktrees
+// and branches alone
+func TestPruneBrokenWorktrees(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "prune-broken")
+	projectDir := filepath.Join(tempDir, "my-project")
+	runBtSuccess(t, tempDir, "init", projectDir)
+	bareDir := filepath.Join(projectDir, ".git")
+
+	// A detached worktree whose administrative directory is named like the branch "keep"
+	detachedDir := filepath.Join(projectDir, "tmp", "keep")
+	runGitSuccess(t, bareDir, "worktree", "add", "--detach", detachedDir, "main")
+	runBtSuccess(t, projectDir, "add", "-b", "keep")
+
+	// A moved worktree that 'bt repair' could still fix
+	runBtSuccess(t, projectDir, "add", "-b", "feature/moved")
+	if err := os.Rename(filepath.Join(projectDir, "feature", "moved"), filepath.Join(tempDir, "moved")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(detachedDir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Candidates are sorted by branch: 1 (detached), 2 feature/moved
+	stdout, _, err := runBtWithInput(t, projectDir, "1\n", "prune", "--no-fetch", "--force")
+	if err != nil {
+		t.Fatalf("bt prune failed: %v\n%s", err, stdout)
+	}
+	assertOutputContains(t, stdout, "(detached)")
+	assertOutputContains(t, stdout, "Broken worktree removed")
+	assertOutputNotContains(t, stdout, "Branch 'keep' deleted")
+
+	assertFileNotExists(t, filepath.Join(bareDir, "worktrees", "keep"))
+	assertFileExists(t, filepath.Join(bareDir, "worktrees", "moved"))
+	assertFileExists(t, filepath.Join(projectDir, "keep"))
+	branches := runGitSuccess(t, bareDir, "branch", "--list")
+	if !strings.Contains(branches, "keep") || !strings.Contains(branches, "feature/moved") {
+		t.Errorf("branches keep and feature/moved should be kept, got:\n%s", branches)
+	}
+}
@@ -0,0 +1,94 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRemoveSafety tests that bt rm and bt repo rm refuse to lose work that exists nowhere else
+func TestRemoveSafety(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "remove-safety")
+	root := filepath.Join(tempDir, "root")
+	env := map[string]string{
+		"BARETREE_ROOT":  root,
+		"XDG_CACHE_HOME": filepath.Join(tempDir, "cache"),
+	}
+	repoDir := filepath.Join(root, "github.com", "user", "project")
+	if stdout, stderr, err := runBtWithEnv(t, tempDir, env, "init", repoDir); err != nil {
+		t.Fatalf("bt init failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+
+	t.Run("rm removes a worktree without unsaved work", func(t *testing.T) {
+		runBtSuccess(t, repoDir, "add", "-b", "feature/clean")
+		runBtSuccess(t, repoDir, "rm", "feature/clean", "--with-branch")
+		assertFileNotExists(t, filepath.Join(repoDir, "feature", "clean"))
+	})
+
+	t.Run("rm refuses a worktree with unsaved work", func(t *testing.T) {
+		runBtSuccess(t, repoDir, "add", "-b", "feature/work")
+		wt := filepath.Join(repoDir, "feature", "work")
+		runGitSuccess(t, wt, "commit", "--allow-empty", "-m", "local only")
+		if err := os.WriteFile(filepath.Join(wt, "notes.txt"), []byte("notes"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		stdout, stderr := runBtFailure(t, repoDir, "rm", "feature/work")
+		assertOutputContains(t, stdout, "1 untracked file(s)")
+		assertOutputContains(t, stdout, "notes.txt")
+		assertOutputContains(t, stdout, "local only")
+		assertOutputContains(t, stderr, "refusing to remove worktree 'feature/work'")
+		assertFileExists(t, wt)
+	})
+
+	t.Run("rm --force removes it anyway", func(t *testing.T) {
+		stdout := runBtSuccess(t, repoDir, "rm", "feature/work", "--force")
+		assertOutputContains(t, stdout, "Warning: removing worktree 'feature/work'")
+		assertFileNotExists(t, filepath.Join(repoDir, "feature", "work"))
+	})
+
+	t.Run("repo rm refuses a repository without remotes", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "repo", "rm", "project")
+		if err == nil {
+			t.Fatalf("bt repo rm should have failed\nstdout: %s", stdout)
+		}
+		assertOutputContains(t, stdout, "No remotes configured")
+		assertOutputContains(t, stdout, "feature/work")
+		assertOutputContains(t, stderr, "refusing to remove repository")
+		assertFileExists(t, repoDir)
+	})
+
+	t.Run("repo rm --force still asks for confirmation", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "repo", "rm", "project", "--force")
+		if err != nil {
+			t.Fatalf("bt repo rm --force failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertOutputContains(t, stdout, "Warning: removing repository github.com/user/project")
+		assertOutputContains(t, stdout, "Cancelled.")
+		assertFileExists(t, repoDir)
+	})
+
+	t.Run("repo rm --force --yes removes it anyway", func(t *testing.T) {
+		if stdout, stderr, err := runBtWithEnv(t, tempDir, env, "repo", "rm", "project", "--force", "--yes"); err != nil {
+			t.Fatalf("bt repo rm --force --yes failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertFileNotExists(t, repoDir)
+	})
+
+	t.Run("repo rm only warns about a clean repository without remotes", func(t *testing.T) {
+		localDir := filepath.Join(root, "github.com", "user", "local")
+		if stdout, stderr, err := runBtWithEnv(t, tempDir, env, "init", localDir); err != nil {
+			t.Fatalf("bt init failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "repo", "rm", "local", "--yes")
+		if err != nil {
+			t.Fatalf("bt repo rm --yes failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertOutputContains(t, stdout, "has no remotes")
+		assertFileNotExists(t, localDir)
+	})
+}
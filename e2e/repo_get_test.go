@@ -0,0 +1,156 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRepoGetHostSettings tests that bt get builds clone URLs from host and protocol settings
+func TestRepoGetHostSettings(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "repo-get")
+	baretreeRoot := filepath.Join(tempDir, "root")
+
+	// Local "remotes" that the hosts below are redirected to
+	upstreamDir := filepath.Join(tempDir, "upstream")
+	runBtSuccess(t, tempDir, "init", upstreamDir)
+	remotesDir := filepath.Join(tempDir, "remotes")
+	runGitSuccess(t, tempDir, "clone", "--bare", filepath.Join(upstreamDir, ".git"), filepath.Join(remotesDir, "team", "app.git"))
+	runGitSuccess(t, tempDir, "clone", "--bare", filepath.Join(upstreamDir, ".git"), filepath.Join(remotesDir, "alice", "tool.git"))
+
+	remotesURL := "file://" + filepath.ToSlash(remotesDir) + "/"
+	gitconfig := filepath.Join(tempDir, "gitconfig")
+	content := `[baretree]
+	user = alice
+	host = gitlab.example.com
+	protocol = https
+[baretree "host.github.com"]
+	protocol = ssh
+[url "` + remotesURL + `"]
+	insteadOf = https://gitlab.example.com/
+	insteadOf = https://other.example.com/
+	insteadOf = git@github.com:
+`
+	if err := os.WriteFile(gitconfig, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"BARETREE_ROOT":     baretreeRoot,
+		"GIT_CONFIG_GLOBAL": gitconfig,
+	}
+
+	t.Run("short path uses default host and protocol", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "get", "team/app")
+		if err != nil {
+			t.Fatalf("bt get failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertOutputContains(t, stdout, "Cloning https://gitlab.example.com/team/app.git")
+		assertFileExists(t, filepath.Join(baretreeRoot, "gitlab.example.com", "team", "app", "main"))
+	})
+
+	t.Run("per-host protocol overrides default", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "get", "github.com/alice/tool")
+		if err != nil {
+			t.Fatalf("bt get failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertOutputContains(t, stdout, "Cloning git@github.com:alice/tool.git")
+		assertFileExists(t, filepath.Join(baretreeRoot, "github.com", "alice", "tool", "main"))
+	})
+
+	t.Run("full URL is kept as given", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "get", "https://other.example.com/team/app.git")
+		if err != nil {
+			t.Fatalf("bt get failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertOutputContains(t, stdout, "Cloning https://other.example.com/team/app.git")
+		assertFileExists(t, filepath.Join(baretreeRoot, "other.example.com", "team", "app", "main"))
+	})
+}
+
+// TestRepoPathLayout tests that bt get and bt migrate -m place repositories with nested
+// namespaces, ports and local remotes at the same paths under the root
+func TestRepoPathLayout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "repo-layout")
+	baretreeRoot := filepath.Join(tempDir, "root")
+
+	upstreamDir := filepath.Join(tempDir, "upstream")
+	runBtSuccess(t, tempDir, "init", upstreamDir)
+	remotesDir := filepath.Join(tempDir, "remotes")
+	remoteRepo := filepath.Join(remotesDir, "org", "team", "app.git")
+	runGitSuccess(t, tempDir, "clone", "--bare", filepath.Join(upstreamDir, ".git"), remoteRepo)
+
+	gitconfig := filepath.Join(tempDir, "gitconfig")
+	content := `[baretree]
+	protocol = https
+[url "file://` + filepath.ToSlash(remotesDir) + `/"]
+	insteadOf = https://gitlab.example.com/
+`
+	if err := os.WriteFile(gitconfig, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	env := map[string]string{
+		"BARETREE_ROOT":     baretreeRoot,
+		"GIT_CONFIG_GLOBAL": gitconfig,
+	}
+
+	// local/<absolute path of the remote without .git>
+	localLayout := filepath.Join(baretreeRoot, "local", strings.TrimPrefix(filepath.ToSlash(remotesDir), "/"), "org", "team", "app")
+
+	t.Run("nested namespace is kept", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "get", "gitlab.example.com/org/team/app")
+		if err != nil {
+			t.Fatalf("bt get failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertFileExists(t, filepath.Join(baretreeRoot, "gitlab.example.com", "org", "team", "app", "main"))
+	})
+
+	t.Run("file URL is placed under local", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "get", "file://"+filepath.ToSlash(remoteRepo))
+		if err != nil {
+			t.Fatalf("bt get failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertFileExists(t, filepath.Join(localLayout, "main"))
+	})
+
+	t.Run("local path maps to the same directory as file URL", func(t *testing.T) {
+		_, stderr, err := runBtWithEnv(t, tempDir, env, "get", remoteRepo)
+		if err == nil {
+			t.Fatal("expected bt get to fail for an existing repository")
+		}
+		assertOutputContains(t, stderr, "repository already exists: "+localLayout)
+	})
+
+	t.Run("migrate -m uses the same layout for ssh URLs with port", func(t *testing.T) {
+		repoDir := filepath.Join(tempDir, "ssh-source")
+		setupGitRepoWithRemote(t, repoDir, "ssh://git@git.example.com:2222/org/team/tool.git")
+
+		stdout, stderr, err := runBtWithEnv(t, repoDir, env, "repo", "migrate", ".", "-m")
+		if err != nil {
+			t.Fatalf("migrate failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertFileExists(t, filepath.Join(baretreeRoot, "git.example.com", "org", "team", "tool", ".git"))
+	})
+
+	t.Run("migrate -m resolves relative local remotes", func(t *testing.T) {
+		repoDir := filepath.Join(tempDir, "local-source")
+		setupGitRepoWithRemote(t, repoDir, "../remotes/org/team/app.git")
+		if err := os.RemoveAll(localLayout); err != nil {
+			t.Fatal(err)
+		}
+
+		stdout, stderr, err := runBtWithEnv(t, repoDir, env, "repo", "migrate", ".", "-m")
+		if err != nil {
+			t.Fatalf("migrate failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertFileExists(t, filepath.Join(localLayout, ".git"))
+	})
+}
@@ -0,0 +1,77 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRepoIndex tests that repository lookups use the cached index and stay in sync with the roots
+func TestRepoIndex(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "repo-index")
+	root := filepath.Join(tempDir, "root")
+	cacheDir := filepath.Join(tempDir, "cache")
+	indexFile := filepath.Join(cacheDir, "baretree", "repos.json")
+	env := map[string]string{"BARETREE_ROOT": root, "XDG_CACHE_HOME": cacheDir}
+
+	bt := func(t *testing.T, args ...string) string {
+		t.Helper()
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, args...)
+		if err != nil {
+			t.Fatalf("bt %v failed: %v\nstdout: %s\nstderr: %s", args, err, stdout, stderr)
+		}
+		return stdout
+	}
+
+	bt(t, "init", filepath.Join(root, "github.com", "user", "alpha"))
+
+	t.Run("listing writes the index", func(t *testing.T) {
+		stdout := bt(t, "repos")
+		assertOutputContains(t, stdout, "github.com/user/alpha")
+		assertFileExists(t, indexFile)
+	})
+
+	t.Run("init updates the index", func(t *testing.T) {
+		bt(t, "init", filepath.Join(root, "gitlab.com", "group", "beta"))
+		stdout := bt(t, "repos")
+		assertOutputContains(t, stdout, "gitlab.com/group/beta")
+	})
+
+	t.Run("repositories created outside bt are picked up", func(t *testing.T) {
+		external := filepath.Join(root, "github.com", "user", "gamma")
+		runGitSuccess(t, tempDir, "init", external)
+		stdout := bt(t, "repos")
+		assertOutputContains(t, stdout, "github.com/user/gamma")
+
+		stdout = bt(t, "go", "gamma")
+		assertOutputContains(t, stdout, external)
+	})
+
+	t.Run("repo remove updates the index", func(t *testing.T) {
+		bt(t, "repo", "remove", "gitlab.com/group/beta", "--yes")
+		stdout := bt(t, "repos")
+		assertOutputNotContains(t, stdout, "beta")
+	})
+
+	t.Run("corrupt index falls back to a full scan", func(t *testing.T) {
+		if err := os.WriteFile(indexFile, []byte("{broken"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		stdout := bt(t, "repos")
+		assertOutputContains(t, stdout, "github.com/user/alpha")
+		assertOutputContains(t, stdout, "github.com/user/gamma")
+	})
+
+	t.Run("reindex rebuilds the index", func(t *testing.T) {
+		if err := os.Remove(indexFile); err != nil {
+			t.Fatal(err)
+		}
+		stdout := bt(t, "repo", "reindex")
+		assertOutputContains(t, stdout, "Indexed 2 repositories")
+		assertFileExists(t, indexFile)
+	})
+}
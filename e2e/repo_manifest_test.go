@@ -0,0 +1,96 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRepoManifest tests exporting all repositories to a manifest and restoring them under another root
+func TestRepoManifest(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "repo-manifest")
+	oldRoot := filepath.Join(tempDir, "old-root")
+	newRoot := filepath.Join(tempDir, "new-root")
+
+	// Upstream that the managed repository pushes to
+	upstream := filepath.Join(tempDir, "upstream.git")
+	runGitSuccess(t, tempDir, "init", "--bare", "-b", "main", upstream)
+
+	appDir := filepath.Join(oldRoot, "example.com", "user", "app")
+	runBtSuccess(t, tempDir, "init", appDir)
+	runGitSuccess(t, filepath.Join(appDir, ".git"), "remote", "add", "origin", upstream)
+	runGitSuccess(t, filepath.Join(appDir, "main"), "push", "origin", "main")
+	runBtSuccess(t, appDir, "add", "-b", "feature/x")
+	runGitSuccess(t, filepath.Join(appDir, "feature", "x"), "push", "origin", "feature/x")
+	runBtSuccess(t, appDir, "post-create", "add", "command", "touch created.txt")
+
+	manifestFile := filepath.Join(tempDir, "manifest.toml")
+
+	t.Run("export writes repositories with remotes, worktrees and config", func(t *testing.T) {
+		env := map[string]string{"BARETREE_ROOT": oldRoot}
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "repo", "manifest", "export", "-o", manifestFile)
+		if err != nil {
+			t.Fatalf("export failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertOutputContains(t, stdout, "Exported 1 repositories")
+
+		data, err := os.ReadFile(manifestFile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		content := string(data)
+		assertOutputContains(t, content, `path = "example.com/user/app"`)
+		assertOutputContains(t, content, upstream)
+		assertOutputContains(t, content, `"feature/x"`)
+		assertOutputContains(t, content, "touch created.txt")
+	})
+
+	t.Run("json export", func(t *testing.T) {
+		env := map[string]string{"BARETREE_ROOT": oldRoot}
+		stdout, _, err := runBtWithEnv(t, tempDir, env, "repo", "manifest", "export", "--format", "json")
+		if err != nil {
+			t.Fatalf("export failed: %v", err)
+		}
+		assertOutputContains(t, stdout, `"path": "example.com/user/app"`)
+	})
+
+	env := map[string]string{"BARETREE_ROOT": newRoot}
+	newAppDir := filepath.Join(newRoot, "example.com", "user", "app")
+
+	t.Run("dry run changes nothing", func(t *testing.T) {
+		stdout, _, err := runBtWithEnv(t, tempDir, env, "repo", "manifest", "apply", manifestFile, "--dry-run")
+		if err != nil {
+			t.Fatalf("apply --dry-run failed: %v\n%s", err, stdout)
+		}
+		assertOutputContains(t, stdout, "would clone")
+		assertFileNotExists(t, newAppDir)
+	})
+
+	t.Run("apply clones repositories, imports config and creates worktrees", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "repo", "manifest", "apply", manifestFile)
+		if err != nil {
+			t.Fatalf("apply failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertFileExists(t, filepath.Join(newAppDir, "main"))
+		assertFileExists(t, filepath.Join(newAppDir, "feature", "x"))
+		// Post-create command from the imported config ran in the new worktree
+		assertFileExists(t, filepath.Join(newAppDir, "feature", "x", "created.txt"))
+	})
+
+	t.Run("apply is idempotent", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "repo", "manifest", "apply", manifestFile)
+		if err != nil {
+			t.Fatalf("apply failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertOutputContains(t, stdout, "Up to date")
+		assertOutputNotContains(t, stdout, "Cloning")
+		if strings.Count(stdout, "Worktree created") != 0 {
+			t.Errorf("no worktrees should be created on re-apply:\n%s", stdout)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package e2e
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestRepoSync tests fetching all repositories under the root concurrently
+func TestRepoSync(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "repo-sync")
+	baretreeRoot := filepath.Join(tempDir, "root")
+	env := map[string]string{"BARETREE_ROOT": baretreeRoot}
+
+	// Upstream repository that both managed repositories fetch from
+	upstreamDir := filepath.Join(tempDir, "upstream")
+	runBtSuccess(t, tempDir, "init", upstreamDir)
+
+	goodDir := filepath.Join(baretreeRoot, "example.com", "user", "good")
+	runBtSuccess(t, tempDir, "init", goodDir)
+	runGitSuccess(t, filepath.Join(goodDir, ".git"), "remote", "add", "origin", filepath.Join(upstreamDir, ".git"))
+
+	t.Run("sync succeeds for reachable remotes", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "repo", "sync", "--jobs", "2")
+		if err != nil {
+			t.Fatalf("bt repo sync failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+		}
+		assertOutputContains(t, stdout, "Syncing 1 repositories")
+		assertOutputContains(t, stdout, "✓ example.com/user/good")
+		assertOutputContains(t, stdout, "1 succeeded, 0 failed")
+	})
+
+	brokenDir := filepath.Join(baretreeRoot, "example.com", "user", "broken")
+	runBtSuccess(t, tempDir, "init", brokenDir)
+	runGitSuccess(t, filepath.Join(brokenDir, ".git"), "remote", "add", "origin", filepath.Join(tempDir, "missing.git"))
+
+	t.Run("sync reports failures in summary", func(t *testing.T) {
+		stdout, stderr, err := runBtWithEnv(t, tempDir, env, "repo", "sync")
+		if err == nil {
+			t.Fatalf("expected bt repo sync to fail\nstdout: %s", stdout)
+		}
+		assertOutputContains(t, stdout, "✗ example.com/user/broken")
+		assertOutputContains(t, stdout, "Summary:")
+		assertOutputContains(t, stdout, "1 succeeded, 1 failed")
+		assertOutputContains(t, stderr, "failed to sync 1 repository(ies)")
+	})
+
+	t.Run("query limits synced repositories", func(t *testing.T) {
+		stdout, _, err := runBtWithEnv(t, tempDir, env, "repo", "sync", "good")
+		if err != nil {
+			t.Fatalf("bt repo sync good failed: %v\nstdout: %s", err, stdout)
+		}
+		assertOutputContains(t, stdout, "Syncing 1 repositories")
+		assertOutputNotContains(t, stdout, "broken")
+	})
+}
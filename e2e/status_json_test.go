@@ -0,0 +1,112 @@
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestStatusJSON(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "status-json")
+	projectDir := filepath.Join(tempDir, "my-project")
+	runBtSuccess(t, tempDir, "init", projectDir)
+	mainDir := filepath.Join(projectDir, "main")
+	bareDir := filepath.Join(projectDir, ".git")
+
+	// feature/ok: managed worktree
+	runBtSuccess(t, projectDir, "add", "-b", "feature/ok")
+
+	// feature/moved: directory does not match the branch name
+	runBtSuccess(t, projectDir, "add", "-b", "feature/moved")
+	runGitSuccess(t, bareDir, "worktree", "move", filepath.Join(projectDir, "feature", "moved"), filepath.Join(projectDir, "elsewhere"))
+
+	// orphan: branch without a worktree
+	runGitSuccess(t, bareDir, "branch", "orphan")
+
+	// Post-create and sync-to-root entries
+	writeFile(t, filepath.Join(mainDir, ".env"), "SECRET=1")
+	runBtSuccess(t, mainDir, "post-create", "add", "symlink", ".env")
+	writeFile(t, filepath.Join(mainDir, "CLAUDE.md"), "# rules")
+	runBtSuccess(t, mainDir, "sync-to-root", "add", "CLAUDE.md")
+	if err := os.Remove(filepath.Join(projectDir, "CLAUDE.md")); err != nil {
+		t.Fatal(err)
+	}
+
+	var report struct {
+		SchemaVersion int `json:"schema_version"`
+		Repository    struct {
+			Root                 string `json:"root"`
+			DefaultBranch        string `json:"default_branch"`
+			DefaultBranchMissing bool   `json:"default_branch_missing"`
+		} `json:"repository"`
+		Worktrees []struct {
+			Branch       string   `json:"branch"`
+			RelativePath string   `json:"relative_path"`
+			Current      bool     `json:"current"`
+			Default      bool     `json:"default"`
+			State        string   `json:"state"`
+			Issues       []string `json:"issues"`
+		} `json:"worktrees"`
+		OrphanBranches []string `json:"orphan_branches"`
+		PostCreate     []struct {
+			Type    string   `json:"type"`
+			Source  string   `json:"source"`
+			Applied []string `json:"applied"`
+		} `json:"post_create"`
+		SyncToRoot []struct {
+			Source string `json:"source"`
+			State  string `json:"state"`
+		} `json:"sync_to_root"`
+	}
+
+	stdout := runBtSuccess(t, mainDir, "status", "--json")
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("failed to parse status JSON: %v\n%s", err, stdout)
+	}
+
+	if report.SchemaVersion != 1 {
+		t.Errorf("schema_version = %d, want 1", report.SchemaVersion)
+	}
+	if report.Repository.DefaultBranch != "main" || report.Repository.DefaultBranchMissing {
+		t.Errorf("unexpected repository: %+v", report.Repository)
+	}
+
+	states := make(map[string]string)
+	for _, wt := range report.Worktrees {
+		states[wt.Branch] = wt.State
+		switch wt.Branch {
+		case "main":
+			if !wt.Current || !wt.Default {
+				t.Errorf("main should be current and default: %+v", wt)
+			}
+		case "feature/moved":
+			if wt.RelativePath != "elsewhere" || len(wt.Issues) != 1 || wt.Issues[0] != "name-mismatch" {
+				t.Errorf("feature/moved should have a name mismatch: %+v", wt)
+			}
+		}
+	}
+	if states["main"] != "managed" || states["feature/ok"] != "managed" || states["feature/moved"] != "warning" {
+		t.Errorf("unexpected worktree states: %v", states)
+	}
+
+	if len(report.OrphanBranches) != 1 || report.OrphanBranches[0] != "orphan" {
+		t.Errorf("orphan_branches = %v, want [orphan]", report.OrphanBranches)
+	}
+	if len(report.PostCreate) != 1 || report.PostCreate[0].Source != ".env" || len(report.PostCreate[0].Applied) != 3 {
+		t.Errorf("unexpected post_create: %+v", report.PostCreate)
+	}
+	if len(report.SyncToRoot) != 1 || report.SyncToRoot[0].State != "not-applied" {
+		t.Errorf("unexpected sync_to_root: %+v", report.SyncToRoot)
+	}
+
+	// Human-readable output shows the same diagnosis
+	stdout = runBtSuccess(t, mainDir, "status")
+	assertOutputContains(t, stdout, "[Name mismatch]")
+	assertOutputContains(t, stdout, "[No worktree]")
+	assertOutputContains(t, stdout, "[NOT APPLIED]")
+}
@@ -0,0 +1,118 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTrash tests that bt rm and bt repo rm move what they remove into a restorable trash
+func TestTrash(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "trash")
+	root := filepath.Join(tempDir, "root")
+	env := map[string]string{
+		"BARETREE_ROOT":  root,
+		"XDG_CACHE_HOME": filepath.Join(tempDir, "cache"),
+		"XDG_STATE_HOME": filepath.Join(tempDir, "state"),
+	}
+	repoDir := filepath.Join(root, "github.com", "user", "project")
+	bt := func(t *testing.T, dir string, args ...string) string {
+		t.Helper()
+		stdout, stderr, err := runBtWithEnv(t, dir, env, args...)
+		if err != nil {
+			t.Fatalf("bt %v failed: %v\nstdout: %s\nstderr: %s", args, err, stdout, stderr)
+		}
+		return stdout
+	}
+	bt(t, tempDir, "init", repoDir)
+
+	// A managed symlink that post-create re-applies on restore
+	if err := os.WriteFile(filepath.Join(repoDir, "main", ".env"), []byte("SECRET=1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	bt(t, repoDir, "post-create", "add", "symlink", ".env")
+
+	wt := filepath.Join(repoDir, "feature", "auth")
+
+	t.Run("rm moves a worktree to the trash", func(t *testing.T) {
+		bt(t, repoDir, "add", "-b", "feature/auth")
+		runGitSuccess(t, wt, "commit", "--allow-empty", "-m", "local only")
+		if err := os.WriteFile(filepath.Join(wt, "notes.txt"), []byte("notes"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		stdout := bt(t, repoDir, "rm", "feature/auth", "--force", "--with-branch")
+		assertOutputContains(t, stdout, "Worktree moved to the trash")
+		assertOutputContains(t, stdout, "bt trash restore")
+		assertFileNotExists(t, wt)
+		if branches := runGitSuccess(t, filepath.Join(repoDir, ".git"), "branch", "--list"); strings.Contains(branches, "feature/auth") {
+			t.Fatalf("branch should be deleted:\n%s", branches)
+		}
+	})
+
+	t.Run("list shows the entry", func(t *testing.T) {
+		stdout := bt(t, tempDir, "trash", "list")
+		assertOutputContains(t, stdout, "worktree")
+		assertOutputContains(t, stdout, "feature/auth")
+		assertOutputContains(t, stdout, wt)
+	})
+
+	t.Run("restore brings back files, branch and symlinks", func(t *testing.T) {
+		stdout := bt(t, tempDir, "trash", "restore", "feature/auth")
+		assertOutputContains(t, stdout, "Branch 'feature/auth' recreated")
+		assertOutputContains(t, stdout, "Restored worktree feature/auth")
+		assertFileExists(t, filepath.Join(wt, "notes.txt"))
+		assertIsSymlink(t, filepath.Join(wt, ".env"))
+		assertOutputContains(t, runGitSuccess(t, wt, "log", "--oneline", "-1"), "local only")
+		assertOutputContains(t, runGitSuccess(t, repoDir, "worktree", "list"), wt)
+		assertOutputContains(t, bt(t, tempDir, "trash", "list"), "Trash is empty")
+	})
+
+	t.Run("rm --permanent skips the trash", func(t *testing.T) {
+		bt(t, repoDir, "rm", "feature/auth", "--force", "--permanent")
+		assertFileNotExists(t, wt)
+		assertOutputContains(t, bt(t, tempDir, "trash", "list"), "Trash is empty")
+	})
+
+	t.Run("repo rm moves a repository to the trash", func(t *testing.T) {
+		stdout := bt(t, tempDir, "repo", "rm", "project", "--force", "--yes")
+		assertOutputContains(t, stdout, "Repository moved to the trash")
+		assertFileNotExists(t, repoDir)
+
+		bt(t, tempDir, "trash", "restore", "github.com/user/project")
+		assertFileExists(t, filepath.Join(repoDir, ".shared", ".env"))
+		assertFileExists(t, filepath.Join(repoDir, "main"))
+		assertOutputContains(t, bt(t, tempDir, "repo", "list"), "github.com/user/project")
+	})
+
+	t.Run("empty --older-than keeps recent entries", func(t *testing.T) {
+		bt(t, repoDir, "add", "-b", "feature/old")
+		bt(t, repoDir, "rm", "feature/old", "--with-branch")
+
+		assertOutputContains(t, bt(t, tempDir, "trash", "empty", "--older-than", "14d"), "Deleted 0 item(s)")
+		assertOutputContains(t, bt(t, tempDir, "trash", "list"), "feature/old")
+
+		assertOutputContains(t, bt(t, tempDir, "trash", "empty"), "Deleted 1 item(s)")
+		assertOutputContains(t, bt(t, tempDir, "trash", "list"), "Trash is empty")
+	})
+
+	t.Run("restore and empty are recorded in the journal", func(t *testing.T) {
+		stdout := bt(t, tempDir, "log", "--all", "--verbose")
+		assertOutputContains(t, stdout, "bt trash restore github.com/user/project")
+		assertOutputContains(t, stdout, "created repository at "+repoDir)
+		assertOutputContains(t, stdout, "permanently deleted "+filepath.Join(repoDir, "feature", "old")+" from the trash")
+	})
+
+	t.Run("restore of an unknown entry fails", func(t *testing.T) {
+		_, stderr, err := runBtWithEnv(t, tempDir, env, "trash", "restore", "missing")
+		if err == nil {
+			t.Fatal("bt trash restore should have failed")
+		}
+		assertOutputContains(t, stderr, "trash entry not found")
+	})
+}
@@ -0,0 +1,126 @@
+package e2e
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestUndo tests that mutating commands are recorded in the journal and reverted by bt undo
+func TestUndo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "undo")
+	root := filepath.Join(tempDir, "root")
+	env := map[string]string{
+		"BARETREE_ROOT":  root,
+		"XDG_CACHE_HOME": filepath.Join(tempDir, "cache"),
+		"XDG_STATE_HOME": filepath.Join(tempDir, "state"),
+	}
+	repoDir := filepath.Join(root, "github.com", "user", "project")
+	bareDir := filepath.Join(repoDir, ".git")
+	bt := func(t *testing.T, args ...string) string {
+		t.Helper()
+		stdout, stderr, err := runBtWithEnv(t, repoDir, env, args...)
+		if err != nil {
+			t.Fatalf("bt %v failed: %v\nstdout: %s\nstderr: %s", args, err, stdout, stderr)
+		}
+		return stdout
+	}
+	if stdout, stderr, err := runBtWithEnv(t, tempDir, env, "init", repoDir); err != nil {
+		t.Fatalf("bt init failed: %v\nstdout: %s\nstderr: %s", err, stdout, stderr)
+	}
+	hasBranch := func(branch string) bool {
+		return strings.Contains(runGitSuccess(t, bareDir, "branch", "--list", branch), branch)
+	}
+
+	t.Run("init is recorded", func(t *testing.T) {
+		assertOutputContains(t, bt(t, "log"), "bt init "+repoDir)
+		stdout := bt(t, "undo", "--dry-run")
+		assertOutputContains(t, stdout, "Undoing #1: bt init")
+		assertOutputContains(t, stdout, "Would revert: created repository at "+repoDir)
+	})
+
+	t.Run("undo add removes the worktree and the new branch", func(t *testing.T) {
+		bt(t, "add", "-b", "feature/added")
+		assertOutputContains(t, bt(t, "log"), "bt add -b feature/added")
+
+		stdout := bt(t, "undo")
+		assertOutputContains(t, stdout, "Undoing #2: bt add -b feature/added")
+		assertOutputContains(t, stdout, "Undone #2")
+		assertFileNotExists(t, filepath.Join(repoDir, "feature", "added"))
+		if hasBranch("feature/added") {
+			t.Error("branch feature/added should be deleted")
+		}
+		assertOutputContains(t, bt(t, "log"), "(undone)")
+	})
+
+	t.Run("undo rename restores the branch and directory", func(t *testing.T) {
+		bt(t, "add", "-b", "feature/old")
+		bt(t, "rename", "feature/old", "feature/new")
+		assertFileExists(t, filepath.Join(repoDir, "feature", "new"))
+
+		stdout := bt(t, "undo", "--dry-run")
+		assertOutputContains(t, stdout, "Would revert: renamed branch feature/old -> feature/new")
+		assertOutputContains(t, stdout, "Dry run")
+		assertFileExists(t, filepath.Join(repoDir, "feature", "new"))
+
+		bt(t, "undo")
+		assertFileExists(t, filepath.Join(repoDir, "feature", "old"))
+		assertFileNotExists(t, filepath.Join(repoDir, "feature", "new"))
+		if !hasBranch("feature/old") || hasBranch("feature/new") {
+			t.Error("branch should be renamed back to feature/old")
+		}
+	})
+
+	t.Run("undo repair --all moves directories back", func(t *testing.T) {
+		runGitSuccess(t, bareDir, "branch", "-m", "feature/old", "feature/fixed")
+		bt(t, "repair", "--all")
+		assertFileExists(t, filepath.Join(repoDir, "feature", "fixed"))
+
+		bt(t, "undo")
+		assertFileExists(t, filepath.Join(repoDir, "feature", "old"))
+		assertFileNotExists(t, filepath.Join(repoDir, "feature", "fixed"))
+		runGitSuccess(t, bareDir, "branch", "-m", "feature/fixed", "feature/old")
+	})
+
+	t.Run("undo rm restores the worktree from the trash", func(t *testing.T) {
+		bt(t, "rm", "feature/old")
+		assertFileNotExists(t, filepath.Join(repoDir, "feature", "old"))
+
+		bt(t, "undo")
+		assertFileExists(t, filepath.Join(repoDir, "feature", "old"))
+		assertOutputContains(t, bt(t, "trash", "list"), "Trash is empty")
+	})
+
+	t.Run("undo refuses when a branch moved since", func(t *testing.T) {
+		bt(t, "add", "-b", "feature/busy")
+		wt := filepath.Join(repoDir, "feature", "busy")
+		runGitSuccess(t, wt, "commit", "--allow-empty", "-m", "new work")
+
+		stdout, stderr, err := runBtWithEnv(t, repoDir, env, "undo")
+		if err == nil {
+			t.Fatalf("bt undo should have failed\nstdout: %s", stdout)
+		}
+		assertOutputContains(t, stderr, "has changed since")
+		assertFileExists(t, wt)
+	})
+
+	t.Run("log --verbose shows the recorded steps", func(t *testing.T) {
+		stdout := bt(t, "log", "--verbose")
+		assertOutputContains(t, stdout, "bt rename feature/old feature/new")
+		assertOutputContains(t, stdout, "renamed branch feature/old -> feature/new")
+		assertOutputContains(t, stdout, "added worktree feature/busy")
+
+		stdout, _, err := runBtWithEnv(t, tempDir, env, "log", "--all", "-n", "1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertOutputContains(t, stdout, "bt add -b feature/busy")
+		if strings.Contains(stdout, "bt rename") {
+			t.Errorf("log -n 1 should show only the latest operation:\n%s", stdout)
+		}
+	})
+}
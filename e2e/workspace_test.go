@@ -0,0 +1,166 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWorkspace tests working on one branch across several repositories
+func TestWorkspace(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "workspace")
+	root := filepath.Join(tempDir, "root")
+	env := map[string]string{
+		"BARETREE_ROOT":  root,
+		"XDG_CACHE_HOME": filepath.Join(tempDir, "cache"),
+		"XDG_STATE_HOME": filepath.Join(tempDir, "state"),
+	}
+
+	bt := func(t *testing.T, workDir string, args ...string) string {
+		t.Helper()
+		stdout, stderr, err := runBtWithEnv(t, workDir, env, args...)
+		if err != nil {
+			t.Fatalf("bt %v failed: %v\nstdout: %s\nstderr: %s", args, err, stdout, stderr)
+		}
+		return stdout
+	}
+	btFailure := func(t *testing.T, workDir string, args ...string) (string, string) {
+		t.Helper()
+		stdout, stderr, err := runBtWithEnv(t, workDir, env, args...)
+		if err == nil {
+			t.Fatalf("bt %v should have failed\nstdout: %s", args, stdout)
+		}
+		return stdout, stderr
+	}
+
+	api := filepath.Join(root, "github.com", "org", "api")
+	web := filepath.Join(root, "github.com", "org", "web")
+	proto := filepath.Join(root, "github.com", "org", "proto")
+	for _, repo := range []string{api, web, proto} {
+		bt(t, tempDir, "init", repo)
+	}
+
+	t.Run("create makes the worktree in every repository", func(t *testing.T) {
+		stdout := bt(t, tempDir, "workspace", "create", "feature/x", "--repos", "api,web,proto")
+		assertOutputContains(t, stdout, "Workspace 'feature/x' created")
+		for _, repo := range []string{api, web, proto} {
+			wt := filepath.Join(repo, "feature", "x")
+			if !isDirectory(wt) {
+				t.Fatalf("expected worktree at %s", wt)
+			}
+			if branch := runGitSuccess(t, wt, "branch", "--show-current"); strings.TrimSpace(branch) != "feature/x" {
+				t.Errorf("%s: expected branch feature/x, got %q", wt, branch)
+			}
+		}
+
+		stdout = bt(t, tempDir, "workspace", "list")
+		assertOutputContains(t, stdout, "feature/x")
+		assertOutputContains(t, stdout, "github.com/org/web")
+	})
+
+	t.Run("create rejects an existing workspace", func(t *testing.T) {
+		_, stderr := btFailure(t, tempDir, "workspace", "create", "feature/x", "--repos", "api")
+		assertOutputContains(t, stderr, "workspace 'feature/x' already exists")
+	})
+
+	t.Run("cd outputs the worktree of a repository", func(t *testing.T) {
+		stdout := bt(t, tempDir, "workspace", "cd", "feature/x", "web")
+		if got := strings.TrimSpace(stdout); got != filepath.Join(web, "feature", "x") {
+			t.Errorf("expected web worktree, got %q", got)
+		}
+
+		// Without a repository, the current repository's worktree is chosen
+		stdout = bt(t, filepath.Join(proto, "main"), "workspace", "cd", "feature/x")
+		if got := strings.TrimSpace(stdout); got != filepath.Join(proto, "feature", "x") {
+			t.Errorf("expected proto worktree, got %q", got)
+		}
+
+		_, stderr := btFailure(t, tempDir, "workspace", "cd", "feature/x", "unknown")
+		assertOutputContains(t, stderr, "in workspace 'feature/x'")
+	})
+
+	t.Run("status reports each repository", func(t *testing.T) {
+		if err := os.WriteFile(filepath.Join(api, "feature", "x", "new.txt"), []byte("change"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		stdout := bt(t, tempDir, "workspace", "status", "feature/x")
+		assertOutputContains(t, stdout, "Workspace: feature/x (3 repositories)")
+		assertOutputContains(t, stdout, "1 changed")
+		assertOutputContains(t, stdout, "clean")
+		assertOutputNotContains(t, stdout, "missing")
+	})
+
+	t.Run("failed create rolls back the other repositories", func(t *testing.T) {
+		// A branch named "bugfix" in web makes "bugfix/y" impossible there
+		runGitSuccess(t, filepath.Join(web, "main"), "branch", "bugfix")
+
+		stdout, stderr := btFailure(t, tempDir, "workspace", "create", "bugfix/y", "--repos", "api,web,proto")
+		assertOutputContains(t, stdout, "Rolling back")
+		assertOutputContains(t, stderr, "failed to create workspace 'bugfix/y'")
+
+		assertFileNotExists(t, filepath.Join(api, "bugfix", "y"))
+		assertFileNotExists(t, filepath.Join(proto, "bugfix", "y"))
+		branches := runGitSuccess(t, filepath.Join(api, "main"), "branch", "--list", "bugfix/y")
+		if strings.TrimSpace(branches) != "" {
+			t.Errorf("expected branch bugfix/y to be deleted in api, got %q", branches)
+		}
+
+		stdout = bt(t, tempDir, "workspace", "list")
+		assertOutputNotContains(t, stdout, "bugfix/y")
+	})
+
+	t.Run("rm removes the worktrees in every repository", func(t *testing.T) {
+		// Uncommitted changes in api keep the workspace until forced
+		stdout, _ := btFailure(t, tempDir, "workspace", "rm", "feature/x", "--with-branch")
+		assertOutputContains(t, stdout, "[github.com/org/api]")
+		assertFileNotExists(t, filepath.Join(web, "feature", "x"))
+		stdout = bt(t, tempDir, "workspace", "list")
+		assertOutputContains(t, stdout, "feature/x")
+
+		stdout = bt(t, tempDir, "workspace", "rm", "feature/x", "--with-branch", "--force")
+		assertOutputContains(t, stdout, "Worktree moved to the trash")
+		assertOutputContains(t, stdout, "Workspace 'feature/x' removed")
+		for _, repo := range []string{api, web, proto} {
+			assertFileNotExists(t, filepath.Join(repo, "feature", "x"))
+		}
+		branches := runGitSuccess(t, filepath.Join(web, "main"), "branch", "--list", "feature/x")
+		if strings.TrimSpace(branches) != "" {
+			t.Errorf("expected branch feature/x to be deleted in web, got %q", branches)
+		}
+
+		stdout = bt(t, tempDir, "workspace", "list")
+		assertOutputContains(t, stdout, "No workspaces found")
+
+		stdout = bt(t, tempDir, "trash", "list")
+		for _, repo := range []string{api, web, proto} {
+			assertOutputContains(t, stdout, filepath.Join(repo, "feature", "x"))
+		}
+	})
+
+	t.Run("rm --permanent deletes the worktrees", func(t *testing.T) {
+		bt(t, tempDir, "workspace", "create", "feature/y", "--repos", "api,web")
+		stdout := bt(t, tempDir, "workspace", "rm", "feature/y", "--permanent")
+		assertOutputContains(t, stdout, "Worktree removed")
+		for _, repo := range []string{api, web} {
+			assertFileNotExists(t, filepath.Join(repo, "feature", "y"))
+		}
+		if stdout := bt(t, tempDir, "trash", "list"); strings.Contains(stdout, "feature/y") {
+			t.Errorf("worktrees removed with --permanent should not be in the trash:\n%s", stdout)
+		}
+	})
+
+	t.Run("undo re-creates the removed worktrees", func(t *testing.T) {
+		stdout := bt(t, tempDir, "undo")
+		assertOutputContains(t, stdout, "bt workspace rm feature/y --permanent")
+		for _, repo := range []string{api, web} {
+			if !isDirectory(filepath.Join(repo, "feature", "y")) {
+				t.Errorf("expected worktree at %s", filepath.Join(repo, "feature", "y"))
+			}
+		}
+	})
+}
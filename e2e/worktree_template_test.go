@@ -0,0 +1,94 @@
+package e2e
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestWorktreeTemplate tests mapping branch names to worktree directories with a naming template
+func TestWorktreeTemplate(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping e2e test in short mode")
+	}
+
+	tempDir := createTempDir(t, "worktree-template")
+	repoDir := filepath.Join(tempDir, "project")
+	runBtSuccess(t, tempDir, "init", repoDir)
+
+	t.Run("invalid template is rejected", func(t *testing.T) {
+		_, stderr := runBtFailure(t, repoDir, "config", "worktree-template", "../{{ .Branch }}")
+		assertOutputContains(t, stderr, "not a clean path inside the repository root")
+	})
+
+	stdout := runBtSuccess(t, repoDir, "config", "worktree-template", `{{ .Branch | trimPrefix "users/alice/" | replace "/" "-" | lower }}`)
+	assertOutputContains(t, stdout, "Worktree naming template set to")
+
+	branch := "users/alice/JIRA-1234-Fix"
+	worktreeDir := filepath.Join(repoDir, "jira-1234-fix")
+
+	t.Run("add uses the template", func(t *testing.T) {
+		runBtSuccess(t, repoDir, "add", "-b", branch)
+		assertFileExists(t, worktreeDir)
+		assertFileNotExists(t, filepath.Join(repoDir, "users"))
+
+		if got := strings.TrimSpace(runGitSuccess(t, worktreeDir, "branch", "--show-current")); got != branch {
+			t.Errorf("branch = %s, want %s", got, branch)
+		}
+	})
+
+	t.Run("cd resolves the branch name", func(t *testing.T) {
+		got := strings.TrimSpace(runBtSuccess(t, repoDir, "cd", branch))
+		if got != worktreeDir {
+			t.Errorf("bt cd = %s, want %s", got, worktreeDir)
+		}
+	})
+
+	t.Run("status reports no mismatch", func(t *testing.T) {
+		stdout := runBtSuccess(t, repoDir, "status")
+		assertOutputNotContains(t, stdout, "Name mismatch")
+	})
+
+	t.Run("rename moves to the templated directory", func(t *testing.T) {
+		runBtSuccess(t, repoDir, "rename", branch, "users/alice/JIRA-1234-Login")
+		assertFileNotExists(t, worktreeDir)
+		worktreeDir = filepath.Join(repoDir, "jira-1234-login")
+		assertFileExists(t, worktreeDir)
+
+		// The current worktree can be renamed too
+		runBtSuccess(t, worktreeDir, "rename", "users/alice/JIRA-1234-Fix")
+		worktreeDir = filepath.Join(repoDir, "jira-1234-fix")
+		assertFileExists(t, worktreeDir)
+	})
+
+	t.Run("changing the template reports and repairs mismatches", func(t *testing.T) {
+		stdout := runBtSuccess(t, repoDir, "config", "worktree-template", "--unset")
+		assertOutputContains(t, stdout, "jira-1234-fix -> "+filepath.Join("users", "alice", "JIRA-1234-Fix"))
+		assertOutputContains(t, stdout, "bt repair --all")
+
+		stdout = runBtSuccess(t, repoDir, "status")
+		assertOutputContains(t, stdout, "Name mismatch")
+
+		runBtSuccess(t, repoDir, "repair", "--all")
+		assertFileNotExists(t, worktreeDir)
+		assertFileExists(t, filepath.Join(repoDir, "users", "alice", "JIRA-1234-Fix"))
+
+		stdout = runBtSuccess(t, repoDir, "status")
+		assertOutputNotContains(t, stdout, "Name mismatch")
+	})
+
+	t.Run("config export and import keep the template", func(t *testing.T) {
+		runBtSuccess(t, repoDir, "config", "worktree-template", `{{ .Slug }}`)
+		exportFile := filepath.Join(tempDir, "baretree.toml")
+		runBtSuccess(t, repoDir, "config", "export", "-o", exportFile)
+
+		runBtSuccess(t, repoDir, "config", "worktree-template", "--unset")
+		stdout := runBtSuccess(t, repoDir, "config", "import", exportFile)
+		assertOutputContains(t, stdout, "worktree_template")
+
+		got := strings.TrimSpace(runBtSuccess(t, repoDir, "config", "worktree-template"))
+		if got != "{{ .Slug }}" {
+			t.Errorf("worktree template after import = %q, want %q", got, "{{ .Slug }}")
+		}
+	})
+}
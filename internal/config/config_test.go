@@ -4,6 +4,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -229,8 +231,19 @@ func TestParsePostCreateEntry(t *testing.T) {
 	}{
 		{".env:symlink", PostCreateAction{Source: ".env", Type: "symlink", Managed: false}, false},
 		{".gitignore:copy:managed", PostCreateAction{Source: ".gitignore", Type: "copy", Managed: true}, false},
+		{".env:template:managed", PostCreateAction{Source: ".env", Type: "template", Managed: true}, false},
 		{"direnv allow:command", PostCreateAction{Source: "direnv allow", Type: "command"}, false},
 		{"npm install:command", PostCreateAction{Source: "npm install", Type: "command"}, false},
+		{"echo a:b:command", PostCreateAction{Source: "echo a:b", Type: "command"}, false},
+		{"npm install:command;name=deps;parallel", PostCreateAction{Source: "npm install", Type: "command", Name: "deps", Parallel: true}, false},
+		{"npm test:command;depends_on=deps,build", PostCreateAction{Source: "npm test", Type: "command", DependsOn: []string{"deps", "build"}}, false},
+		{"npm ci:command;timeout=5m;retries=2;required", PostCreateAction{Source: "npm ci", Type: "command", Timeout: "5m", Retries: 2, Required: true}, false},
+		{"make:command;depends_on=echo a%3Ab,x%2Cy", PostCreateAction{Source: "make", Type: "command", DependsOn: []string{"echo a:b", "x,y"}}, false},
+		{"make:command;unknown=1", PostCreateAction{Source: "make", Type: "command"}, false},
+		{"npm ci:command;retries=abc", PostCreateAction{}, true},
+		{"npm ci:command;retries=-1", PostCreateAction{}, true},
+		{"npm ci:command;timeout=soon", PostCreateAction{}, true},
+		{"npm ci:command;name=50%", PostCreateAction{}, true},
 		{"invalid", PostCreateAction{}, true},
 	}
 
@@ -241,7 +254,7 @@ func TestParsePostCreateEntry(t *testing.T) {
 				t.Errorf("parsePostCreateEntry(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
 				return
 			}
-			if !tt.wantErr && result != tt.expected {
+			if !tt.wantErr && !reflect.DeepEqual(result, tt.expected) {
 				t.Errorf("parsePostCreateEntry(%q) = %v, want %v", tt.input, result, tt.expected)
 			}
 		})
@@ -256,6 +269,9 @@ func TestFormatPostCreateEntry(t *testing.T) {
 		{PostCreateAction{Source: ".env", Type: "symlink", Managed: false}, ".env:symlink"},
 		{PostCreateAction{Source: ".gitignore", Type: "copy", Managed: true}, ".gitignore:copy:managed"},
 		{PostCreateAction{Source: "direnv allow", Type: "command"}, "direnv allow:command"},
+		{PostCreateAction{Source: "npm install", Type: "command", Name: "deps", Parallel: true}, "npm install:command;name=deps;parallel"},
+		{PostCreateAction{Source: "npm test", Type: "command", DependsOn: []string{"deps", "build"}}, "npm test:command;depends_on=deps,build"},
+		{PostCreateAction{Source: "npm ci", Type: "command", Timeout: "5m", Retries: 2, Required: true}, "npm ci:command;timeout=5m;retries=2;required"},
 	}
 
 	for _, tt := range tests {
@@ -267,3 +283,166 @@ func TestFormatPostCreateEntry(t *testing.T) {
 		})
 	}
 }
+
+func TestPostCreateEntryRoundTrip(t *testing.T) {
+	tests := []PostCreateAction{
+		{Source: "echo a:b; echo c", Type: "command"},
+		{Source: "make", Type: "command", DependsOn: []string{"echo a:b", "x,y", "npm test;lint"}},
+		{Source: "make test", Type: "command", Name: "tests: 100%", Parallel: true, Timeout: "1m30s", Retries: 3, Required: true},
+		{Source: "config/.env", Type: "template", Managed: true},
+	}
+
+	for _, want := range tests {
+		entry := formatPostCreateEntry(want)
+		got, err := parsePostCreateEntry(entry)
+		if err != nil {
+			t.Errorf("parsePostCreateEntry(%q) error = %v", entry, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("round trip through %q = %+v, want %+v", entry, got, want)
+		}
+	}
+}
+
+func TestLoadConfigRejectsInvalidPostCreateEntry(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestBareRepo(t, tempDir, ".git")
+	cmd := exec.Command("git", "config", "--file", filepath.Join(tempDir, ".git", "config"), "--add", GitConfigKeyPostCreate, "npm ci:command;retries=abc")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git config failed: %v\n%s", err, output)
+	}
+
+	if _, err := LoadConfig(tempDir); err == nil || !strings.Contains(err.Error(), "retries") {
+		t.Errorf("LoadConfig() should reject invalid retries, got %v", err)
+	}
+}
+
+func TestSaveLoadHooks(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestBareRepo(t, tempDir, ".git")
+
+	cfg := DefaultConfig()
+	cfg.PreRemove = []HookAction{{Command: "docker compose down"}, {Command: "dropdb app_$BT_BRANCH"}}
+	cfg.PostRename = []HookAction{{Command: "echo renamed: done"}}
+
+	if err := SaveConfig(tempDir, cfg); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	loaded, err := LoadConfig(tempDir)
+	if err != nil {
+		t.Fatalf("failed to load saved config: %v", err)
+	}
+
+	if len(loaded.PreRemove) != 2 || loaded.PreRemove[1].Command != "dropdb app_$BT_BRANCH" {
+		t.Errorf("unexpected pre-remove hooks: %v", loaded.PreRemove)
+	}
+	if len(loaded.PostRename) != 1 || loaded.PostRename[0].Command != "echo renamed: done" {
+		t.Errorf("unexpected post-rename hooks: %v", loaded.PostRename)
+	}
+	if len(loaded.PostRemove) != 0 || len(loaded.PostCheckout) != 0 {
+		t.Errorf("expected no post-remove/post-checkout hooks, got %v / %v", loaded.PostRemove, loaded.PostCheckout)
+	}
+
+	// Export/import keeps each hook list in its own section
+	tomlContent, err := ExportConfigToTOML(loaded)
+	if err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+	imported, err := ImportConfigFromTOML(tomlContent)
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+	if len(imported.PreRemove) != 2 || len(imported.PostRename) != 1 {
+		t.Errorf("hooks not preserved through TOML:\n%s", tomlContent)
+	}
+}
+
+func TestHooksUnknownEvent(t *testing.T) {
+	cfg := DefaultConfig()
+	if cfg.Hooks("post-create") != nil {
+		t.Error("expected nil hook list for unknown event")
+	}
+	for _, event := range HookEvents {
+		if cfg.Hooks(event) == nil {
+			t.Errorf("expected hook list for event %q", event)
+		}
+	}
+}
+
+func TestSaveLoadWorktreeTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestBareRepo(t, tempDir, ".git")
+
+	cfg := DefaultConfig()
+	cfg.Repository.WorktreeTemplate = `{{ .Branch | replace "/" "-" }}`
+	if err := SaveConfig(tempDir, cfg); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	loaded, err := LoadConfig(tempDir)
+	if err != nil {
+		t.Fatalf("failed to load saved config: %v", err)
+	}
+	if loaded.Repository.WorktreeTemplate != cfg.Repository.WorktreeTemplate {
+		t.Errorf("expected worktree template %q, got %q", cfg.Repository.WorktreeTemplate, loaded.Repository.WorktreeTemplate)
+	}
+
+	tomlContent, err := ExportConfigToTOML(loaded)
+	if err != nil {
+		t.Fatalf("failed to export: %v", err)
+	}
+	imported, err := ImportConfigFromTOML(tomlContent)
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+	if imported.Repository.WorktreeTemplate != cfg.Repository.WorktreeTemplate {
+		t.Errorf("worktree template not preserved through TOML:\n%s", tomlContent)
+	}
+
+	// Saving without a template removes it
+	loaded.Repository.WorktreeTemplate = ""
+	if err := SaveConfig(tempDir, loaded); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	loaded, err = LoadConfig(tempDir)
+	if err != nil {
+		t.Fatalf("failed to load saved config: %v", err)
+	}
+	if loaded.Repository.WorktreeTemplate != "" {
+		t.Errorf("expected no worktree template, got %q", loaded.Repository.WorktreeTemplate)
+	}
+}
+
+func TestSaveLoadEditorWorkspace(t *testing.T) {
+	tempDir := t.TempDir()
+	createTestBareRepo(t, tempDir, ".git")
+
+	cfg := DefaultConfig()
+	cfg.Repository.EditorWorkspace = "vscode"
+	if err := SaveConfig(tempDir, cfg); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+
+	loaded, err := LoadConfig(tempDir)
+	if err != nil {
+		t.Fatalf("failed to load saved config: %v", err)
+	}
+	if loaded.Repository.EditorWorkspace != "vscode" {
+		t.Errorf("expected editor workspace %q, got %q", "vscode", loaded.Repository.EditorWorkspace)
+	}
+
+	// Saving without a format removes it
+	loaded.Repository.EditorWorkspace = ""
+	if err := SaveConfig(tempDir, loaded); err != nil {
+		t.Fatalf("failed to save config: %v", err)
+	}
+	loaded, err = LoadConfig(tempDir)
+	if err != nil {
+		t.Fatalf("failed to load saved config: %v", err)
+	}
+	if loaded.Repository.EditorWorkspace != "" {
+		t.Errorf("expected no editor workspace, got %q", loaded.Repository.EditorWorkspace)
+	}
+}
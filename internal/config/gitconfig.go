@@ -2,20 +2,36 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
 // Git config keys for baretree
 const (
-	GitConfigSection          = "baretree"
-	GitConfigKeyDefaultBranch = "baretree.defaultbranch"
-	GitConfigKeyPostCreate    = "baretree.postcreate"
-	GitConfigKeySyncToRoot    = "baretree.synctoroot"
+	GitConfigSection             = "baretree"
+	GitConfigKeyDefaultBranch    = "baretree.defaultbranch"
+	GitConfigKeyWorktreeTemplate = "baretree.worktreetemplate"
+	GitConfigKeyEditorWorkspace  = "baretree.editorworkspace"
+	GitConfigKeyPostCreate       = "baretree.postcreate"
+	GitConfigKeySyncToRoot       = "baretree.synctoroot"
+	GitConfigKeyPreRemove        = "baretree.preremove"
+	GitConfigKeyPostRemove       = "baretree.postremove"
+	GitConfigKeyPostRename       = "baretree.postrename"
+	GitConfigKeyPostCheckout     = "baretree.postcheckout"
 )
 
+// hookGitConfigKeys maps each hook event to its git config key
+var hookGitConfigKeys = map[string]string{
+	HookPreRemove:    GitConfigKeyPreRemove,
+	HookPostRemove:   GitConfigKeyPostRemove,
+	HookPostRename:   GitConfigKeyPostRename,
+	HookPostCheckout: GitConfigKeyPostCheckout,
+}
+
 // LoadConfigFromGit loads configuration from git-config in the bare repository
 func LoadConfigFromGit(repoRoot string) (*Config, error) {
 	// First, find the bare directory by checking common locations
@@ -36,14 +52,22 @@ func LoadConfigFromGit(repoRoot string) (*Config, error) {
 	} else {
 		cfg.Repository.DefaultBranch = "main"
 	}
+	if worktreeTemplate, err := gitConfigGet(bareDir, GitConfigKeyWorktreeTemplate); err == nil {
+		cfg.Repository.WorktreeTemplate = worktreeTemplate
+	}
+	if editorWorkspace, err := gitConfigGet(bareDir, GitConfigKeyEditorWorkspace); err == nil {
+		cfg.Repository.EditorWorkspace = editorWorkspace
+	}
 
 	// Read post-create entries
 	postCreateEntries, err := gitConfigGetAll(bareDir, GitConfigKeyPostCreate)
 	if err == nil {
 		for _, entry := range postCreateEntries {
-			if action, err := parsePostCreateEntry(entry); err == nil {
-				cfg.PostCreate = append(cfg.PostCreate, action)
+			action, err := parsePostCreateEntry(entry)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", GitConfigKeyPostCreate, err)
 			}
+			cfg.PostCreate = append(cfg.PostCreate, action)
 		}
 	}
 
@@ -57,6 +81,17 @@ func LoadConfigFromGit(repoRoot string) (*Config, error) {
 		}
 	}
 
+	// Read hook entries (one command per value)
+	for _, event := range HookEvents {
+		hookEntries, err := gitConfigGetAll(bareDir, hookGitConfigKeys[event])
+		if err == nil {
+			hooks := cfg.Hooks(event)
+			for _, entry := range hookEntries {
+				*hooks = append(*hooks, HookAction{Command: entry})
+			}
+		}
+	}
+
 	return cfg, nil
 }
 
@@ -68,6 +103,20 @@ func SaveConfigToGit(repoRoot string, cfg *Config) error {
 	if err := gitConfigSet(bareDir, GitConfigKeyDefaultBranch, cfg.Repository.DefaultBranch); err != nil {
 		return fmt.Errorf("failed to set defaultbranch: %w", err)
 	}
+	if cfg.Repository.WorktreeTemplate != "" {
+		if err := gitConfigSet(bareDir, GitConfigKeyWorktreeTemplate, cfg.Repository.WorktreeTemplate); err != nil {
+			return fmt.Errorf("failed to set worktreetemplate: %w", err)
+		}
+	} else {
+		_ = gitConfigUnset(bareDir, GitConfigKeyWorktreeTemplate)
+	}
+	if cfg.Repository.EditorWorkspace != "" {
+		if err := gitConfigSet(bareDir, GitConfigKeyEditorWorkspace, cfg.Repository.EditorWorkspace); err != nil {
+			return fmt.Errorf("failed to set editorworkspace: %w", err)
+		}
+	} else {
+		_ = gitConfigUnset(bareDir, GitConfigKeyEditorWorkspace)
+	}
 
 	// Clear existing post-create entries and add new ones
 	_ = gitConfigUnsetAll(bareDir, GitConfigKeyPostCreate)
@@ -87,6 +136,17 @@ func SaveConfigToGit(repoRoot string, cfg *Config) error {
 		}
 	}
 
+	// Clear existing hook entries and add new ones
+	for _, event := range HookEvents {
+		key := hookGitConfigKeys[event]
+		_ = gitConfigUnsetAll(bareDir, key)
+		for _, hook := range *cfg.Hooks(event) {
+			if err := gitConfigAdd(bareDir, key, hook.Command); err != nil {
+				return fmt.Errorf("failed to add %s hook: %w", event, err)
+			}
+		}
+	}
+
 	return nil
 }
 
@@ -295,7 +355,10 @@ func gitConfigUnsetAll(bareDir, key string) error {
 
 // parsePostCreateEntry parses a post-create entry from git config format
 // Format for symlink/copy: "source:type" or "source:type:managed"
-// Format for command: "command_string:command"
+// Format for command: "command_string:command", optionally followed by
+// ";"-separated options: "command_string:command;name=deps;parallel;depends_on=a,b",
+// "command_string:command;timeout=5m;retries=2;required".
+// Option values are escaped (see escapeOptionValue), so the last colon always precedes the type.
 func parsePostCreateEntry(entry string) (PostCreateAction, error) {
 	// Find the last colon to determine the type
 	// This handles commands that may contain colons
@@ -307,11 +370,15 @@ func parsePostCreateEntry(entry string) (PostCreateAction, error) {
 	suffix := entry[lastColonIdx+1:]
 
 	// Check if it's a command type
-	if suffix == "command" {
-		return PostCreateAction{
+	if suffix == "command" || strings.HasPrefix(suffix, "command;") {
+		action := PostCreateAction{
 			Source: entry[:lastColonIdx],
 			Type:   "command",
-		}, nil
+		}
+		if err := parseCommandOptions(&action, strings.Split(suffix, ";")[1:]); err != nil {
+			return PostCreateAction{}, fmt.Errorf("invalid post-create entry %q: %w", entry, err)
+		}
+		return action, nil
 	}
 
 	// Parse as symlink/copy format: "source:type" or "source:type:managed"
@@ -332,10 +399,64 @@ func parsePostCreateEntry(entry string) (PostCreateAction, error) {
 	return action, nil
 }
 
+// parseCommandOptions applies "key=value" or flag options of a command entry to action.
+// Unknown options are ignored so that newer entries can be read by older versions,
+// but invalid values of known options are rejected.
+func parseCommandOptions(action *PostCreateAction, options []string) error {
+	for _, opt := range options {
+		key, rawValue, _ := strings.Cut(opt, "=")
+		value, err := url.PathUnescape(rawValue)
+		if err != nil {
+			return fmt.Errorf("invalid %s value %q: %w", key, rawValue, err)
+		}
+		switch key {
+		case "name":
+			action.Name = value
+		case "parallel":
+			action.Parallel = true
+		case "depends_on":
+			for _, dep := range strings.Split(rawValue, ",") {
+				if dep == "" {
+					continue
+				}
+				// Each reference is escaped on its own, so commas inside it are kept
+				dep, err := url.PathUnescape(dep)
+				if err != nil {
+					return fmt.Errorf("invalid depends_on value %q: %w", rawValue, err)
+				}
+				action.DependsOn = append(action.DependsOn, dep)
+			}
+		case "timeout":
+			action.Timeout = value
+			if _, err := action.TimeoutDuration(); err != nil {
+				return err
+			}
+		case "retries":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid retries %q: must be a non-negative integer", value)
+			}
+			action.Retries = n
+		case "required":
+			action.Required = true
+		}
+	}
+	return nil
+}
+
+// optionValueEscaper escapes the characters that separate the type, options and depends_on
+// references of a post-create entry
+var optionValueEscaper = strings.NewReplacer("%", "%25", ":", "%3A", ";", "%3B", ",", "%2C")
+
+// escapeOptionValue escapes a command option value for a post-create entry
+func escapeOptionValue(value string) string {
+	return optionValueEscaper.Replace(value)
+}
+
 // formatPostCreateEntry formats a PostCreateAction for git config storage
 func formatPostCreateEntry(action PostCreateAction) string {
 	if action.Type == "command" {
-		return fmt.Sprintf("%s:command", action.Source)
+		return fmt.Sprintf("%s:command%s", action.Source, formatCommandOptions(action))
 	}
 	if action.Managed {
 		return fmt.Sprintf("%s:%s:managed", action.Source, action.Type)
@@ -343,6 +464,37 @@ func formatPostCreateEntry(action PostCreateAction) string {
 	return fmt.Sprintf("%s:%s", action.Source, action.Type)
 }
 
+// formatCommandOptions formats the options of a command entry (empty if none are set)
+func formatCommandOptions(action PostCreateAction) string {
+	var opts []string
+	if action.Name != "" {
+		opts = append(opts, "name="+escapeOptionValue(action.Name))
+	}
+	if action.Parallel {
+		opts = append(opts, "parallel")
+	}
+	if len(action.DependsOn) > 0 {
+		deps := make([]string, len(action.DependsOn))
+		for i, dep := range action.DependsOn {
+			deps[i] = escapeOptionValue(dep)
+		}
+		opts = append(opts, "depends_on="+strings.Join(deps, ","))
+	}
+	if action.Timeout != "" {
+		opts = append(opts, "timeout="+escapeOptionValue(action.Timeout))
+	}
+	if action.Retries > 0 {
+		opts = append(opts, "retries="+strconv.Itoa(action.Retries))
+	}
+	if action.Required {
+		opts = append(opts, "required")
+	}
+	if len(opts) == 0 {
+		return ""
+	}
+	return ";" + strings.Join(opts, ";")
+}
+
 // parseSyncToRootEntry parses a sync-to-root entry from git config format
 // Format: "source" or "source:target"
 func parseSyncToRootEntry(entry string) (SyncToRootAction, error) {
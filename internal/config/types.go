@@ -1,35 +1,120 @@
 package config
 
+import (
+	"fmt"
+	"time"
+)
+
 // BareDir is the fixed directory name for the bare repository.
 // This is intentionally fixed to ".git" to ensure compatibility with git submodules.
 const BareDir = ".git"
 
 // Config represents the baretree configuration.
 // Runtime storage: git-config ([baretree] section in .git/config)
-// Export/import format: TOML (for 'bt config export/import')
+// Export/import format: TOML (for 'bt config export/import'), also embedded in repository manifests (TOML or JSON)
 type Config struct {
-	Repository Repository         `toml:"repository"`
-	PostCreate []PostCreateAction `toml:"postcreate"`
-	SyncToRoot []SyncToRootAction `toml:"synctoroot"`
+	Repository   Repository         `toml:"repository" json:"repository"`
+	PostCreate   []PostCreateAction `toml:"postcreate" json:"postcreate"`
+	SyncToRoot   []SyncToRootAction `toml:"synctoroot" json:"synctoroot"`
+	PreRemove    []HookAction       `toml:"preremove" json:"preremove"`
+	PostRemove   []HookAction       `toml:"postremove" json:"postremove"`
+	PostRename   []HookAction       `toml:"postrename" json:"postrename"`
+	PostCheckout []HookAction       `toml:"postcheckout" json:"postcheckout"`
 }
 
 // Repository configuration
 type Repository struct {
-	DefaultBranch string `toml:"default_branch"`
+	DefaultBranch string `toml:"default_branch" json:"default_branch"`
+	// WorktreeTemplate maps branch names to worktree directories relative to the repository root
+	// (Go text/template, e.g. {{ .Branch | replace "/" "-" }}); empty uses the branch name as is
+	WorktreeTemplate string `toml:"worktree_template,omitempty" json:"worktree_template,omitempty"`
+	// EditorWorkspace is the editor workspace format ("vscode" or "jetbrains") kept current as worktrees
+	// are added, removed and renamed (see 'bt editor workspace --auto'); empty disables it
+	EditorWorkspace string `toml:"editor_workspace,omitempty" json:"editor_workspace,omitempty"`
 }
 
 // PostCreateAction represents an action to perform after worktree creation.
-// Type can be "symlink", "copy", or "command".
+// Type can be "symlink", "copy", "template", or "command".
+// A template is rendered with Go text/template into each worktree instead of being copied.
+//
+// Commands run one after another by default. A command with Parallel set runs
+// concurrently with adjacent parallel commands, and a command with DependsOn
+// waits only for the listed commands (referenced by name or command string).
+//
+// A command is killed after Timeout (a Go duration such as "5m"; no limit if empty)
+// and re-run up to Retries times on failure. If a Required command still fails,
+// the new worktree and its branch are rolled back.
 type PostCreateAction struct {
-	Source  string `toml:"source"`  // file path for symlink/copy, command string for command
-	Type    string `toml:"type"`    // "symlink", "copy", or "command"
-	Managed bool   `toml:"managed"` // if true, source is in .shared/ directory (symlink/copy only)
+	Source    string   `toml:"source" json:"source"`                             // file path for symlink/copy, command string for command
+	Type      string   `toml:"type" json:"type"`                                 // "symlink", "copy", "template", or "command"
+	Managed   bool     `toml:"managed" json:"managed"`                           // if true, source is in .shared/ directory (symlink/copy/template only)
+	Name      string   `toml:"name,omitempty" json:"name,omitempty"`             // optional name used in output and depends_on (command only)
+	Parallel  bool     `toml:"parallel,omitempty" json:"parallel,omitempty"`     // run concurrently with adjacent parallel commands (command only)
+	DependsOn []string `toml:"depends_on,omitempty" json:"depends_on,omitempty"` // commands that must succeed first (command only)
+	Timeout   string   `toml:"timeout,omitempty" json:"timeout,omitempty"`       // maximum run time per attempt, e.g. "30s" (command only)
+	Retries   int      `toml:"retries,omitempty" json:"retries,omitempty"`       // number of re-runs after a failure (command only)
+	Required  bool     `toml:"required,omitempty" json:"required,omitempty"`     // roll back worktree creation if the command fails (command only)
+}
+
+// Label returns the name used to refer to the action in output and dependencies
+func (a PostCreateAction) Label() string {
+	if a.Name != "" {
+		return a.Name
+	}
+	return a.Source
+}
+
+// TimeoutDuration parses Timeout, returning 0 if no timeout is set
+func (a PostCreateAction) TimeoutDuration() (time.Duration, error) {
+	if a.Timeout == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(a.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout %q for %s: %w", a.Timeout, a.Label(), err)
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid timeout %q for %s: must be positive", a.Timeout, a.Label())
+	}
+	return d, nil
 }
 
 // SyncToRootAction represents a file/directory to symlink from the default branch worktree to the repository root.
 type SyncToRootAction struct {
-	Source string `toml:"source"` // relative path in default branch worktree
-	Target string `toml:"target"` // relative path in repository root (empty means same as source)
+	Source string `toml:"source" json:"source"` // relative path in default branch worktree
+	Target string `toml:"target" json:"target"` // relative path in repository root (empty means same as source)
+}
+
+// HookAction represents a shell command run on a worktree lifecycle event.
+type HookAction struct {
+	Command string `toml:"command" json:"command"`
+}
+
+// Lifecycle events that support hooks
+const (
+	HookPreRemove    = "pre-remove"    // before 'bt remove' deletes a worktree (failure vetoes the removal)
+	HookPostRemove   = "post-remove"   // after 'bt remove' deletes a worktree
+	HookPostRename   = "post-rename"   // after 'bt rename' renames a worktree
+	HookPostCheckout = "post-checkout" // after 'bt unbare' checks out a worktree as a standalone repository
+)
+
+// HookEvents lists all lifecycle events that support hooks
+var HookEvents = []string{HookPreRemove, HookPostRemove, HookPostRename, HookPostCheckout}
+
+// Hooks returns a pointer to the hook list for the given event, or nil if the event is unknown
+func (c *Config) Hooks(event string) *[]HookAction {
+	switch event {
+	case HookPreRemove:
+		return &c.PreRemove
+	case HookPostRemove:
+		return &c.PostRemove
+	case HookPostRename:
+		return &c.PostRename
+	case HookPostCheckout:
+		return &c.PostCheckout
+	default:
+		return nil
+	}
 }
 
 // DefaultConfig returns a default configuration
@@ -0,0 +1,44 @@
+// Package fuzzy implements the loose matching used to resolve repository and worktree names
+package fuzzy
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Match reports whether all characters of query appear in s in the same order
+// (case-insensitive), e.g. "far" matches "feature/auth-refresh"
+func Match(s, query string) bool {
+	s = strings.ToLower(s)
+	for _, r := range strings.ToLower(query) {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+utf8.RuneLen(r):]
+	}
+	return true
+}
+
+// Best returns the index of the candidate with the highest score, or -1 if no candidate
+// has a positive score or the highest score is shared (the choice would be arbitrary)
+func Best(scores []float64) int {
+	best := -1
+	tie := false
+	for i, score := range scores {
+		if score <= 0 {
+			continue
+		}
+		switch {
+		case best < 0 || score > scores[best]:
+			best = i
+			tie = false
+		case score == scores[best]:
+			tie = true
+		}
+	}
+	if tie {
+		return -1
+	}
+	return best
+}
@@ -0,0 +1,43 @@
+package fuzzy
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		s     string
+		query string
+		want  bool
+	}{
+		{"feature/auth-refresh", "auth", true},
+		{"feature/auth-refresh", "far", true},
+		{"github.com/amaya382/baretree", "bt", true},
+		{"github.com/amaya382/baretree", "BT", true},
+		{"feature/auth-refresh", "hsa", false},
+		{"main", "mainline", false},
+		{"anything", "", true},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.s, tt.query); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, want %v", tt.s, tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestBest(t *testing.T) {
+	tests := []struct {
+		name   string
+		scores []float64
+		want   int
+	}{
+		{"highest wins", []float64{1, 5, 2}, 1},
+		{"tie is undecided", []float64{5, 5, 1}, -1},
+		{"tie below the best is fine", []float64{1, 1, 5}, 2},
+		{"no scores", []float64{0, 0}, -1},
+		{"empty", nil, -1},
+	}
+	for _, tt := range tests {
+		if got := Best(tt.scores); got != tt.want {
+			t.Errorf("%s: Best(%v) = %d, want %d", tt.name, tt.scores, got, tt.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // BranchInfo contains information about a branch
@@ -220,3 +221,49 @@ func (e *Executor) PullBranch(localBranch string) error {
 	_, err = e.Execute("update-ref", "refs/heads/"+localBranch, upstreamHash)
 	return err
 }
+
+// ListMergedBranches returns local branches whose tips are reachable from target
+func (e *Executor) ListMergedBranches(target string) ([]string, error) {
+	output, err := e.Execute("for-each-ref", "--merged="+target, "--format=%(refname:short)", "refs/heads/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branches merged into '%s': %w", target, err)
+	}
+
+	if output == "" {
+		return []string{}, nil
+	}
+
+	return strings.Split(output, "\n"), nil
+}
+
+// ListGoneBranches returns local branches whose configured upstream no longer exists
+// (e.g., deleted on the remote and pruned by 'git fetch --prune')
+func (e *Executor) ListGoneBranches() ([]string, error) {
+	output, err := e.Execute("for-each-ref", "--format=%(refname:short)\t%(upstream:track)", "refs/heads/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list branch upstreams: %w", err)
+	}
+
+	var gone []string
+	for _, line := range strings.Split(output, "\n") {
+		name, track, _ := strings.Cut(line, "\t")
+		if track == "[gone]" {
+			gone = append(gone, name)
+		}
+	}
+	return gone, nil
+}
+
+// LastCommitTime returns the committer date of the commit ref points to
+func (e *Executor) LastCommitTime(ref string) (time.Time, error) {
+	output, err := e.Execute("log", "-1", "--format=%ct", ref, "--")
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get last commit of '%s': %w", ref, err)
+	}
+
+	sec, err := strconv.ParseInt(output, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to parse commit time of '%s': %w", ref, err)
+	}
+	return time.Unix(sec, 0), nil
+}
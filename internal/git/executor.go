@@ -2,11 +2,18 @@ package git
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"os"
 	"os/exec"
 	"strings"
+	"time"
 )
 
+// waitDelay bounds how long to wait for output of processes (ssh, remote helpers) left behind
+// by a git command killed through its context
+const waitDelay = 2 * time.Second
+
 // Executor executes git commands
 type Executor struct {
 	workDir string
@@ -19,10 +26,26 @@ func NewExecutor(workDir string) *Executor {
 
 // Execute runs a git command and returns the output
 func (e *Executor) Execute(args ...string) (string, error) {
-	cmd := exec.Command("git", args...)
+	return e.execute(context.Background(), false, args)
+}
+
+// ExecuteContext runs a git command that is killed when ctx is done and returns the output.
+// Git never prompts for credentials, so a prompt cannot block the command until ctx is done.
+func (e *Executor) ExecuteContext(ctx context.Context, args ...string) (string, error) {
+	return e.execute(ctx, true, args)
+}
+
+// execute runs a git command and returns the output. A bounded command does not prompt for
+// credentials and stops waiting for the output of leftover processes once it is killed.
+func (e *Executor) execute(ctx context.Context, bounded bool, args []string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
 	if e.workDir != "" {
 		cmd.Dir = e.workDir
 	}
+	if bounded {
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		cmd.WaitDelay = waitDelay
+	}
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
@@ -30,6 +53,9 @@ func (e *Executor) Execute(args ...string) (string, error) {
 
 	err := cmd.Run()
 	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", fmt.Errorf("git %s failed: %w", strings.Join(args, " "), ctxErr)
+		}
 		return "", fmt.Errorf("git %s failed: %w\nstderr: %s", strings.Join(args, " "), err, stderr.String())
 	}
 
@@ -15,6 +15,14 @@ import (
 const (
 	// DefaultRoot is the default root directory for repositories
 	DefaultRoot = "~/baretree"
+	// DefaultHost is the default host for short repository names
+	DefaultHost = "github.com"
+	// DefaultProtocol is the default protocol for clone URLs built from short repository names
+	DefaultProtocol = "ssh"
+	// DefaultPullRequestRef is the default ref pattern of pull requests ({number} is the request number)
+	DefaultPullRequestRef = "refs/pull/{number}/head"
+	// DefaultMergeRequestRef is the default ref pattern of merge requests ({number} is the request number)
+	DefaultMergeRequestRef = "refs/merge-requests/{number}/head"
 )
 
 // Config holds the global baretree configuration from git-config
@@ -23,6 +31,29 @@ type Config struct {
 	Roots []string
 	// User is the default user name for short repository names
 	User string
+	// Host is the default host for short repository names (baretree.host)
+	Host string
+	// Protocol is the default clone protocol, "ssh" or "https" (baretree.protocol)
+	Protocol string
+	// Hosts holds per-host overrides (baretree.host.<name>.protocol, baretree.host.<name>.user),
+	// keyed by lower-case host name
+	Hosts map[string]HostConfig
+	// Frecency enables recording visited repositories and worktrees and ranking matches
+	// by how often and how recently they were visited (baretree.frecency, default: true)
+	Frecency bool
+	// KeepSubdir makes bt cd go to the same subdirectory of the target worktree
+	// instead of its root (baretree.keepSubdir, default: true)
+	KeepSubdir bool
+}
+
+// HostConfig holds settings that override the defaults for a single host
+type HostConfig struct {
+	Protocol string
+	User     string
+	// PullRequestRef and MergeRequestRef are the ref patterns fetched by bt add --pr and --mr
+	// (baretree.host.<name>.pullRequestRef, baretree.host.<name>.mergeRequestRef)
+	PullRequestRef  string
+	MergeRequestRef string
 }
 
 // LoadConfig loads the global configuration from git-config and environment
@@ -59,9 +90,99 @@ func LoadConfig() (*Config, error) {
 		}
 	}
 
+	// Load default host and protocol
+	cfg.Host = DefaultHost
+	if host, err := executor.Execute("config", "--get", "baretree.host"); err == nil && host != "" {
+		cfg.Host = host
+	}
+	cfg.Protocol = DefaultProtocol
+	if protocol, err := executor.Execute("config", "--get", "baretree.protocol"); err == nil && protocol != "" {
+		cfg.Protocol = strings.ToLower(protocol)
+	}
+
+	cfg.Frecency = true
+	if frecency, err := executor.Execute("config", "--type=bool", "--get", "baretree.frecency"); err == nil && frecency == "false" {
+		cfg.Frecency = false
+	}
+	cfg.KeepSubdir = true
+	if keepSubdir, err := executor.Execute("config", "--type=bool", "--get", "baretree.keepSubdir"); err == nil && keepSubdir == "false" {
+		cfg.KeepSubdir = false
+	}
+
+	// Load per-host overrides: baretree.host.<name>.{protocol,user,pullRequestRef,mergeRequestRef}
+	// (git reports variable names in lower case)
+	cfg.Hosts = make(map[string]HostConfig)
+	output, err := executor.Execute("config", "--get-regexp", `^baretree\.host\..+\.(protocol|user|pullrequestref|mergerequestref)$`)
+	if err == nil && output != "" {
+		for _, line := range strings.Split(output, "\n") {
+			key, value, _ := strings.Cut(line, " ")
+			name := strings.TrimPrefix(key, "baretree.host.")
+			dot := strings.LastIndex(name, ".")
+			if dot <= 0 {
+				continue
+			}
+			host := strings.ToLower(name[:dot])
+			hostCfg := cfg.Hosts[host]
+			switch name[dot+1:] {
+			case "protocol":
+				hostCfg.Protocol = strings.ToLower(value)
+			case "user":
+				hostCfg.User = value
+			case "pullrequestref":
+				hostCfg.PullRequestRef = value
+			case "mergerequestref":
+				hostCfg.MergeRequestRef = value
+			}
+			cfg.Hosts[host] = hostCfg
+		}
+	}
+
 	return cfg, nil
 }
 
+// ProtocolFor returns the clone protocol for a host (per-host setting, then baretree.protocol)
+func (c *Config) ProtocolFor(host string) string {
+	if hostCfg, ok := c.Hosts[strings.ToLower(host)]; ok && hostCfg.Protocol != "" {
+		return hostCfg.Protocol
+	}
+	if c.Protocol != "" {
+		return c.Protocol
+	}
+	return DefaultProtocol
+}
+
+// UserFor returns the default user for short repository names on a host (per-host setting, then baretree.user)
+func (c *Config) UserFor(host string) string {
+	if hostCfg, ok := c.Hosts[strings.ToLower(host)]; ok && hostCfg.User != "" {
+		return hostCfg.User
+	}
+	return c.User
+}
+
+// PullRequestRefFor returns the ref pattern fetched by bt add --pr for a host
+func (c *Config) PullRequestRefFor(host string) string {
+	if hostCfg, ok := c.Hosts[strings.ToLower(host)]; ok && hostCfg.PullRequestRef != "" {
+		return hostCfg.PullRequestRef
+	}
+	return DefaultPullRequestRef
+}
+
+// MergeRequestRefFor returns the ref pattern fetched by bt add --mr for a host
+func (c *Config) MergeRequestRefFor(host string) string {
+	if hostCfg, ok := c.Hosts[strings.ToLower(host)]; ok && hostCfg.MergeRequestRef != "" {
+		return hostCfg.MergeRequestRef
+	}
+	return DefaultMergeRequestRef
+}
+
+// DefaultHostName returns the default host for short repository names
+func (c *Config) DefaultHostName() string {
+	if c.Host != "" {
+		return c.Host
+	}
+	return DefaultHost
+}
+
 // PrimaryRoot returns the primary (last) root directory
 func (c *Config) PrimaryRoot() string {
 	if len(c.Roots) == 0 {
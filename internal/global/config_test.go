@@ -0,0 +1,82 @@
+package global
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadConfigHostSettings(t *testing.T) {
+	gitconfig := filepath.Join(t.TempDir(), "gitconfig")
+	content := `[baretree]
+	user = alice
+	host = gitlab.example.com
+	protocol = HTTPS
+[baretree "host.GitHub.com"]
+	protocol = ssh
+	user = alice-gh
+[baretree "host.gitlab.example.com"]
+	user = team
+	mergeRequestRef = refs/merge-requests/{number}/merge
+[baretree "host.local"]
+	pullRequestRef = refs/heads/pr-{number}
+`
+	if err := os.WriteFile(gitconfig, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", gitconfig)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+
+	if got := cfg.DefaultHostName(); got != "gitlab.example.com" {
+		t.Errorf("DefaultHostName() = %q, want gitlab.example.com", got)
+	}
+
+	tests := []struct {
+		host         string
+		wantProtocol string
+		wantUser     string
+	}{
+		{host: "gitlab.example.com", wantProtocol: "https", wantUser: "team"},
+		{host: "github.com", wantProtocol: "ssh", wantUser: "alice-gh"},
+		{host: "bitbucket.org", wantProtocol: "https", wantUser: "alice"},
+	}
+	for _, tt := range tests {
+		if got := cfg.ProtocolFor(tt.host); got != tt.wantProtocol {
+			t.Errorf("ProtocolFor(%q) = %q, want %q", tt.host, got, tt.wantProtocol)
+		}
+		if got := cfg.UserFor(tt.host); got != tt.wantUser {
+			t.Errorf("UserFor(%q) = %q, want %q", tt.host, got, tt.wantUser)
+		}
+	}
+
+	if got, want := cfg.MergeRequestRefFor("gitlab.example.com"), "refs/merge-requests/{number}/merge"; got != want {
+		t.Errorf("MergeRequestRefFor() = %q, want %q", got, want)
+	}
+	if got, want := cfg.PullRequestRefFor("local"), "refs/heads/pr-{number}"; got != want {
+		t.Errorf("PullRequestRefFor(local) = %q, want %q", got, want)
+	}
+	if got := cfg.PullRequestRefFor("github.com"); got != DefaultPullRequestRef {
+		t.Errorf("PullRequestRefFor(github.com) = %q, want %q", got, DefaultPullRequestRef)
+	}
+}
+
+func TestLoadConfigHostDefaults(t *testing.T) {
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "missing"))
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+
+	cfg, err := LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig failed: %v", err)
+	}
+	if cfg.DefaultHostName() != DefaultHost {
+		t.Errorf("DefaultHostName() = %q, want %q", cfg.DefaultHostName(), DefaultHost)
+	}
+	if got := cfg.ProtocolFor("github.com"); got != DefaultProtocol {
+		t.Errorf("ProtocolFor() = %q, want %q", got, DefaultProtocol)
+	}
+}
@@ -0,0 +1,62 @@
+package global
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CacheDir returns the directory for baretree caches that can be rebuilt at any time
+// ($XDG_CACHE_HOME/baretree, or the user cache directory of the OS)
+func CacheDir() (string, error) {
+	cacheDir := os.Getenv("XDG_CACHE_HOME")
+	if cacheDir == "" {
+		var err error
+		cacheDir, err = os.UserCacheDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine cache directory: %w", err)
+		}
+	}
+	return filepath.Join(cacheDir, "baretree"), nil
+}
+
+// StateDir returns the directory for baretree state that should persist but is not
+// configuration, such as usage history ($XDG_STATE_HOME/baretree, or ~/.local/state/baretree)
+func StateDir() (string, error) {
+	stateDir := os.Getenv("XDG_STATE_HOME")
+	if stateDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to determine state directory: %w", err)
+		}
+		stateDir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(stateDir, "baretree"), nil
+}
+
+// writeFileAtomic writes data to path via a temporary file in the same directory and a rename,
+// so that concurrent readers never see a partially written file
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
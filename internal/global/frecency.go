@@ -0,0 +1,203 @@
+package global
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// frecencyVersion is the current version of the frecency store format.
+// Stores with another version are ignored and started over.
+const frecencyVersion = 1
+
+// frecencyFileName is the name of the frecency store file in the state directory
+const frecencyFileName = "frecency.json"
+
+// frecencyMaxRank bounds the sum of all ranks. When it is exceeded, all ranks are scaled
+// down and entries that fall below 1 are forgotten, so old favorites fade out over time.
+const frecencyMaxRank = 1000.0
+
+// Frecency records how often and how recently repositories and worktrees were visited
+// with bt go / bt cd, and ranks them zoxide-style by a combination of both
+type Frecency struct {
+	Version int                       `json:"version"`
+	Entries map[string]*FrecencyEntry `json:"entries"`
+}
+
+// FrecencyEntry holds the visits of a single path
+type FrecencyEntry struct {
+	// Rank is increased by one on every visit and scaled down when the store grows too large
+	Rank float64 `json:"rank"`
+	// LastAccess is the time of the last visit in Unix seconds
+	LastAccess int64 `json:"last_access"`
+}
+
+// FrecencyPath returns the path of the frecency store file ($XDG_STATE_HOME/baretree/frecency.json)
+func FrecencyPath() (string, error) {
+	stateDir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, frecencyFileName), nil
+}
+
+// LoadFrecency reads the frecency store. A missing or unreadable store is returned empty.
+func LoadFrecency() *Frecency {
+	f := &Frecency{Version: frecencyVersion, Entries: make(map[string]*FrecencyEntry)}
+
+	path, err := FrecencyPath()
+	if err != nil {
+		return f
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return f
+	}
+
+	var stored Frecency
+	if err := json.Unmarshal(data, &stored); err != nil || stored.Version != frecencyVersion || stored.Entries == nil {
+		return f
+	}
+	for p, entry := range stored.Entries {
+		if entry != nil {
+			f.Entries[p] = entry
+		}
+	}
+	return f
+}
+
+// FrecencyScorer returns a function that scores paths by frecency, or nil if frecency is disabled
+func (c *Config) FrecencyScorer() func(path string) float64 {
+	if !c.Frecency {
+		return nil
+	}
+	return LoadFrecency().Score
+}
+
+// RecordVisit records a visit to path in the frecency store
+func RecordVisit(path string) error {
+	storePath, err := FrecencyPath()
+	if err != nil {
+		return err
+	}
+	return withFileLock(storePath, func() error {
+		f := LoadFrecency()
+		f.visit(path, time.Now())
+		f.forgetMissing()
+		f.age()
+		return f.save()
+	})
+}
+
+// Score returns the frecency score of path: the rank of the visits to path and to any
+// directory under it (e.g. the worktrees of a repository), weighted by how recently each was visited.
+// Unvisited paths score 0.
+func (f *Frecency) Score(path string) float64 {
+	if f == nil {
+		return 0
+	}
+	now := time.Now().Unix()
+	prefix := path + string(filepath.Separator)
+	score := 0.0
+	for p, entry := range f.Entries {
+		if p == path || strings.HasPrefix(p, prefix) {
+			score += entry.Rank * recencyWeight(now-entry.LastAccess)
+		}
+	}
+	return score
+}
+
+// SortRepositoriesByScore orders repositories by descending score, keeping the original order
+// for equal scores. It reports whether any repository has a positive score; score may be nil.
+func SortRepositoriesByScore(repos []RepoInfo, score func(path string) float64) bool {
+	if score == nil {
+		return false
+	}
+	scores := make(map[string]float64, len(repos))
+	ranked := false
+	for _, repo := range repos {
+		scores[repo.Path] = score(repo.Path)
+		ranked = ranked || scores[repo.Path] > 0
+	}
+	if ranked {
+		sort.SliceStable(repos, func(i, j int) bool {
+			return scores[repos[i].Path] > scores[repos[j].Path]
+		})
+	}
+	return ranked
+}
+
+// recencyWeight returns the weight of a visit that happened age seconds ago
+func recencyWeight(age int64) float64 {
+	switch {
+	case age < 60*60:
+		return 4
+	case age < 24*60*60:
+		return 2
+	case age < 7*24*60*60:
+		return 0.5
+	default:
+		return 0.25
+	}
+}
+
+// visit adds a visit to path at the given time
+func (f *Frecency) visit(path string, at time.Time) {
+	entry, ok := f.Entries[path]
+	if !ok {
+		entry = &FrecencyEntry{}
+		f.Entries[path] = entry
+	}
+	entry.Rank++
+	entry.LastAccess = at.Unix()
+}
+
+// forgetMissing removes entries whose directories no longer exist
+func (f *Frecency) forgetMissing() {
+	for p := range f.Entries {
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			delete(f.Entries, p)
+		}
+	}
+}
+
+// age scales all ranks down once their sum exceeds frecencyMaxRank and forgets
+// entries that drop below a rank of 1
+func (f *Frecency) age() {
+	total := 0.0
+	for _, entry := range f.Entries {
+		total += entry.Rank
+	}
+	if total <= frecencyMaxRank {
+		return
+	}
+
+	factor := 0.9 * frecencyMaxRank / total
+	for p, entry := range f.Entries {
+		entry.Rank *= factor
+		if entry.Rank < 1 {
+			delete(f.Entries, p)
+		}
+	}
+}
+
+// save writes the frecency store atomically
+func (f *Frecency) save() error {
+	path, err := FrecencyPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("failed to encode frecency store: %w", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write frecency store: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package global
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFrecencyScore(t *testing.T) {
+	now := time.Now()
+	repo := filepath.Join("/root", "github.com", "user", "repo")
+	f := &Frecency{Entries: map[string]*FrecencyEntry{
+		repo:                               {Rank: 2, LastAccess: now.Unix()},
+		filepath.Join(repo, "feature/a"):   {Rank: 1, LastAccess: now.Add(-2 * time.Hour).Unix()},
+		repo + "-fork":                     {Rank: 10, LastAccess: now.Unix()},
+		filepath.Join("/root", "old"):      {Rank: 10, LastAccess: now.Add(-30 * 24 * time.Hour).Unix()},
+		filepath.Join("/root", "lastweek"): {Rank: 10, LastAccess: now.Add(-3 * 24 * time.Hour).Unix()},
+	}}
+
+	// Visits to worktrees count towards their repository; sibling paths with the same prefix do not
+	if got, want := f.Score(repo), 2*4.0+1*2.0; got != want {
+		t.Errorf("Score(repo) = %v, want %v", got, want)
+	}
+	if got, want := f.Score(filepath.Join(repo, "feature/a")), 2.0; got != want {
+		t.Errorf("Score(worktree) = %v, want %v", got, want)
+	}
+	if got := f.Score(filepath.Join("/root", "old")); got >= f.Score(filepath.Join("/root", "lastweek")) {
+		t.Errorf("older visits should score lower, got %v", got)
+	}
+	if got := f.Score(filepath.Join("/root", "never")); got != 0 {
+		t.Errorf("Score(unvisited) = %v, want 0", got)
+	}
+
+	var disabled *Frecency
+	if got := disabled.Score(repo); got != 0 {
+		t.Errorf("nil store Score = %v, want 0", got)
+	}
+}
+
+func TestFrecencyAge(t *testing.T) {
+	f := &Frecency{Entries: map[string]*FrecencyEntry{
+		"/a": {Rank: frecencyMaxRank},
+		"/b": {Rank: 1},
+	}}
+	f.age()
+
+	if _, ok := f.Entries["/b"]; ok {
+		t.Error("entries that drop below rank 1 should be forgotten")
+	}
+	if rank := f.Entries["/a"].Rank; rank >= frecencyMaxRank {
+		t.Errorf("ranks should be scaled down, got %v", rank)
+	}
+}
+
+func TestRecordVisit(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing")
+
+	f := LoadFrecency()
+	f.visit(missing, time.Now())
+	if err := f.save(); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := RecordVisit(dir); err != nil {
+			t.Fatalf("RecordVisit failed: %v", err)
+		}
+	}
+
+	f = LoadFrecency()
+	if entry := f.Entries[dir]; entry == nil || entry.Rank != 3 {
+		t.Errorf("expected rank 3 after three visits, got %+v", entry)
+	}
+	if _, ok := f.Entries[missing]; ok {
+		t.Error("entries of removed directories should be forgotten")
+	}
+}
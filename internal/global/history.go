@@ -0,0 +1,176 @@
+package global
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// HistoryLimit is the maximum number of directories kept in a directory history
+const HistoryLimit = 50
+
+// Files in the home directory that held the single previous directory of bt cd and of
+// bt repo cd / bt go before histories were kept in the state directory
+const (
+	legacyWorktreeHistoryFile   = ".baretree_history"
+	legacyRepositoryHistoryFile = ".baretree_repo_history"
+)
+
+// DirHistory is a bounded list of previously visited directories, most recent first.
+// It backs "bt cd -N" (one history per repository) and "bt go -N" (one shared history).
+type DirHistory struct {
+	path string
+	// legacy is the old history file used to seed a history that does not exist yet, and
+	// scope the directory its entry must be in to belong to this history (empty for any)
+	legacy string
+	scope  string
+}
+
+// WorktreeHistory returns the worktree history of the repository at repoRoot
+// ($XDG_STATE_HOME/baretree/history/worktrees/<name>-<hash>)
+func WorktreeHistory(repoRoot string) (*DirHistory, error) {
+	stateDir, err := StateDir()
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(filepath.Clean(repoRoot)))
+	name := filepath.Base(repoRoot) + "-" + hex.EncodeToString(sum[:6])
+	return &DirHistory{
+		path:   filepath.Join(stateDir, "history", "worktrees", name),
+		legacy: legacyHistoryPath(legacyWorktreeHistoryFile),
+		scope:  filepath.Clean(repoRoot),
+	}, nil
+}
+
+// RepositoryHistory returns the history of repositories visited with bt go
+// ($XDG_STATE_HOME/baretree/history/repositories)
+func RepositoryHistory() (*DirHistory, error) {
+	stateDir, err := StateDir()
+	if err != nil {
+		return nil, err
+	}
+	return &DirHistory{
+		path:   filepath.Join(stateDir, "history", "repositories"),
+		legacy: legacyHistoryPath(legacyRepositoryHistoryFile),
+	}, nil
+}
+
+// ParseHistoryPosition parses a history reference: "-" is the previous directory (1),
+// "-N" the N-th previous one. It reports false for anything else.
+func ParseHistoryPosition(arg string) (int, bool) {
+	if arg == "-" {
+		return 1, true
+	}
+	if !strings.HasPrefix(arg, "-") {
+		return 0, false
+	}
+	n, err := strconv.Atoi(arg[1:])
+	if err != nil || n < 1 || arg[1] == '+' {
+		return 0, false
+	}
+	return n, true
+}
+
+// Entries returns the directories in the history, most recent first, leaving out
+// current (the directory the user is in) and directories that no longer exist
+func (h *DirHistory) Entries(current string) ([]string, error) {
+	entries, err := h.read()
+	if err != nil {
+		return nil, err
+	}
+	var existing []string
+	for _, entry := range entries {
+		if entry == current {
+			continue
+		}
+		if info, err := os.Stat(entry); err == nil && info.IsDir() {
+			existing = append(existing, entry)
+		}
+	}
+	return existing, nil
+}
+
+// Move records a move from the directory from to the directory to: to is taken out of
+// the history (it becomes the current directory) and from is pushed on top of it.
+// Concurrent updates from several shells are serialized with a lock file.
+func (h *DirHistory) Move(from, to string) error {
+	return withFileLock(h.path, func() error {
+		entries, err := h.read()
+		if err != nil {
+			return err
+		}
+
+		updated := []string{}
+		if from != "" && from != to {
+			updated = append(updated, from)
+		}
+		for _, entry := range entries {
+			if entry != from && entry != to {
+				updated = append(updated, entry)
+			}
+		}
+		if len(updated) > HistoryLimit {
+			updated = updated[:HistoryLimit]
+		}
+
+		if err := writeFileAtomic(h.path, []byte(strings.Join(updated, "\n")+"\n")); err != nil {
+			return err
+		}
+		// The old file has been taken over by this history
+		if len(h.readLegacy()) > 0 {
+			os.Remove(h.legacy)
+		}
+		return nil
+	})
+}
+
+// read returns the raw entries of the history file (one directory per line), or the entry of
+// the old history file if there is none yet
+func (h *DirHistory) read() ([]string, error) {
+	data, err := os.ReadFile(h.path)
+	if os.IsNotExist(err) {
+		return h.readLegacy(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			entries = append(entries, line)
+		}
+	}
+	return entries, nil
+}
+
+// readLegacy returns the directory in the old history file if it belongs to this history
+func (h *DirHistory) readLegacy() []string {
+	if h.legacy == "" {
+		return nil
+	}
+	data, err := os.ReadFile(h.legacy)
+	if err != nil {
+		return nil
+	}
+	dir := strings.TrimSpace(string(data))
+	if dir == "" || !filepath.IsAbs(dir) {
+		return nil
+	}
+	if h.scope != "" && dir != h.scope && !strings.HasPrefix(dir, h.scope+string(filepath.Separator)) {
+		return nil
+	}
+	return []string{dir}
+}
+
+// legacyHistoryPath returns the path of an old history file in the home directory ("" if unknown)
+func legacyHistoryPath(name string) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, name)
+}
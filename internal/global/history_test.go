@@ -0,0 +1,228 @@
+package global
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestParseHistoryPosition(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want int
+		ok   bool
+	}{
+		{"-", 1, true},
+		{"-1", 1, true},
+		{"-12", 12, true},
+		{"-0", 0, false},
+		{"--1", 0, false},
+		{"-+1", 0, false},
+		{"-a", 0, false},
+		{"--history", 0, false},
+		{"feature/auth", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseHistoryPosition(tt.arg)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseHistoryPosition(%q) = %d, %v, want %d, %v", tt.arg, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+// historyDirs creates n directories and returns their paths
+func historyDirs(t *testing.T, n int) []string {
+	t.Helper()
+	root := t.TempDir()
+	dirs := make([]string, n)
+	for i := range dirs {
+		dirs[i] = filepath.Join(root, fmt.Sprintf("dir%d", i))
+		if err := os.Mkdir(dirs[i], 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dirs
+}
+
+func TestDirHistoryMove(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	dirs := historyDirs(t, 4)
+
+	history, err := WorktreeHistory(filepath.Join(t.TempDir(), "repo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a -> b -> c -> a -> d
+	moves := [][2]string{{dirs[0], dirs[1]}, {dirs[1], dirs[2]}, {dirs[2], dirs[0]}, {dirs[0], dirs[3]}}
+	for _, m := range moves {
+		if err := history.Move(m[0], m[1]); err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+	}
+
+	// Most recent first, each directory once, the current directory left out
+	entries, err := history.Entries(dirs[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{dirs[0], dirs[2], dirs[1]}; !reflect.DeepEqual(entries, want) {
+		t.Errorf("Entries() = %v, want %v", entries, want)
+	}
+
+	// Directories that no longer exist are skipped
+	if err := os.Remove(dirs[2]); err != nil {
+		t.Fatal(err)
+	}
+	entries, err = history.Entries(dirs[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{dirs[1]}; !reflect.DeepEqual(entries, want) {
+		t.Errorf("Entries() after removal = %v, want %v", entries, want)
+	}
+}
+
+func TestDirHistoryLimit(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	dirs := historyDirs(t, HistoryLimit+10)
+
+	history, err := RepositoryHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < len(dirs)-1; i++ {
+		if err := history.Move(dirs[i], dirs[i+1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := history.Entries(dirs[len(dirs)-1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != HistoryLimit {
+		t.Fatalf("len(Entries()) = %d, want %d", len(entries), HistoryLimit)
+	}
+	if entries[0] != dirs[len(dirs)-2] {
+		t.Errorf("Entries()[0] = %s, want %s", entries[0], dirs[len(dirs)-2])
+	}
+}
+
+func TestWorktreeHistoryPerRepository(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	dirs := historyDirs(t, 2)
+
+	// Repositories with the same name get separate histories
+	first, err := WorktreeHistory(filepath.Join("/a", "repo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := WorktreeHistory(filepath.Join("/b", "repo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Move(dirs[0], dirs[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := second.Entries(dirs[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Entries() of another repository = %v, want none", entries)
+	}
+}
+
+func TestDirHistoryConcurrentMoves(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	dirs := historyDirs(t, 20)
+
+	history, err := RepositoryHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Every move from a different shell must be kept
+	var wg sync.WaitGroup
+	errs := make(chan error, len(dirs))
+	for _, dir := range dirs {
+		wg.Add(1)
+		go func(dir string) {
+			defer wg.Done()
+			errs <- history.Move(dir, "")
+		}(dir)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Move() error = %v", err)
+		}
+	}
+
+	entries, err := history.Entries("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(dirs) {
+		t.Errorf("len(Entries()) = %d, want %d", len(entries), len(dirs))
+	}
+}
+
+func TestDirHistoryLegacySeed(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	repoRoot := t.TempDir()
+	previous := filepath.Join(repoRoot, "main")
+	current := filepath.Join(repoRoot, "feature")
+	for _, dir := range []string{previous, current} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	legacy := filepath.Join(home, legacyWorktreeHistoryFile)
+	if err := os.WriteFile(legacy, []byte(previous), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The old file of another repository is not taken over
+	other, err := WorktreeHistory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := other.Entries(""); len(entries) != 0 {
+		t.Errorf("other repository should have no history, got %v", entries)
+	}
+
+	history, err := WorktreeHistory(repoRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := history.Entries(current)
+	if err != nil || len(entries) != 1 || entries[0] != previous {
+		t.Fatalf("Entries() = %v, %v; want the old previous directory", entries, err)
+	}
+
+	if err := history.Move(current, previous); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Errorf("old history file should be removed once taken over, stat error = %v", err)
+	}
+	if entries, _ := history.Entries(previous); len(entries) != 1 || entries[0] != current {
+		t.Errorf("Entries() = %v, want [%s]", entries, current)
+	}
+}
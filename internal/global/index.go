@@ -0,0 +1,369 @@
+package global
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// indexVersion is the current version of the repository index format.
+// Indexes with another version are ignored and rebuilt.
+const indexVersion = 1
+
+// indexFileName is the name of the repository index file in the cache directory
+const indexFileName = "repos.json"
+
+// repoIndex is the persistent cache of the repositories found under each root
+type repoIndex struct {
+	Version int                   `json:"version"`
+	Roots   map[string]*rootIndex `json:"roots"`
+}
+
+// rootIndex holds the repositories found under a single root.
+// Dirs records the modification time of every directory walked to find them
+// (excluding repositories), so that adding or removing a repository anywhere
+// under the root is detected by a stat of each directory instead of a full walk.
+type rootIndex struct {
+	// Repos are repository paths relative to the root, in scan order
+	Repos []string `json:"repos"`
+	// Dirs maps directories relative to the root ("." for the root itself) to their
+	// modification time in nanoseconds; 0 means the directory did not exist
+	Dirs map[string]int64 `json:"dirs"`
+}
+
+// IndexPath returns the path of the repository index file ($XDG_CACHE_HOME/baretree/repos.json)
+func IndexPath() (string, error) {
+	cacheDir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, indexFileName), nil
+}
+
+// ListRepositories returns the repositories under the given roots like ScanRepositories,
+// but reads them from the repository index when it is up to date. Roots with directories
+// that changed since they were indexed are rescanned and the index is updated. If the
+// index cannot be read or written, this falls back to a full scan.
+func ListRepositories(roots []string) ([]RepoInfo, error) {
+	index, _ := loadIndex()
+
+	fresh := &repoIndex{Version: indexVersion, Roots: make(map[string]*rootIndex)}
+	changed := len(index.Roots) != len(roots)
+	for _, root := range roots {
+		ri, ok := index.Roots[root]
+		if !ok || !ri.isValid(root) {
+			var err error
+			ri, err = scanRoot(root)
+			if err != nil {
+				return nil, err
+			}
+			changed = true
+		}
+		fresh.Roots[root] = ri
+	}
+
+	if changed {
+		// A failure only means the next call scans again
+		_ = saveIndex(fresh)
+	}
+	return fresh.repositories(roots), nil
+}
+
+// RebuildIndex scans the given roots from scratch and rewrites the repository index
+func RebuildIndex(roots []string) ([]RepoInfo, error) {
+	index := &repoIndex{Version: indexVersion, Roots: make(map[string]*rootIndex)}
+	for _, root := range roots {
+		ri, err := scanRoot(root)
+		if err != nil {
+			return nil, err
+		}
+		index.Roots[root] = ri
+	}
+
+	if err := saveIndex(index); err != nil {
+		return nil, err
+	}
+	return index.repositories(roots), nil
+}
+
+// UpdateIndex records that a repository was created at or removed from repoPath
+// (e.g. by bt get, bt init, bt migrate or bt repo remove) without rescanning the root.
+// Nothing is done if there is no index yet or repoPath is not under an indexed root.
+// If other directories on the way changed as well, the root is rescanned.
+func UpdateIndex(roots []string, repoPath string) error {
+	index, err := loadIndex()
+	if err != nil {
+		return nil
+	}
+
+	for _, root := range roots {
+		ri, ok := index.Roots[root]
+		if !ok {
+			continue
+		}
+		rel, err := filepath.Rel(root, repoPath)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+
+		if !ri.update(root, rel) {
+			if index.Roots[root], err = scanRoot(root); err != nil {
+				return err
+			}
+		}
+		return saveIndex(index)
+	}
+	return nil
+}
+
+// repositories converts the index of the given roots into RepoInfo, skipping
+// repositories already found under a previous root
+func (idx *repoIndex) repositories(roots []string) []RepoInfo {
+	var repos []RepoInfo
+	seen := make(map[string]bool)
+	for _, root := range roots {
+		ri, ok := idx.Roots[root]
+		if !ok {
+			continue
+		}
+		for _, rel := range ri.Repos {
+			path := filepath.Join(root, rel)
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			repos = append(repos, RepoInfo{
+				Path:         path,
+				RelativePath: rel,
+				Name:         filepath.Base(path),
+			})
+		}
+	}
+	return repos
+}
+
+// isValid reports whether no directory under the root changed since it was indexed
+func (ri *rootIndex) isValid(root string) bool {
+	for rel, modTime := range ri.Dirs {
+		info, err := os.Stat(filepath.Join(root, rel))
+		if err != nil {
+			if modTime == 0 && os.IsNotExist(err) {
+				continue
+			}
+			return false
+		}
+		if !info.IsDir() || info.ModTime().UnixNano() != modTime {
+			return false
+		}
+	}
+	for _, rel := range ri.Repos {
+		if !isRepoDir(filepath.Join(root, rel)) {
+			return false
+		}
+	}
+	return true
+}
+
+// update brings the index in line with a repository created or removed at rel.
+// The directories between the root and rel are re-read; it returns false if any of
+// them has entries the index does not know about, in which case the root must be rescanned.
+func (ri *rootIndex) update(root, rel string) bool {
+	ri.removeTree(rel)
+	if isRepoDir(filepath.Join(root, rel)) {
+		ri.Repos = append(ri.Repos, rel)
+	} else if _, err := os.Stat(filepath.Join(root, rel)); err == nil {
+		// Not a repository (anymore), but its contents were never indexed
+		return false
+	}
+	sort.SliceStable(ri.Repos, func(i, j int) bool {
+		return lessPath(ri.Repos[i], ri.Repos[j])
+	})
+
+	// Walk down from the root to the parent of rel
+	parts := strings.Split(rel, string(filepath.Separator))
+	dir := "."
+	for i, part := range parts {
+		info, err := os.Stat(filepath.Join(root, dir))
+		if err != nil {
+			// The rest of the path was removed (e.g. empty parent directories)
+			ri.removeTree(dir)
+			if dir == "." {
+				ri.Dirs["."] = 0
+			}
+			return true
+		}
+
+		entries, err := os.ReadDir(filepath.Join(root, dir))
+		if err != nil {
+			return false
+		}
+		actual := make(map[string]bool)
+		for _, entry := range entries {
+			if entry.IsDir() && !strings.HasPrefix(entry.Name(), ".") && entry.Name() != part {
+				actual[entry.Name()] = true
+			}
+		}
+		known := ri.children(dir)
+		delete(known, part)
+		if len(actual) != len(known) {
+			return false
+		}
+		for name := range actual {
+			if !known[name] {
+				return false
+			}
+		}
+		ri.Dirs[dir] = info.ModTime().UnixNano()
+
+		if i == len(parts)-1 {
+			break
+		}
+		dir = filepath.Join(dir, part)
+	}
+	return true
+}
+
+// removeTree removes rel and everything under it from the index
+func (ri *rootIndex) removeTree(rel string) {
+	within := func(p string) bool {
+		return rel == "." || p == rel || strings.HasPrefix(p, rel+string(filepath.Separator))
+	}
+	repos := ri.Repos[:0]
+	for _, p := range ri.Repos {
+		if !within(p) {
+			repos = append(repos, p)
+		}
+	}
+	ri.Repos = repos
+	for p := range ri.Dirs {
+		if within(p) {
+			delete(ri.Dirs, p)
+		}
+	}
+}
+
+// children returns the names of the indexed directories and repositories directly under dir
+func (ri *rootIndex) children(dir string) map[string]bool {
+	children := make(map[string]bool)
+	add := func(p string) {
+		if p != "." && filepath.Dir(p) == dir {
+			children[filepath.Base(p)] = true
+		}
+	}
+	for p := range ri.Dirs {
+		add(p)
+	}
+	for _, p := range ri.Repos {
+		add(p)
+	}
+	return children
+}
+
+// scanRoot walks a root directory and indexes the repositories under it.
+// A root that does not exist is indexed as empty.
+func scanRoot(root string) (*rootIndex, error) {
+	ri := &rootIndex{Repos: []string{}, Dirs: make(map[string]int64)}
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		ri.Dirs["."] = 0
+		return ri, nil
+	}
+
+	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // Skip directories we can't access
+		}
+
+		if !d.IsDir() {
+			return nil
+		}
+
+		// Skip hidden directories (except the root itself)
+		if path != root && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+
+		relPath, _ := filepath.Rel(root, path)
+
+		// Don't descend into repositories
+		if isRepoDir(path) {
+			ri.Repos = append(ri.Repos, relPath)
+			return filepath.SkipDir
+		}
+
+		if info, err := d.Info(); err == nil {
+			ri.Dirs[relPath] = info.ModTime().UnixNano()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ri, nil
+}
+
+// isRepoDir reports whether path contains a .git directory
+func isRepoDir(path string) bool {
+	info, err := os.Stat(filepath.Join(path, ".git"))
+	return err == nil && info.IsDir()
+}
+
+// lessPath orders relative paths component by component, as filepath.WalkDir visits them
+func lessPath(a, b string) bool {
+	partsA := strings.Split(a, string(filepath.Separator))
+	partsB := strings.Split(b, string(filepath.Separator))
+	for i := 0; i < len(partsA) && i < len(partsB); i++ {
+		if partsA[i] != partsB[i] {
+			return partsA[i] < partsB[i]
+		}
+	}
+	return len(partsA) < len(partsB)
+}
+
+// loadIndex reads the repository index. An empty index is returned with the error
+// if the file does not exist, cannot be parsed or has another version.
+func loadIndex() (*repoIndex, error) {
+	empty := &repoIndex{Version: indexVersion, Roots: make(map[string]*rootIndex)}
+
+	path, err := IndexPath()
+	if err != nil {
+		return empty, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return empty, err
+	}
+
+	var index repoIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return empty, fmt.Errorf("failed to parse repository index: %w", err)
+	}
+	if index.Version != indexVersion || index.Roots == nil {
+		return empty, fmt.Errorf("unsupported repository index version %d", index.Version)
+	}
+	for root, ri := range index.Roots {
+		if ri == nil || ri.Dirs == nil {
+			delete(index.Roots, root)
+		}
+	}
+	return &index, nil
+}
+
+// saveIndex writes the repository index atomically
+func saveIndex(index *repoIndex) error {
+	path, err := IndexPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to encode repository index: %w", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return fmt.Errorf("failed to write repository index: %w", err)
+	}
+	return nil
+}
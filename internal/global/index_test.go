@@ -0,0 +1,232 @@
+package global
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// relativePaths returns the relative paths of repos
+func relativePaths(repos []RepoInfo) []string {
+	paths := []string{}
+	for _, r := range repos {
+		paths = append(paths, filepath.ToSlash(r.RelativePath))
+	}
+	return paths
+}
+
+// makeRepoDir creates a directory that looks like a repository to the scanner
+func makeRepoDir(t *testing.T, root, rel string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Join(root, filepath.FromSlash(rel), ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// touchDir sets the modification time of a directory into the past so that the next change is detected
+// even on filesystems with coarse timestamps
+func touchDir(t *testing.T, path string) {
+	t.Helper()
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, past, past); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListRepositoriesUsesIndex(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	root := t.TempDir()
+	makeRepoDir(t, root, "github.com/user/a")
+	makeRepoDir(t, root, "github.com/user/b")
+	touchDir(t, filepath.Join(root, "github.com", "user"))
+
+	repos, err := ListRepositories([]string{root})
+	if err != nil {
+		t.Fatalf("ListRepositories failed: %v", err)
+	}
+	want := []string{"github.com/user/a", "github.com/user/b"}
+	if got := relativePaths(repos); !reflect.DeepEqual(got, want) {
+		t.Fatalf("ListRepositories() = %v, want %v", got, want)
+	}
+
+	indexPath, err := IndexPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertIndexExists(t, indexPath)
+
+	// A repository created under a known directory invalidates the root
+	makeRepoDir(t, root, "github.com/user/c")
+	repos, _ = ListRepositories([]string{root})
+	want = []string{"github.com/user/a", "github.com/user/b", "github.com/user/c"}
+	if got := relativePaths(repos); !reflect.DeepEqual(got, want) {
+		t.Errorf("after adding a repository: got %v, want %v", got, want)
+	}
+
+	// A removed repository is detected as well
+	touchDir(t, filepath.Join(root, "github.com", "user"))
+	if _, err := ListRepositories([]string{root}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.RemoveAll(filepath.Join(root, "github.com", "user", "a")); err != nil {
+		t.Fatal(err)
+	}
+	repos, _ = ListRepositories([]string{root})
+	want = []string{"github.com/user/b", "github.com/user/c"}
+	if got := relativePaths(repos); !reflect.DeepEqual(got, want) {
+		t.Errorf("after removing a repository: got %v, want %v", got, want)
+	}
+
+	// Entries are served from the index while no indexed directory changed
+	index, err := loadIndex()
+	if err != nil {
+		t.Fatalf("loadIndex failed: %v", err)
+	}
+	index.Roots[root].Repos = append(index.Roots[root].Repos, "cached/only")
+	if err := saveIndex(index); err != nil {
+		t.Fatal(err)
+	}
+	makeRepoDir(t, root, "cached/only")
+	index.Roots[root].Dirs["cached"] = statModTime(t, filepath.Join(root, "cached"))
+	index.Roots[root].Dirs["."] = statModTime(t, root)
+	if err := saveIndex(index); err != nil {
+		t.Fatal(err)
+	}
+	repos, _ = ListRepositories([]string{root})
+	if got := relativePaths(repos); len(got) != 3 || got[2] != "cached/only" {
+		t.Errorf("expected the cached entry to be used, got %v", got)
+	}
+}
+
+func TestListRepositoriesCorruptIndex(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	root := t.TempDir()
+	makeRepoDir(t, root, "github.com/user/a")
+
+	indexPath, _ := IndexPath()
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(indexPath, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	repos, err := ListRepositories([]string{root})
+	if err != nil {
+		t.Fatalf("ListRepositories failed: %v", err)
+	}
+	if got := relativePaths(repos); !reflect.DeepEqual(got, []string{"github.com/user/a"}) {
+		t.Errorf("expected fallback to a full scan, got %v", got)
+	}
+	if _, err := loadIndex(); err != nil {
+		t.Errorf("index should be rewritten: %v", err)
+	}
+}
+
+func TestListRepositoriesMissingRoot(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	root := filepath.Join(t.TempDir(), "root")
+
+	repos, err := ListRepositories([]string{root})
+	if err != nil || len(repos) != 0 {
+		t.Fatalf("ListRepositories() = %v, %v; want no repositories", repos, err)
+	}
+
+	makeRepoDir(t, root, "github.com/user/a")
+	repos, _ = ListRepositories([]string{root})
+	if got := relativePaths(repos); !reflect.DeepEqual(got, []string{"github.com/user/a"}) {
+		t.Errorf("root created after indexing: got %v", got)
+	}
+}
+
+func TestUpdateIndex(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	root := t.TempDir()
+	makeRepoDir(t, root, "github.com/user/a")
+	roots := []string{root}
+
+	if _, err := RebuildIndex(roots); err != nil {
+		t.Fatalf("RebuildIndex failed: %v", err)
+	}
+
+	t.Run("added repository in a new directory", func(t *testing.T) {
+		makeRepoDir(t, root, "gitlab.com/group/sub/b")
+		if err := UpdateIndex(roots, filepath.Join(root, "gitlab.com", "group", "sub", "b")); err != nil {
+			t.Fatalf("UpdateIndex failed: %v", err)
+		}
+		index, _ := loadIndex()
+		if !index.Roots[root].isValid(root) {
+			t.Error("index should be up to date after UpdateIndex")
+		}
+		want := []string{"github.com/user/a", "gitlab.com/group/sub/b"}
+		if got := relativePaths(index.repositories(roots)); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("removed repository with its empty parents", func(t *testing.T) {
+		if err := os.RemoveAll(filepath.Join(root, "gitlab.com")); err != nil {
+			t.Fatal(err)
+		}
+		if err := UpdateIndex(roots, filepath.Join(root, "gitlab.com", "group", "sub", "b")); err != nil {
+			t.Fatalf("UpdateIndex failed: %v", err)
+		}
+		index, _ := loadIndex()
+		if !index.Roots[root].isValid(root) {
+			t.Error("index should be up to date after UpdateIndex")
+		}
+		if got := relativePaths(index.repositories(roots)); !reflect.DeepEqual(got, []string{"github.com/user/a"}) {
+			t.Errorf("got %v, want [github.com/user/a]", got)
+		}
+	})
+
+	t.Run("unrelated changes on the way rescan the root", func(t *testing.T) {
+		// Created without UpdateIndex
+		makeRepoDir(t, root, "github.com/user/other")
+		makeRepoDir(t, root, "github.com/user/c")
+		if err := UpdateIndex(roots, filepath.Join(root, "github.com", "user", "c")); err != nil {
+			t.Fatalf("UpdateIndex failed: %v", err)
+		}
+		index, _ := loadIndex()
+		want := []string{"github.com/user/a", "github.com/user/c", "github.com/user/other"}
+		if got := relativePaths(index.repositories(roots)); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("paths outside the roots are ignored", func(t *testing.T) {
+		if err := UpdateIndex(roots, filepath.Join(t.TempDir(), "repo")); err != nil {
+			t.Errorf("UpdateIndex failed: %v", err)
+		}
+	})
+}
+
+func TestLessPath(t *testing.T) {
+	sep := string(filepath.Separator)
+	if !lessPath("a"+sep+"b", "a-c") {
+		t.Error("a/b should sort before a-c like filepath.WalkDir")
+	}
+	if lessPath("b", "a"+sep+"z") {
+		t.Error("b should sort after a/z")
+	}
+}
+
+// statModTime returns the modification time of path in nanoseconds
+func statModTime(t *testing.T, path string) int64 {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return info.ModTime().UnixNano()
+}
+
+// assertIndexExists fails the test if the index file was not written
+func assertIndexExists(t *testing.T, path string) {
+	t.Helper()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("index file not written: %v", err)
+	}
+}
@@ -0,0 +1,501 @@
+package global
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/amaya382/baretree/internal/config"
+	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
+)
+
+// journalVersion is the current version of the journal format
+const journalVersion = 1
+
+// journalFileName is the name of the journal file in the state directory
+const journalFileName = "journal.json"
+
+// JournalLimit is the maximum number of operations kept in the journal
+const JournalLimit = 200
+
+// Actions of journal steps. Each step records what is needed to revert it.
+const (
+	// StepAddWorktree: a worktree was added at Path for Branch
+	StepAddWorktree = "add-worktree"
+	// StepRemoveWorktree: the worktree at Path on Branch (empty if detached) at commit Head was deleted
+	StepRemoveWorktree = "remove-worktree"
+	// StepMoveWorktree: the worktree at OldPath was moved to Path
+	StepMoveWorktree = "move-worktree"
+	// StepRenameBranch: OldBranch was renamed to Branch
+	StepRenameBranch = "rename-branch"
+	// StepUpdateRef: Ref was changed from OldSHA to NewSHA (empty means the ref did not exist)
+	StepUpdateRef = "update-ref"
+	// StepTrash: the worktree or repository at Path was moved into the trash as TrashID
+	StepTrash = "trash"
+	// StepCreateRepository: a repository was created at Path
+	StepCreateRepository = "create-repository"
+	// StepIrreversible: a change that cannot be reverted, described by Note
+	StepIrreversible = "irreversible"
+)
+
+// JournalEntry is one mutating bt command and the steps it performed, in order
+type JournalEntry struct {
+	ID      int    `json:"id"`
+	Command string `json:"command"`
+	// Repository is the root of the repository the command ran in (empty outside a repository)
+	Repository string        `json:"repository,omitempty"`
+	Time       time.Time     `json:"time"`
+	Steps      []JournalStep `json:"steps"`
+	// UndoneAt is set once the entry has been undone with 'bt undo'
+	UndoneAt *time.Time `json:"undone_at,omitempty"`
+}
+
+// JournalStep is a single change to refs or directories (see the Step* actions for the fields used)
+type JournalStep struct {
+	Action string `json:"action"`
+	// Repository is the root of the repository the step applies to
+	Repository string `json:"repository,omitempty"`
+	Path       string `json:"path,omitempty"`
+	OldPath    string `json:"old_path,omitempty"`
+	Branch     string `json:"branch,omitempty"`
+	OldBranch  string `json:"old_branch,omitempty"`
+	Ref        string `json:"ref,omitempty"`
+	OldSHA     string `json:"old_sha,omitempty"`
+	NewSHA     string `json:"new_sha,omitempty"`
+	Head       string `json:"head,omitempty"`
+	TrashID    string `json:"trash_id,omitempty"`
+	Note       string `json:"note,omitempty"`
+}
+
+// Reversible reports whether every step of the entry can be undone
+func (e *JournalEntry) Reversible() bool {
+	for _, step := range e.Steps {
+		if step.Action == StepIrreversible {
+			return false
+		}
+	}
+	return true
+}
+
+// Describe returns a one-line description of the step, e.g. "renamed branch a -> b"
+func (s JournalStep) Describe() string {
+	switch s.Action {
+	case StepAddWorktree:
+		return fmt.Sprintf("added worktree %s at %s", s.Branch, s.Path)
+	case StepRemoveWorktree:
+		return fmt.Sprintf("removed worktree %s at %s (%s)", s.Branch, s.Path, shortHash(s.Head))
+	case StepMoveWorktree:
+		return fmt.Sprintf("moved worktree %s -> %s", s.OldPath, s.Path)
+	case StepRenameBranch:
+		return fmt.Sprintf("renamed branch %s -> %s", s.OldBranch, s.Branch)
+	case StepUpdateRef:
+		switch {
+		case s.OldSHA == "":
+			return fmt.Sprintf("created %s at %s", s.Ref, shortHash(s.NewSHA))
+		case s.NewSHA == "":
+			return fmt.Sprintf("deleted %s (was %s)", s.Ref, shortHash(s.OldSHA))
+		default:
+			return fmt.Sprintf("updated %s %s..%s", s.Ref, shortHash(s.OldSHA), shortHash(s.NewSHA))
+		}
+	case StepTrash:
+		return fmt.Sprintf("moved %s to the trash (%s)", s.Path, s.TrashID)
+	case StepCreateRepository:
+		return fmt.Sprintf("created repository at %s", s.Path)
+	case StepIrreversible:
+		return s.Note
+	default:
+		return s.Action
+	}
+}
+
+// Operation collects the steps of a mutating command and records them in the journal
+type Operation struct {
+	entry JournalEntry
+}
+
+// NewOperation starts recording the command being run (taken from the command line) in the
+// repository at repoRoot (empty if it does not run in a repository)
+func NewOperation(repoRoot string) *Operation {
+	return &Operation{entry: JournalEntry{
+		Command:    strings.Join(append([]string{"bt"}, os.Args[1:]...), " "),
+		Repository: repoRoot,
+		Time:       time.Now(),
+	}}
+}
+
+// Record adds a step that has been performed. Steps without a repository inherit the
+// repository of the operation.
+func (op *Operation) Record(step JournalStep) {
+	if step.Repository == "" {
+		step.Repository = op.entry.Repository
+	}
+	op.entry.Steps = append(op.entry.Steps, step)
+}
+
+// RecordRef adds an update-ref step for ref if its value changed from oldSHA
+// (see ResolveRef)
+func (op *Operation) RecordRef(repoRoot, ref, oldSHA string) {
+	if newSHA := ResolveRef(repoRoot, ref); newSHA != oldSHA {
+		op.Record(JournalStep{Action: StepUpdateRef, Repository: repoRoot, Ref: ref, OldSHA: oldSHA, NewSHA: newSHA})
+	}
+}
+
+// Save appends the operation to the journal. Operations without steps are not recorded.
+func (op *Operation) Save() error {
+	if len(op.entry.Steps) == 0 {
+		return nil
+	}
+	return updateJournal(func(store *journalStore) error {
+		store.NextID++
+		op.entry.ID = store.NextID
+		store.Entries = append(store.Entries, op.entry)
+		if len(store.Entries) > JournalLimit {
+			store.Entries = store.Entries[len(store.Entries)-JournalLimit:]
+		}
+		return nil
+	})
+}
+
+// SaveOrWarn saves the operation like Save, but a failure only prints a warning to writer
+// because the operation itself has been performed
+func (op *Operation) SaveOrWarn(writer io.Writer) {
+	if err := op.Save(); err != nil {
+		fmt.Fprintf(writer, "Warning: failed to record operation in the journal: %v\n", err)
+	}
+}
+
+// ResolveRef returns the commit ref points to in the repository at repoRoot, or "" if it does not exist
+func ResolveRef(repoRoot, ref string) string {
+	sha, _ := journalExecutor(repoRoot).Execute("rev-parse", "--verify", "-q", ref)
+	return sha
+}
+
+type journalStore struct {
+	Version int            `json:"version"`
+	NextID  int            `json:"next_id"`
+	Entries []JournalEntry `json:"entries"`
+}
+
+// JournalPath returns the path of the journal file ($XDG_STATE_HOME/baretree/journal.json)
+func JournalPath() (string, error) {
+	stateDir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, journalFileName), nil
+}
+
+// ListJournal returns the journal entries of the repository at repoRoot (all entries if empty),
+// most recent first
+func ListJournal(repoRoot string) ([]JournalEntry, error) {
+	path, err := JournalPath()
+	if err != nil {
+		return nil, err
+	}
+	store, err := readJournal(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []JournalEntry
+	for i := len(store.Entries) - 1; i >= 0; i-- {
+		if repoRoot == "" || store.Entries[i].Repository == repoRoot {
+			entries = append(entries, store.Entries[i])
+		}
+	}
+	return entries, nil
+}
+
+// UndoJournalEntry reverts the steps of an entry in reverse order and marks it as undone.
+// Nothing is changed unless every step can still be reverted: refs must still point to the
+// commits the entry left them at, and moved or added worktrees must still be where it put them.
+func UndoJournalEntry(entry *JournalEntry, writer io.Writer) error {
+	if entry.UndoneAt != nil {
+		return fmt.Errorf("operation %d has already been undone", entry.ID)
+	}
+	for i := len(entry.Steps) - 1; i >= 0; i-- {
+		if err := checkUndo(entry.Steps[i]); err != nil {
+			return fmt.Errorf("cannot undo operation %d: %w", entry.ID, err)
+		}
+	}
+
+	repos := make(map[string]bool)
+	for i := len(entry.Steps) - 1; i >= 0; i-- {
+		step := entry.Steps[i]
+		if err := undoStep(step, writer); err != nil {
+			return fmt.Errorf("failed to undo '%s' (earlier steps were reverted): %w", step.Describe(), err)
+		}
+		fmt.Fprintf(writer, "✓ Reverted: %s\n", step.Describe())
+		if step.Action != StepCreateRepository {
+			repos[step.Repository] = true
+		}
+	}
+
+	for repoRoot := range repos {
+		if wtMgr, err := journalWorktreeManager(repoRoot); err == nil {
+			if err := wtMgr.RefreshEditorWorkspace(); err != nil {
+				fmt.Fprintf(writer, "Warning: failed to update editor workspace: %v\n", err)
+			}
+		}
+	}
+
+	return updateJournal(func(store *journalStore) error {
+		now := time.Now()
+		for i := range store.Entries {
+			if store.Entries[i].ID == entry.ID {
+				store.Entries[i].UndoneAt = &now
+				return nil
+			}
+		}
+		return fmt.Errorf("operation %d is no longer in the journal", entry.ID)
+	})
+}
+
+// checkUndo verifies that a step can be reverted without losing anything done since
+func checkUndo(step JournalStep) error {
+	switch step.Action {
+	case StepAddWorktree:
+		if _, err := os.Stat(step.Path); err != nil {
+			return fmt.Errorf("worktree %s no longer exists", step.Path)
+		}
+		wtMgr, err := journalWorktreeManager(step.Repository)
+		if err != nil {
+			return err
+		}
+		check, err := wtMgr.CheckRemoval(step.Path)
+		if err != nil {
+			return err
+		}
+		if len(check.Changed) > 0 || len(check.Untracked) > 0 {
+			return fmt.Errorf("worktree %s has uncommitted changes or untracked files", step.Path)
+		}
+	case StepRemoveWorktree:
+		if _, err := os.Lstat(step.Path); err == nil {
+			return fmt.Errorf("%s already exists", step.Path)
+		}
+	case StepMoveWorktree:
+		if _, err := os.Stat(step.Path); err != nil {
+			return fmt.Errorf("worktree %s no longer exists", step.Path)
+		}
+		if _, err := os.Lstat(step.OldPath); err == nil {
+			return fmt.Errorf("%s already exists", step.OldPath)
+		}
+	case StepRenameBranch:
+		if ResolveRef(step.Repository, "refs/heads/"+step.Branch) == "" {
+			return fmt.Errorf("branch '%s' no longer exists", step.Branch)
+		}
+		if ResolveRef(step.Repository, "refs/heads/"+step.OldBranch) != "" {
+			return fmt.Errorf("branch '%s' already exists", step.OldBranch)
+		}
+	case StepUpdateRef:
+		if current := ResolveRef(step.Repository, step.Ref); current != step.NewSHA {
+			return fmt.Errorf("%s has changed since (now %s)", step.Ref, orNone(shortHash(current)))
+		}
+	case StepTrash:
+		entry, err := FindTrashEntry(step.TrashID)
+		if err != nil || entry.ID != step.TrashID {
+			return fmt.Errorf("trash entry %s no longer exists", step.TrashID)
+		}
+		if _, err := os.Lstat(entry.OriginalPath); err == nil {
+			return fmt.Errorf("%s already exists", entry.OriginalPath)
+		}
+	case StepCreateRepository:
+		if _, err := os.Stat(step.Path); err != nil {
+			return fmt.Errorf("repository %s no longer exists", step.Path)
+		}
+	case StepIrreversible:
+		return fmt.Errorf("%s", step.Note)
+	default:
+		return fmt.Errorf("unknown step: %s", step.Action)
+	}
+	return nil
+}
+
+// undoStep reverts a single step
+func undoStep(step JournalStep, writer io.Writer) error {
+	executor := journalExecutor(step.Repository)
+	switch step.Action {
+	case StepAddWorktree:
+		wtMgr, err := journalWorktreeManager(step.Repository)
+		if err != nil {
+			return err
+		}
+		// Untracked post-create files were checked to match their sources
+		if err := wtMgr.Remove(step.Path, true); err != nil {
+			return err
+		}
+		removeEmptyParents(filepath.Dir(step.Path), step.Repository)
+
+	case StepRemoveWorktree:
+		wtMgr, err := journalWorktreeManager(step.Repository)
+		if err != nil {
+			return err
+		}
+		args := []string{"worktree", "add", step.Path}
+		switch {
+		case step.Branch == "":
+			args = []string{"worktree", "add", "--detach", step.Path, step.Head}
+		case ResolveRef(step.Repository, "refs/heads/"+step.Branch) == "":
+			args = []string{"worktree", "add", "-b", step.Branch, step.Path, step.Head}
+		default:
+			args = append(args, step.Branch)
+		}
+		if err := os.MkdirAll(filepath.Dir(step.Path), 0755); err != nil {
+			return err
+		}
+		if _, err := wtMgr.Executor.Execute(args...); err != nil {
+			return err
+		}
+		if _, err := wtMgr.ApplyPostCreateFiles(step.Path, writer); err != nil {
+			fmt.Fprintf(writer, "Warning: failed to re-apply post-create files: %v\n", err)
+		}
+
+	case StepMoveWorktree:
+		if err := os.MkdirAll(filepath.Dir(step.OldPath), 0755); err != nil {
+			return err
+		}
+		if err := os.Rename(step.Path, step.OldPath); err != nil {
+			return err
+		}
+		if _, err := executor.Execute("worktree", "repair", step.OldPath); err != nil {
+			return err
+		}
+		removeEmptyParents(filepath.Dir(step.Path), step.Repository)
+
+	case StepRenameBranch:
+		if _, err := executor.Execute("branch", "-m", step.Branch, step.OldBranch); err != nil {
+			return err
+		}
+
+	case StepUpdateRef:
+		// The current value is passed along, so git refuses if the ref changed in the meantime
+		var err error
+		if step.OldSHA == "" {
+			_, err = executor.Execute("update-ref", "-d", step.Ref, step.NewSHA)
+		} else {
+			_, err = executor.Execute("update-ref", step.Ref, step.OldSHA, step.NewSHA)
+		}
+		if err != nil {
+			return err
+		}
+
+	case StepTrash:
+		entry, err := FindTrashEntry(step.TrashID)
+		if err != nil {
+			return err
+		}
+		if err := RestoreTrashEntry(entry, writer); err != nil {
+			return err
+		}
+		if entry.Kind == TrashKindRepository {
+			if cfg, err := LoadConfig(); err == nil {
+				_ = UpdateIndex(cfg.Roots, entry.OriginalPath)
+			}
+		}
+
+	case StepCreateRepository:
+		name := filepath.Base(step.Path)
+		var roots []string
+		if cfg, err := LoadConfig(); err == nil {
+			roots = cfg.Roots
+			for _, root := range roots {
+				if rel, err := filepath.Rel(root, step.Path); err == nil && !strings.HasPrefix(rel, "..") {
+					name = rel
+					break
+				}
+			}
+		}
+		entry, err := TrashRepository(RepoInfo{Path: step.Path, RelativePath: name, Name: filepath.Base(step.Path)})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(writer, "  Moved %s to the trash (%s)\n", step.Path, entry.ID)
+		if roots != nil {
+			_ = UpdateIndex(roots, step.Path)
+		}
+	}
+	return nil
+}
+
+// journalExecutor returns a git executor for the bare repository of the repository at repoRoot
+func journalExecutor(repoRoot string) *git.Executor {
+	bareDir, err := repository.GetBareRepoPath(repoRoot)
+	if err != nil {
+		bareDir = filepath.Join(repoRoot, config.BareDir)
+	}
+	return git.NewExecutor(bareDir)
+}
+
+// journalWorktreeManager creates a worktree manager for the repository at repoRoot
+func journalWorktreeManager(repoRoot string) (*worktree.Manager, error) {
+	mgr, err := repository.NewManager(repoRoot)
+	if err != nil {
+		return nil, fmt.Errorf("repository %s is gone: %w", repoRoot, err)
+	}
+	return worktree.NewManager(repoRoot, mgr.BareDir, mgr.Config), nil
+}
+
+// removeEmptyParents removes empty directories from dir up to (but not including) stop
+func removeEmptyParents(dir, stop string) {
+	for strings.HasPrefix(dir, stop+string(filepath.Separator)) {
+		if err := os.Remove(dir); err != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// orNone returns s, or "none" if s is empty
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+// updateJournal applies fn to the journal while holding its lock and saves the result
+func updateJournal(fn func(store *journalStore) error) error {
+	path, err := JournalPath()
+	if err != nil {
+		return err
+	}
+	return withFileLock(path, func() error {
+		store, err := readJournal(path)
+		if err != nil {
+			return err
+		}
+		if err := fn(store); err != nil {
+			return err
+		}
+		data, err := json.MarshalIndent(store, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeFileAtomic(path, append(data, '\n'))
+	})
+}
+
+// readJournal reads the journal; a missing journal is empty
+func readJournal(path string) (*journalStore, error) {
+	store := &journalStore{Version: journalVersion}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if store.Version != journalVersion {
+		return nil, fmt.Errorf("unsupported journal version %d in %s", store.Version, path)
+	}
+	return store, nil
+}
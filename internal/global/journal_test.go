@@ -0,0 +1,178 @@
+package global
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/amaya382/baretree/internal/repository"
+)
+
+func TestJournalSaveAndList(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	// Operations without steps are not recorded
+	if err := NewOperation("/repo/a").Save(); err != nil {
+		t.Fatal(err)
+	}
+	if entries, err := ListJournal(""); err != nil || len(entries) != 0 {
+		t.Fatalf("ListJournal() = %+v, %v; want no entries", entries, err)
+	}
+
+	for _, repo := range []string{"/repo/a", "/repo/b", "/repo/a"} {
+		op := NewOperation(repo)
+		op.Record(JournalStep{Action: StepRenameBranch, OldBranch: "x", Branch: "y"})
+		if err := op.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err := ListJournal("/repo/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].ID != 3 || entries[1].ID != 1 {
+		t.Fatalf("ListJournal(/repo/a) should list entries 3 and 1, got %+v", entries)
+	}
+	if entries[0].Steps[0].Repository != "/repo/a" {
+		t.Errorf("step should inherit the repository, got %q", entries[0].Steps[0].Repository)
+	}
+	if got := entries[0].Steps[0].Describe(); got != "renamed branch x -> y" {
+		t.Errorf("Describe() = %q", got)
+	}
+
+	// The journal keeps only the most recent operations
+	for i := 0; i < JournalLimit; i++ {
+		op := NewOperation("/repo/c")
+		op.Record(JournalStep{Action: StepIrreversible, Note: "note"})
+		if err := op.Save(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	all, err := ListJournal("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != JournalLimit || all[0].ID != JournalLimit+3 {
+		t.Errorf("journal should keep %d entries up to ID %d, got %d up to %d", JournalLimit, JournalLimit+3, len(all), all[0].ID)
+	}
+	if all[0].Reversible() {
+		t.Error("an entry with an irreversible step should not be reversible")
+	}
+	if err := UndoJournalEntry(&all[0], io.Discard); err == nil || !strings.Contains(err.Error(), "note") {
+		t.Errorf("UndoJournalEntry() should refuse an irreversible step, got %v", err)
+	}
+}
+
+func TestUndoRenameAndMove(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	repo := createWorktreeTestRepo(t, t.TempDir(), "example.com/user/project")
+	bareDir, err := repository.GetBareRepoPath(repo.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	oldPath := filepath.Join(repo.Path, "feature", "x")
+	newPath := filepath.Join(repo.Path, "feature", "y")
+	runTestGit(t, bareDir, "worktree", "add", "-b", "feature/x", oldPath, "main")
+
+	// What 'bt rename feature/x feature/y' does
+	op := NewOperation(repo.Path)
+	runTestGit(t, bareDir, "branch", "-m", "feature/x", "feature/y")
+	op.Record(JournalStep{Action: StepRenameBranch, OldBranch: "feature/x", Branch: "feature/y"})
+	runTestGit(t, bareDir, "worktree", "move", oldPath, newPath)
+	op.Record(JournalStep{Action: StepMoveWorktree, OldPath: oldPath, Path: newPath})
+	if err := op.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := ListJournal(repo.Path)
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("ListJournal() = %+v, %v", entries, err)
+	}
+	if err := UndoJournalEntry(&entries[0], io.Discard); err != nil {
+		t.Fatalf("UndoJournalEntry() error = %v", err)
+	}
+
+	if got := gitOutput(t, oldPath, "symbolic-ref", "--short", "HEAD"); got != "feature/x" {
+		t.Errorf("branch after undo = %s, want feature/x", got)
+	}
+	if _, err := os.Stat(newPath); !os.IsNotExist(err) {
+		t.Errorf("%s should be gone, stat error = %v", newPath, err)
+	}
+	if list := gitOutput(t, bareDir, "worktree", "list"); !strings.Contains(list, oldPath) {
+		t.Errorf("worktree should be registered at %s:\n%s", oldPath, list)
+	}
+
+	entries, _ = ListJournal(repo.Path)
+	if entries[0].UndoneAt == nil {
+		t.Error("entry should be marked as undone")
+	}
+	if err := UndoJournalEntry(&entries[0], io.Discard); err == nil {
+		t.Error("undoing twice should fail")
+	}
+}
+
+func TestUndoAddWorktree(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	repo := createWorktreeTestRepo(t, t.TempDir(), "example.com/user/project")
+	bareDir, err := repository.GetBareRepoPath(repo.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// What 'bt add -b feature/new' does
+	wtPath := filepath.Join(repo.Path, "feature", "new")
+	ref := "refs/heads/feature/new"
+	op := NewOperation(repo.Path)
+	oldSHA := ResolveRef(repo.Path, ref)
+	runTestGit(t, bareDir, "worktree", "add", "-b", "feature/new", wtPath, "main")
+	op.RecordRef(repo.Path, ref, oldSHA)
+	op.Record(JournalStep{Action: StepAddWorktree, Path: wtPath, Branch: "feature/new"})
+	if err := op.Save(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ListJournal(repo.Path)
+	if err != nil || len(entries) != 1 || len(entries[0].Steps) != 2 {
+		t.Fatalf("ListJournal() = %+v, %v; want one entry with a ref and a worktree step", entries, err)
+	}
+
+	// A commit made since then would be lost, so nothing is changed
+	runTestGit(t, wtPath, "commit", "--allow-empty", "-m", "new work")
+	if err := UndoJournalEntry(&entries[0], io.Discard); err == nil || !strings.Contains(err.Error(), "has changed since") {
+		t.Fatalf("UndoJournalEntry() should refuse a moved branch, got %v", err)
+	}
+	if _, err := os.Stat(wtPath); err != nil {
+		t.Fatalf("worktree should be untouched: %v", err)
+	}
+
+	runTestGit(t, wtPath, "reset", "--hard", "HEAD~1")
+	if err := UndoJournalEntry(&entries[0], io.Discard); err != nil {
+		t.Fatalf("UndoJournalEntry() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(repo.Path, "feature")); !os.IsNotExist(err) {
+		t.Errorf("worktree and its empty parent should be gone, stat error = %v", err)
+	}
+	if sha := ResolveRef(repo.Path, ref); sha != "" {
+		t.Errorf("branch should be deleted, points to %s", sha)
+	}
+}
+
+func TestOperationSaveOrWarn(t *testing.T) {
+	// A file in place of the state directory makes saving fail
+	stateFile := filepath.Join(t.TempDir(), "state")
+	if err := os.WriteFile(stateFile, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_STATE_HOME", stateFile)
+
+	op := NewOperation("/repo/a")
+	op.Record(JournalStep{Action: StepCreateRepository, Path: "/repo/a"})
+	var out strings.Builder
+	op.SaveOrWarn(&out)
+	if !strings.Contains(out.String(), "Warning: failed to record operation in the journal") {
+		t.Errorf("SaveOrWarn() should print a warning, got %q", out.String())
+	}
+}
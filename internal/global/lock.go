@@ -0,0 +1,63 @@
+package global
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// lockTimeout is how long to wait for another process to release a lock
+	lockTimeout = 5 * time.Second
+	// staleLockAge is the age after which a lock is assumed to be left behind by a crashed process
+	staleLockAge = 30 * time.Second
+)
+
+// withFileLock runs fn while holding an exclusive lock on path (a "<path>.lock" file created
+// with O_EXCL, which works the same on every platform). Locks older than staleLockAge are broken.
+func withFileLock(path string, fn func() error) error {
+	lockPath := path + ".lock"
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0755); err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			break
+		}
+		if !os.IsExist(err) {
+			return fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			breakStaleLock(lockPath, info)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for lock %s", lockPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	defer os.Remove(lockPath)
+
+	return fn()
+}
+
+// breakStaleLock removes the stale lock described by info. The lock is first renamed to a name
+// unique to this process, so that two processes cannot both break it. If another process has
+// replaced it with a fresh lock since info was taken, that lock is put back instead.
+func breakStaleLock(lockPath string, info os.FileInfo) {
+	stalePath := fmt.Sprintf("%s.stale.%d.%d", lockPath, os.Getpid(), time.Now().UnixNano())
+	if err := os.Rename(lockPath, stalePath); err != nil {
+		return
+	}
+	// Inode numbers can be reused, so the lock must also still be stale
+	if moved, err := os.Stat(stalePath); err == nil && (!os.SameFile(info, moved) || time.Since(moved.ModTime()) <= staleLockAge) {
+		// Fails only if yet another process has taken the lock in the meantime
+		_ = os.Link(stalePath, lockPath)
+	}
+	os.Remove(stalePath)
+}
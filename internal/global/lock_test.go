@@ -0,0 +1,59 @@
+package global
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWithFileLockBreaksStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.json")
+	lockPath := path + ".lock"
+	if err := os.WriteFile(lockPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleLockAge)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	ran := false
+	if err := withFileLock(path, func() error { ran = true; return nil }); err != nil {
+		t.Fatalf("withFileLock() error = %v", err)
+	}
+	if !ran {
+		t.Error("fn should run after the stale lock is broken")
+	}
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("lock should be released, stat error = %v", err)
+	}
+}
+
+func TestBreakStaleLockKeepsFreshLock(t *testing.T) {
+	lockPath := filepath.Join(t.TempDir(), "state.json.lock")
+	if err := os.WriteFile(lockPath, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	staleInfo, err := os.Stat(lockPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Another process breaks the stale lock and takes a fresh one before this one acts
+	if err := os.Remove(lockPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lockPath, []byte("fresh"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	breakStaleLock(lockPath, staleInfo)
+	data, err := os.ReadFile(lockPath)
+	if err != nil || string(data) != "fresh" {
+		t.Errorf("fresh lock should be kept, got %q, %v", data, err)
+	}
+	if matches, _ := filepath.Glob(lockPath + ".stale.*"); len(matches) != 0 {
+		t.Errorf("renamed lock should be cleaned up: %v", matches)
+	}
+}
@@ -0,0 +1,178 @@
+package global
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/amaya382/baretree/internal/config"
+	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
+)
+
+// ManifestVersion is the current version of the repository manifest format
+const ManifestVersion = 1
+
+// Manifest formats
+const (
+	ManifestFormatTOML = "toml"
+	ManifestFormatJSON = "json"
+)
+
+// Manifest describes every repository under the baretree roots so that the
+// whole managed tree can be restored on another machine
+type Manifest struct {
+	Version      int                  `toml:"version" json:"version"`
+	Repositories []ManifestRepository `toml:"repository" json:"repositories"`
+}
+
+// ManifestRepository describes a single repository in a manifest
+type ManifestRepository struct {
+	// Path is the repository path relative to its root, always slash-separated (e.g., "github.com/user/repo")
+	Path          string           `toml:"path" json:"path"`
+	DefaultBranch string           `toml:"default_branch" json:"default_branch"`
+	Remotes       []ManifestRemote `toml:"remote" json:"remotes"`
+	// Worktrees lists the branches checked out in worktrees other than the default branch
+	Worktrees []string       `toml:"worktrees" json:"worktrees"`
+	Config    *config.Config `toml:"config,omitempty" json:"config,omitempty"`
+}
+
+// ManifestRemote is a git remote of a repository
+type ManifestRemote struct {
+	Name string `toml:"name" json:"name"`
+	URL  string `toml:"url" json:"url"`
+}
+
+// CloneRemote returns the remote to clone from: origin if present, otherwise the first remote
+func (r *ManifestRepository) CloneRemote() *ManifestRemote {
+	for i := range r.Remotes {
+		if r.Remotes[i].Name == "origin" {
+			return &r.Remotes[i]
+		}
+	}
+	if len(r.Remotes) > 0 {
+		return &r.Remotes[0]
+	}
+	return nil
+}
+
+// ExportManifest builds a manifest of the given repositories, inspecting up to
+// DefaultListJobs repositories concurrently. Repositories that cannot be read are
+// left out of the manifest and reported in errs.
+func ExportManifest(repos []RepoInfo) (*Manifest, []error) {
+	entries := make([]ManifestRepository, len(repos))
+	entryErrs := make([]error, len(repos))
+	runParallel(len(repos), DefaultListJobs, func(i int) {
+		entries[i], entryErrs[i] = exportManifestRepository(repos[i])
+	})
+
+	manifest := &Manifest{Version: ManifestVersion, Repositories: []ManifestRepository{}}
+	var errs []error
+	for i, err := range entryErrs {
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", repos[i].RelativePath, err))
+			continue
+		}
+		manifest.Repositories = append(manifest.Repositories, entries[i])
+	}
+	return manifest, errs
+}
+
+// exportManifestRepository reads the remotes, worktrees and configuration of a repository
+func exportManifestRepository(repo RepoInfo) (ManifestRepository, error) {
+	bareDir, err := repository.GetBareRepoPath(repo.Path)
+	if err != nil {
+		return ManifestRepository{}, err
+	}
+
+	mgr, err := repository.NewManager(repo.Path)
+	if err != nil {
+		return ManifestRepository{}, err
+	}
+
+	entry := ManifestRepository{
+		Path:          filepath.ToSlash(repo.RelativePath),
+		DefaultBranch: mgr.Config.Repository.DefaultBranch,
+		Remotes:       []ManifestRemote{},
+		Worktrees:     []string{},
+		Config:        mgr.Config,
+	}
+
+	executor := git.NewExecutor(bareDir)
+	remotes, err := executor.ListRemotes()
+	if err != nil {
+		return ManifestRepository{}, fmt.Errorf("failed to list remotes: %w", err)
+	}
+	for _, remote := range remotes {
+		remoteURL, err := executor.Execute("config", "--get", "remote."+remote+".url")
+		if err != nil || remoteURL == "" {
+			continue
+		}
+		entry.Remotes = append(entry.Remotes, ManifestRemote{Name: remote, URL: remoteURL})
+	}
+
+	wtMgr := worktree.NewManager(repo.Path, bareDir, mgr.Config)
+	worktrees, err := wtMgr.List()
+	if err != nil {
+		return ManifestRepository{}, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	for _, wt := range worktrees {
+		if wt.IsBare || wt.Branch == "" || wt.Branch == entry.DefaultBranch {
+			continue
+		}
+		entry.Worktrees = append(entry.Worktrees, wt.Branch)
+	}
+
+	return entry, nil
+}
+
+// EncodeManifest encodes a manifest in the given format ("toml" or "json")
+func EncodeManifest(manifest *Manifest, format string) (string, error) {
+	var buf bytes.Buffer
+	switch format {
+	case ManifestFormatTOML:
+		if err := toml.NewEncoder(&buf).Encode(manifest); err != nil {
+			return "", fmt.Errorf("failed to encode manifest: %w", err)
+		}
+	case ManifestFormatJSON:
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(manifest); err != nil {
+			return "", fmt.Errorf("failed to encode manifest: %w", err)
+		}
+	default:
+		return "", fmt.Errorf("unsupported manifest format: %s (use %s or %s)", format, ManifestFormatTOML, ManifestFormatJSON)
+	}
+	return buf.String(), nil
+}
+
+// DecodeManifest decodes a TOML or JSON manifest (JSON is detected by a leading '{')
+func DecodeManifest(data []byte) (*Manifest, error) {
+	var manifest Manifest
+	if strings.HasPrefix(strings.TrimSpace(string(data)), "{") {
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON manifest: %w", err)
+		}
+	} else {
+		if err := toml.Unmarshal(data, &manifest); err != nil {
+			return nil, fmt.Errorf("failed to parse TOML manifest: %w", err)
+		}
+	}
+
+	if manifest.Version > ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d (this bt supports up to %d)", manifest.Version, ManifestVersion)
+	}
+	for i, repo := range manifest.Repositories {
+		if repo.Path == "" || filepath.IsAbs(repo.Path) || strings.Contains("/"+repo.Path+"/", "/../") {
+			return nil, fmt.Errorf("invalid repository path in manifest: %q", repo.Path)
+		}
+		if repo.DefaultBranch == "" {
+			manifest.Repositories[i].DefaultBranch = "main"
+		}
+	}
+	return &manifest, nil
+}
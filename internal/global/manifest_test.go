@@ -0,0 +1,138 @@
+package global
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/amaya382/baretree/internal/config"
+)
+
+func TestExportManifest(t *testing.T) {
+	root := t.TempDir()
+	repo := createWorktreeTestRepo(t, root, "example.com/user/app")
+	bareDir := filepath.Join(repo.Path, ".git")
+	runTestGit(t, bareDir, "worktree", "add", "-b", "feature/x", filepath.Join(repo.Path, "feature", "x"), "main")
+
+	missing := RepoInfo{Path: filepath.Join(root, "missing"), RelativePath: "missing", Name: "missing"}
+
+	manifest, errs := ExportManifest([]RepoInfo{repo, missing})
+	if len(errs) != 1 || !strings.Contains(errs[0].Error(), "missing") {
+		t.Errorf("expected one error for the missing repository, got %v", errs)
+	}
+	if manifest.Version != ManifestVersion {
+		t.Errorf("Version = %d, want %d", manifest.Version, ManifestVersion)
+	}
+	if len(manifest.Repositories) != 1 {
+		t.Fatalf("expected 1 repository, got %d", len(manifest.Repositories))
+	}
+
+	entry := manifest.Repositories[0]
+	if entry.Path != "example.com/user/app" {
+		t.Errorf("Path = %q, want example.com/user/app", entry.Path)
+	}
+	if entry.DefaultBranch != "main" {
+		t.Errorf("DefaultBranch = %q, want main", entry.DefaultBranch)
+	}
+	if len(entry.Remotes) != 1 || entry.Remotes[0].Name != "origin" {
+		t.Errorf("Remotes = %v, want origin only", entry.Remotes)
+	}
+	if !reflect.DeepEqual(entry.Worktrees, []string{"feature/x"}) {
+		t.Errorf("Worktrees = %v, want [feature/x]", entry.Worktrees)
+	}
+}
+
+func TestManifestRoundTrip(t *testing.T) {
+	manifest := &Manifest{
+		Version: ManifestVersion,
+		Repositories: []ManifestRepository{
+			{
+				Path:          "github.com/user/repo",
+				DefaultBranch: "develop",
+				Remotes: []ManifestRemote{
+					{Name: "upstream", URL: "git@github.com:org/repo.git"},
+					{Name: "origin", URL: "git@github.com:user/repo.git"},
+				},
+				Worktrees: []string{"feature/a"},
+				Config: &config.Config{
+					Repository: config.Repository{DefaultBranch: "develop"},
+					PostCreate: []config.PostCreateAction{{Source: ".env", Type: "symlink"}},
+				},
+			},
+		},
+	}
+
+	for _, format := range []string{ManifestFormatTOML, ManifestFormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			encoded, err := EncodeManifest(manifest, format)
+			if err != nil {
+				t.Fatalf("EncodeManifest failed: %v", err)
+			}
+			decoded, err := DecodeManifest([]byte(encoded))
+			if err != nil {
+				t.Fatalf("DecodeManifest failed: %v\n%s", err, encoded)
+			}
+			if !reflect.DeepEqual(decoded, manifest) {
+				t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", decoded, manifest)
+			}
+			if remote := decoded.Repositories[0].CloneRemote(); remote == nil || remote.Name != "origin" {
+				t.Errorf("CloneRemote() = %v, want origin", remote)
+			}
+		})
+	}
+
+	if _, err := EncodeManifest(manifest, "yaml"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
+
+func TestDecodeManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:  "default branch defaults to main",
+			input: "version = 1\n[[repository]]\npath = \"github.com/user/repo\"\n",
+		},
+		{
+			name:    "newer version",
+			input:   "version = 2\n",
+			wantErr: true,
+		},
+		{
+			name:    "absolute path",
+			input:   "version = 1\n[[repository]]\npath = \"/etc/repo\"\n",
+			wantErr: true,
+		},
+		{
+			name:    "path escaping the root",
+			input:   `{"version": 1, "repositories": [{"path": "github.com/../../repo"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "empty path",
+			input:   `{"version": 1, "repositories": [{"path": ""}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "invalid TOML",
+			input:   "version = ",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest, err := DecodeManifest([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("DecodeManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && manifest.Repositories[0].DefaultBranch != "main" {
+				t.Errorf("DefaultBranch = %q, want main", manifest.Repositories[0].DefaultBranch)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package global
+
+import "sync"
+
+// runParallel calls fn for every index in [0, count) using at most jobs goroutines
+func runParallel(count, jobs int, fn func(i int)) {
+	if jobs <= 0 {
+		jobs = 1
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < jobs && w < count; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < count; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+}
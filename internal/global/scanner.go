@@ -1,9 +1,11 @@
 package global
 
 import (
-	"os"
+	"fmt"
 	"path/filepath"
 	"strings"
+
+	"github.com/amaya382/baretree/internal/fuzzy"
 )
 
 // RepoInfo holds information about a discovered repository
@@ -16,54 +18,18 @@ type RepoInfo struct {
 	Name string
 }
 
-// ScanRepositories scans the given root directories for baretree repositories
+// ScanRepositories scans the given root directories for baretree repositories.
+// It always walks the roots; use ListRepositories to read them from the repository index.
 func ScanRepositories(roots []string) ([]RepoInfo, error) {
-	var repos []RepoInfo
-	seen := make(map[string]bool)
-
+	index := &repoIndex{Version: indexVersion, Roots: make(map[string]*rootIndex)}
 	for _, root := range roots {
-		if _, err := os.Stat(root); os.IsNotExist(err) {
-			continue
-		}
-
-		err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
-			if err != nil {
-				return nil // Skip directories we can't access
-			}
-
-			if !d.IsDir() {
-				return nil
-			}
-
-			// Skip hidden directories (except the root itself)
-			if path != root && strings.HasPrefix(d.Name(), ".") {
-				return filepath.SkipDir
-			}
-
-			// Check if this directory contains a .git directory (is a repository)
-			gitDir := filepath.Join(path, ".git")
-			if info, err := os.Stat(gitDir); err == nil && info.IsDir() {
-				if !seen[path] {
-					seen[path] = true
-					relPath, _ := filepath.Rel(root, path)
-					repos = append(repos, RepoInfo{
-						Path:         path,
-						RelativePath: relPath,
-						Name:         filepath.Base(path),
-					})
-				}
-				return filepath.SkipDir // Don't descend into repositories
-			}
-
-			return nil
-		})
-
+		ri, err := scanRoot(root)
 		if err != nil {
 			return nil, err
 		}
+		index.Roots[root] = ri
 	}
-
-	return repos, nil
+	return index.repositories(roots), nil
 }
 
 // FilterRepositories filters repositories by a query string.
@@ -93,3 +59,102 @@ func FilterRepositories(repos []RepoInfo, query string) []RepoInfo {
 	// Return prefix matches first, then substring matches
 	return append(prefixMatches, substringMatches...)
 }
+
+// ResolveRepository finds a repository matching the query.
+// Resolution order:
+// 1. Exact match on full relative path (github.com/org/repo)
+// 2. Exact match on org/repo
+// 3. Exact match on repo name (returns error if multiple matches)
+// 4. Partial match (returns error if multiple matches)
+// Returns the matching repository, list of ambiguous matches (if any), and error.
+func ResolveRepository(repos []RepoInfo, query string) (*RepoInfo, []RepoInfo, error) {
+	return resolveRepository(repos, query, false, nil)
+}
+
+// ResolveRepositoryFuzzy finds a repository like ResolveRepository, but also matches the
+// characters of the query in order (e.g. "bt" for baretree) if nothing contains the query.
+// Multiple matches are decided by score (frecency); score may be nil.
+func ResolveRepositoryFuzzy(repos []RepoInfo, query string, score func(path string) float64) (*RepoInfo, []RepoInfo, error) {
+	return resolveRepository(repos, query, true, score)
+}
+
+func resolveRepository(repos []RepoInfo, query string, fuzzyMatch bool, score func(path string) float64) (*RepoInfo, []RepoInfo, error) {
+	query = strings.ToLower(query)
+	queryParts := strings.Split(query, "/")
+
+	// pick returns the single match, or the best-scored one among several
+	pick := func(matches []RepoInfo) (*RepoInfo, []RepoInfo, error) {
+		if len(matches) == 1 {
+			return &matches[0], nil, nil
+		}
+		if score != nil {
+			scores := make([]float64, len(matches))
+			for i := range matches {
+				scores[i] = score(matches[i].Path)
+			}
+			if best := fuzzy.Best(scores); best >= 0 {
+				return &matches[best], nil, nil
+			}
+		}
+		return nil, matches, fmt.Errorf("ambiguous repository name '%s': %d matches found", query, len(matches))
+	}
+
+	// 1. Exact match on full relative path
+	for i := range repos {
+		if strings.ToLower(filepath.ToSlash(repos[i].RelativePath)) == query {
+			return &repos[i], nil, nil
+		}
+	}
+
+	// 2. Exact match on org/repo (last two components)
+	if len(queryParts) == 2 {
+		for i := range repos {
+			relParts := strings.Split(repos[i].RelativePath, string(filepath.Separator))
+			if len(relParts) >= 2 {
+				orgRepo := strings.ToLower(relParts[len(relParts)-2] + "/" + relParts[len(relParts)-1])
+				if orgRepo == query {
+					return &repos[i], nil, nil
+				}
+			}
+		}
+	}
+
+	// 3. Exact match on repo name only - collect all matches
+	if len(queryParts) == 1 {
+		var matches []RepoInfo
+		for i := range repos {
+			if strings.ToLower(repos[i].Name) == query {
+				matches = append(matches, repos[i])
+			}
+		}
+		if len(matches) > 0 {
+			return pick(matches)
+		}
+	}
+
+	// 4. Partial match (contains query) - collect all matches
+	var matches []RepoInfo
+	for i := range repos {
+		if strings.Contains(strings.ToLower(filepath.ToSlash(repos[i].RelativePath)), query) ||
+			strings.Contains(strings.ToLower(repos[i].Name), query) {
+			matches = append(matches, repos[i])
+		}
+	}
+	if len(matches) > 0 {
+		return pick(matches)
+	}
+
+	// 5. Fuzzy match (characters of the query in order)
+	if fuzzyMatch {
+		for i := range repos {
+			if fuzzy.Match(filepath.ToSlash(repos[i].RelativePath), query) {
+				matches = append(matches, repos[i])
+			}
+		}
+		if len(matches) > 0 {
+			return pick(matches)
+		}
+	}
+
+	return nil, nil, fmt.Errorf("repository not found: %s", query)
+}
@@ -1,6 +1,7 @@
 package global
 
 import (
+	"path/filepath"
 	"testing"
 )
 
@@ -136,3 +137,45 @@ func TestFilterRepositories_PrefixPriority(t *testing.T) {
 		}
 	})
 }
+
+func TestResolveRepository(t *testing.T) {
+	repos := []RepoInfo{
+		{Path: "/root/github.com/org/api", RelativePath: filepath.Join("github.com", "org", "api"), Name: "api"},
+		{Path: "/root/github.com/other/api", RelativePath: filepath.Join("github.com", "other", "api"), Name: "api"},
+		{Path: "/root/github.com/org/baretree", RelativePath: filepath.Join("github.com", "org", "baretree"), Name: "baretree"},
+	}
+
+	tests := []struct {
+		query     string
+		want      string
+		ambiguous int
+	}{
+		{query: "github.com/org/api", want: "/root/github.com/org/api"},
+		{query: "other/api", want: "/root/github.com/other/api"},
+		{query: "api", ambiguous: 2},
+		{query: "bare", want: "/root/github.com/org/baretree"},
+		{query: "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			match, ambiguous, err := ResolveRepository(repos, tt.query)
+			if tt.want == "" {
+				if err == nil || len(ambiguous) != tt.ambiguous {
+					t.Fatalf("ResolveRepository(%q) = %v, %d ambiguous, %v; want an error with %d ambiguous", tt.query, match, len(ambiguous), err, tt.ambiguous)
+				}
+				return
+			}
+			if err != nil || match.Path != tt.want {
+				t.Fatalf("ResolveRepository(%q) = %v, %v; want %s", tt.query, match, err, tt.want)
+			}
+		})
+	}
+
+	// Fuzzy matching only applies to ResolveRepositoryFuzzy
+	if _, _, err := ResolveRepository(repos, "brtr"); err == nil {
+		t.Error("ResolveRepository should not match fuzzily")
+	}
+	if match, _, err := ResolveRepositoryFuzzy(repos, "brtr", nil); err != nil || match.Name != "baretree" {
+		t.Errorf("ResolveRepositoryFuzzy(brtr) = %v, %v", match, err)
+	}
+}
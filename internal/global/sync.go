@@ -0,0 +1,88 @@
+package global
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/amaya382/baretree/internal/git"
+)
+
+const (
+	// DefaultSyncJobs is the default number of repositories fetched concurrently
+	DefaultSyncJobs = 8
+	// DefaultSyncTimeout is the default time limit for fetching a single repository
+	DefaultSyncTimeout = 5 * time.Minute
+)
+
+// SyncOptions configures SyncRepositories
+type SyncOptions struct {
+	// Jobs is the maximum number of concurrent fetches (DefaultSyncJobs if <= 0)
+	Jobs int
+	// Timeout is the per-repository time limit (no limit if <= 0)
+	Timeout time.Duration
+	// Prune removes remote-tracking refs that no longer exist on the remote
+	Prune bool
+}
+
+// SyncResult is the outcome of fetching a single repository
+type SyncResult struct {
+	Repo     RepoInfo
+	Err      error
+	Duration time.Duration
+	TimedOut bool
+}
+
+// SyncRepositories runs 'git fetch --all' in every repository using a bounded worker pool.
+// onDone (if non-nil) is called once per repository as soon as its fetch finishes; calls
+// are serialized, so it may print progress without extra locking.
+// Results are returned in the same order as repos.
+func SyncRepositories(repos []RepoInfo, opts SyncOptions, onDone func(SyncResult)) []SyncResult {
+	jobs := opts.Jobs
+	if jobs <= 0 {
+		jobs = DefaultSyncJobs
+	}
+
+	results := make([]SyncResult, len(repos))
+	var mu sync.Mutex
+
+	runParallel(len(repos), jobs, func(i int) {
+		result := fetchRepository(repos[i], opts)
+
+		mu.Lock()
+		defer mu.Unlock()
+		results[i] = result
+		if onDone != nil {
+			onDone(result)
+		}
+	})
+
+	return results
+}
+
+// fetchRepository fetches all remotes of a single repository
+func fetchRepository(repo RepoInfo, opts SyncOptions) SyncResult {
+	ctx := context.Background()
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	args := []string{"fetch", "--all"}
+	if opts.Prune {
+		args = append(args, "--prune")
+	}
+
+	start := time.Now()
+	executor := git.NewExecutor(filepath.Join(repo.Path, ".git"))
+	_, err := executor.ExecuteContext(ctx, args...)
+
+	return SyncResult{
+		Repo:     repo,
+		Err:      err,
+		Duration: time.Since(start),
+		TimedOut: ctx.Err() == context.DeadlineExceeded,
+	}
+}
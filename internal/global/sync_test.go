@@ -0,0 +1,105 @@
+package global
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// createSyncTestRepo creates a repository with a bare .git whose origin points to remoteURL
+func createSyncTestRepo(t *testing.T, root, name, remoteURL string) RepoInfo {
+	t.Helper()
+	repoPath := filepath.Join(root, name)
+	barePath := filepath.Join(repoPath, ".git")
+	if err := os.MkdirAll(barePath, 0755); err != nil {
+		t.Fatalf("failed to create repo dir: %v", err)
+	}
+	for _, args := range [][]string{
+		{"init", "--bare"},
+		{"remote", "add", "origin", remoteURL},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = barePath
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, output)
+		}
+	}
+	return RepoInfo{Path: repoPath, RelativePath: name, Name: name}
+}
+
+func TestSyncRepositories(t *testing.T) {
+	tempDir := t.TempDir()
+
+	upstream := filepath.Join(tempDir, "upstream.git")
+	cmd := exec.Command("git", "init", "--bare", upstream)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to create upstream: %v\n%s", err, output)
+	}
+
+	root := filepath.Join(tempDir, "root")
+	repos := []RepoInfo{
+		createSyncTestRepo(t, root, "good1", upstream),
+		createSyncTestRepo(t, root, "broken", filepath.Join(tempDir, "does-not-exist.git")),
+		createSyncTestRepo(t, root, "good2", upstream),
+	}
+
+	var callbacks int
+	results := SyncRepositories(repos, SyncOptions{Jobs: 2, Prune: true}, func(SyncResult) {
+		callbacks++
+	})
+
+	if callbacks != len(repos) {
+		t.Errorf("expected %d progress callbacks, got %d", len(repos), callbacks)
+	}
+	if len(results) != len(repos) {
+		t.Fatalf("expected %d results, got %d", len(repos), len(results))
+	}
+
+	for i, r := range results {
+		if r.Repo.Name != repos[i].Name {
+			t.Errorf("result %d: expected repo %q, got %q", i, repos[i].Name, r.Repo.Name)
+		}
+	}
+	if results[0].Err != nil || results[2].Err != nil {
+		t.Errorf("expected good repos to sync, got errors: %v / %v", results[0].Err, results[2].Err)
+	}
+	if results[1].Err == nil {
+		t.Error("expected broken repo to fail")
+	}
+	if results[1].TimedOut {
+		t.Error("broken repo should not be reported as timed out")
+	}
+}
+
+func TestSyncRepositoriesTimeout(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	root := t.TempDir()
+
+	// A remote helper that never answers and keeps git's output pipes open after git is killed
+	repo := createSyncTestRepo(t, root, "stuck", "ext::sh -c sleep% 30")
+	cmd := exec.Command("git", "config", "protocol.ext.allow", "always")
+	cmd.Dir = filepath.Join(repo.Path, ".git")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git config failed: %v\n%s", err, output)
+	}
+
+	start := time.Now()
+	results := SyncRepositories([]RepoInfo{repo}, SyncOptions{Jobs: 1, Timeout: 200 * time.Millisecond}, nil)
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Errorf("sync should stop shortly after the timeout, took %v", elapsed)
+	}
+	if !results[0].TimedOut || results[0].Err == nil {
+		t.Errorf("expected a timeout error, got %+v", results[0])
+	}
+}
+
+func TestSyncRepositoriesEmpty(t *testing.T) {
+	results := SyncRepositories(nil, SyncOptions{}, nil)
+	if len(results) != 0 {
+		t.Errorf("expected no results, got %d", len(results))
+	}
+}
@@ -0,0 +1,573 @@
+package global
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
+)
+
+// Kinds of trash entries
+const (
+	TrashKindWorktree   = "worktree"
+	TrashKindRepository = "repository"
+)
+
+const (
+	// trashEntryFile holds the metadata of a trash entry
+	trashEntryFile = "entry.json"
+	// trashDataDir holds the trashed directory itself
+	trashDataDir = "data"
+	// trashIndexFile is the saved git index (staged changes) of a trashed worktree
+	trashIndexFile = "index"
+	// trashMetadataFile is the saved lifecycle metadata of a trashed worktree (port, template hashes)
+	trashMetadataFile = "metadata.json"
+	// trashSharedDir holds copies of the managed post-create sources in .shared a trashed worktree used
+	trashSharedDir = "shared"
+	// trashRefPrefix keeps the commits of trashed worktrees reachable, even if their branch is deleted
+	trashRefPrefix = "refs/baretree/trash/"
+)
+
+// TrashEntry is a worktree or repository removed with 'bt rm' or 'bt repo rm' that can be restored
+type TrashEntry struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+	// Name is the branch of a worktree, or the repository path relative to its root
+	Name string `json:"name"`
+	// OriginalPath is where the worktree or repository is restored to
+	OriginalPath string `json:"original_path"`
+	// RepositoryPath is the repository a worktree belongs to (worktrees only)
+	RepositoryPath string `json:"repository_path,omitempty"`
+	// Branch and Head are the checked out branch (empty if detached) and commit (worktrees only)
+	Branch    string    `json:"branch,omitempty"`
+	Head      string    `json:"head,omitempty"`
+	DeletedAt time.Time `json:"deleted_at"`
+
+	dir string
+}
+
+// DataPath returns where the trashed directory is kept
+func (e *TrashEntry) DataPath() string {
+	return filepath.Join(e.dir, trashDataDir)
+}
+
+// TrashDir returns the directory holding trash entries ($XDG_STATE_HOME/baretree/trash)
+func TrashDir() (string, error) {
+	stateDir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, "trash"), nil
+}
+
+// ListTrash returns all trash entries, most recently deleted first.
+// Entries whose metadata cannot be read are skipped.
+func ListTrash() ([]TrashEntry, error) {
+	trashDir, err := TrashDir()
+	if err != nil {
+		return nil, err
+	}
+	dirs, err := os.ReadDir(trashDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trash: %w", err)
+	}
+
+	var entries []TrashEntry
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		entry, err := readTrashEntry(filepath.Join(trashDir, d.Name()))
+		if err != nil {
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// FindTrashEntry returns the trash entry with the given ID, the most recently deleted entry with
+// the given name, or the only entry whose ID starts with query
+func FindTrashEntry(query string) (*TrashEntry, error) {
+	entries, err := ListTrash()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		if entries[i].ID == query {
+			return &entries[i], nil
+		}
+	}
+	for i := range entries {
+		if entries[i].Name == query || entries[i].Name == filepath.FromSlash(query) {
+			return &entries[i], nil
+		}
+	}
+
+	var matches []*TrashEntry
+	for i := range entries {
+		if strings.HasPrefix(entries[i].ID, query) {
+			matches = append(matches, &entries[i])
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("trash entry not found: %s", query)
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("ambiguous trash entry '%s' matches %d entries", query, len(matches))
+	}
+}
+
+// TrashWorktree moves a worktree into the trash instead of deleting it. Its files are kept as
+// they are (including uncommitted and untracked files), its index, its metadata and the managed
+// .shared files it uses are saved, and its commit is kept reachable by a ref, so the branch can
+// be deleted.
+func TrashWorktree(wtMgr *worktree.Manager, worktreePath string) (*TrashEntry, error) {
+	executor := git.NewExecutor(worktreePath)
+	branch, _ := executor.Execute("symbolic-ref", "--short", "-q", "HEAD")
+	head, _ := executor.Execute("rev-parse", "--verify", "-q", "HEAD")
+	if head == "" {
+		return nil, fmt.Errorf("cannot move a worktree without commits to the trash")
+	}
+	adminDir, err := executor.Execute("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate worktree metadata: %w", err)
+	}
+
+	name := branch
+	if name == "" {
+		name = filepath.Base(worktreePath)
+	}
+	entry, err := newTrashEntry(TrashKindWorktree, name, worktreePath)
+	if err != nil {
+		return nil, err
+	}
+	entry.RepositoryPath = wtMgr.RepoRoot
+	entry.Branch = branch
+	entry.Head = head
+
+	ref := trashRefPrefix + entry.ID
+	if _, err := wtMgr.Executor.Execute("update-ref", ref, head); err != nil {
+		os.RemoveAll(entry.dir)
+		return nil, fmt.Errorf("failed to keep commit %s: %w", head, err)
+	}
+	discard := func() {
+		_, _ = wtMgr.Executor.Execute("update-ref", "-d", ref)
+		os.RemoveAll(entry.dir)
+	}
+
+	if err := copyTrashFile(filepath.Join(adminDir, "index"), filepath.Join(entry.dir, trashIndexFile)); err != nil && !os.IsNotExist(err) {
+		discard()
+		return nil, fmt.Errorf("failed to save index: %w", err)
+	}
+	if err := saveTrashMetadata(wtMgr, worktreePath, entry.dir); err != nil {
+		discard()
+		return nil, fmt.Errorf("failed to save worktree metadata: %w", err)
+	}
+	if err := saveTrashSharedFiles(wtMgr, entry.dir); err != nil {
+		discard()
+		return nil, fmt.Errorf("failed to save .shared files: %w", err)
+	}
+	if err := saveTrashEntry(entry); err != nil {
+		discard()
+		return nil, err
+	}
+	if err := moveTree(worktreePath, entry.DataPath()); err != nil {
+		discard()
+		return nil, fmt.Errorf("failed to move worktree to trash: %w", err)
+	}
+
+	// The directory is gone, so this only unregisters the worktree
+	if err := wtMgr.Remove(worktreePath, true); err != nil {
+		return entry, fmt.Errorf("worktree moved to trash but not unregistered: %w", err)
+	}
+	return entry, nil
+}
+
+// TrashRepository moves a whole repository (bare repository, worktrees and .shared) into the trash
+func TrashRepository(repo RepoInfo) (*TrashEntry, error) {
+	entry, err := newTrashEntry(TrashKindRepository, repo.RelativePath, repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := saveTrashEntry(entry); err != nil {
+		os.RemoveAll(entry.dir)
+		return nil, err
+	}
+	if err := moveTree(repo.Path, entry.DataPath()); err != nil {
+		os.RemoveAll(entry.dir)
+		return nil, fmt.Errorf("failed to move repository to trash: %w", err)
+	}
+	return entry, nil
+}
+
+// RestoreTrashEntry moves a trash entry back to its original path. A worktree is re-registered
+// with git on its branch (recreated if it was deleted), its index and metadata are restored,
+// managed .shared files that are gone are put back and post-create files that are missing are
+// applied again; writer receives their output.
+func RestoreTrashEntry(entry *TrashEntry, writer io.Writer) error {
+	if _, err := os.Lstat(entry.OriginalPath); err == nil {
+		return fmt.Errorf("cannot restore %s: %s already exists", entry.Name, entry.OriginalPath)
+	}
+
+	if entry.Kind == TrashKindRepository {
+		if err := os.MkdirAll(filepath.Dir(entry.OriginalPath), 0755); err != nil {
+			return err
+		}
+		if err := moveTree(entry.DataPath(), entry.OriginalPath); err != nil {
+			return fmt.Errorf("failed to restore repository: %w", err)
+		}
+		return os.RemoveAll(entry.dir)
+	}
+
+	return restoreWorktree(entry, writer)
+}
+
+// restoreWorktree restores a trashed worktree into a new registration of the same branch
+func restoreWorktree(entry *TrashEntry, writer io.Writer) error {
+	mgr, err := repository.NewManager(entry.RepositoryPath)
+	if err != nil {
+		return fmt.Errorf("repository of %s is gone: %w", entry.Name, err)
+	}
+	wtMgr := worktree.NewManager(entry.RepositoryPath, mgr.BareDir, mgr.Config)
+	ref := trashRefPrefix + entry.ID
+
+	// Register an empty worktree on the branch, recreating the branch if it was deleted
+	args := []string{"worktree", "add", "--no-checkout"}
+	if entry.Branch != "" {
+		tip, err := wtMgr.Executor.Execute("rev-parse", "--verify", "-q", "refs/heads/"+entry.Branch)
+		if err != nil {
+			if _, err := wtMgr.Executor.Execute("branch", entry.Branch, ref); err != nil {
+				return fmt.Errorf("failed to recreate branch '%s': %w", entry.Branch, err)
+			}
+			fmt.Fprintf(writer, "✓ Branch '%s' recreated at %s\n", entry.Branch, shortHash(entry.Head))
+		} else if tip != entry.Head {
+			fmt.Fprintf(writer, "Warning: branch '%s' moved from %s to %s since the worktree was removed\n", entry.Branch, shortHash(entry.Head), shortHash(tip))
+		}
+		args = append(args, entry.OriginalPath, entry.Branch)
+	} else {
+		args = append(args, "--detach", entry.OriginalPath, ref)
+	}
+	if err := os.MkdirAll(filepath.Dir(entry.OriginalPath), 0755); err != nil {
+		return err
+	}
+	if _, err := wtMgr.Executor.Execute(args...); err != nil {
+		return fmt.Errorf("failed to register worktree: %w", err)
+	}
+
+	// Move the files back, keeping the new .git file that points to the new registration
+	files, err := os.ReadDir(entry.DataPath())
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f.Name() == ".git" {
+			continue
+		}
+		if err := moveTree(filepath.Join(entry.DataPath(), f.Name()), filepath.Join(entry.OriginalPath, f.Name())); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", f.Name(), err)
+		}
+	}
+
+	// Restore staged changes, or index HEAD if the index was not saved
+	executor := git.NewExecutor(entry.OriginalPath)
+	adminDir, err := executor.Execute("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return err
+	}
+	if err := copyTrashFile(filepath.Join(entry.dir, trashIndexFile), filepath.Join(adminDir, "index")); err != nil {
+		if _, err := executor.Execute("read-tree", "HEAD"); err != nil {
+			return fmt.Errorf("failed to restore index: %w", err)
+		}
+	}
+	_, _ = executor.Execute("update-index", "-q", "--refresh")
+
+	if err := restoreTrashMetadata(wtMgr, entry); err != nil {
+		fmt.Fprintf(writer, "Warning: failed to restore worktree metadata: %v\n", err)
+	}
+	if err := restoreTrashSharedFiles(wtMgr, entry, writer); err != nil {
+		fmt.Fprintf(writer, "Warning: failed to restore .shared files: %v\n", err)
+	}
+	if _, err := wtMgr.ApplyPostCreateFiles(entry.OriginalPath, writer); err != nil {
+		fmt.Fprintf(writer, "Warning: failed to re-apply post-create files: %v\n", err)
+	}
+	_, _ = wtMgr.Executor.Execute("update-ref", "-d", ref)
+	return os.RemoveAll(entry.dir)
+}
+
+// saveTrashMetadata saves the lifecycle metadata of a worktree, which lives in its administrative
+// directory and is deleted when the worktree is unregistered
+func saveTrashMetadata(wtMgr *worktree.Manager, worktreePath, dir string) error {
+	md, err := wtMgr.LoadMetadata(worktreePath)
+	if err != nil || md == nil {
+		return err
+	}
+	data, err := json.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, trashMetadataFile), append(data, '\n'), 0644)
+}
+
+// restoreTrashMetadata writes the saved metadata to the restored worktree's new registration
+func restoreTrashMetadata(wtMgr *worktree.Manager, entry *TrashEntry) error {
+	data, err := os.ReadFile(filepath.Join(entry.dir, trashMetadataFile))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var md worktree.Metadata
+	if err := json.Unmarshal(data, &md); err != nil {
+		return err
+	}
+	return wtMgr.SaveMetadata(entry.OriginalPath, md)
+}
+
+// saveTrashSharedFiles copies the .shared sources of managed post-create actions, so a worktree
+// can be restored with them even if they are removed from .shared in the meantime
+func saveTrashSharedFiles(wtMgr *worktree.Manager, dir string) error {
+	if wtMgr.Config == nil {
+		return nil
+	}
+	for _, action := range wtMgr.Config.PostCreate {
+		if !action.Managed || action.Type == "command" {
+			continue
+		}
+		src := filepath.Join(wtMgr.GetSharedDir(), action.Source)
+		if _, err := os.Lstat(src); os.IsNotExist(err) {
+			continue
+		}
+		dst := filepath.Join(dir, trashSharedDir, action.Source)
+		if _, err := os.Lstat(dst); err == nil {
+			// Listed by more than one action
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := copyTree(src, dst); err != nil {
+			return fmt.Errorf("%s: %w", action.Source, err)
+		}
+	}
+	return nil
+}
+
+// restoreTrashSharedFiles copies saved .shared sources back where they no longer exist.
+// Files that are still there are left alone, as other worktrees may have changed them since.
+func restoreTrashSharedFiles(wtMgr *worktree.Manager, entry *TrashEntry, writer io.Writer) error {
+	savedDir := filepath.Join(entry.dir, trashSharedDir)
+	if _, err := os.Stat(savedDir); os.IsNotExist(err) {
+		return nil
+	}
+	sharedDir := wtMgr.GetSharedDir()
+	return filepath.WalkDir(savedDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || path == savedDir {
+			return err
+		}
+		rel, err := filepath.Rel(savedDir, path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(sharedDir, rel)
+		if _, err := os.Lstat(dst); err == nil {
+			// Still there; look for missing files inside a directory
+			return nil
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := copyTree(path, dst); err != nil {
+			return fmt.Errorf("%s: %w", rel, err)
+		}
+		fmt.Fprintf(writer, "✓ Restored %s/%s\n", worktree.SharedDir, filepath.ToSlash(rel))
+		if d.IsDir() {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+}
+
+// DeleteTrashEntry permanently deletes a trash entry
+func DeleteTrashEntry(entry *TrashEntry) error {
+	if entry.Kind == TrashKindWorktree {
+		// The commits become unreachable unless a branch still points to them (best effort:
+		// the repository may be gone)
+		_, _ = git.NewExecutor(entry.RepositoryPath).Execute("update-ref", "-d", trashRefPrefix+entry.ID)
+	}
+	if err := os.RemoveAll(entry.dir); err != nil {
+		return fmt.Errorf("failed to delete trash entry %s: %w", entry.ID, err)
+	}
+	return nil
+}
+
+// newTrashEntry creates the directory of a new trash entry with a unique, sortable ID
+// (e.g., 20240102-150405-feature-auth)
+func newTrashEntry(kind, name, originalPath string) (*TrashEntry, error) {
+	trashDir, err := TrashDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(trashDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create trash: %w", err)
+	}
+
+	now := time.Now()
+	slug := strings.NewReplacer("/", "-", "\\", "-", ":", "-", " ", "-").Replace(name)
+	base := now.Format("20060102-150405") + "-" + slug
+	id := base
+	for i := 2; ; i++ {
+		err := os.Mkdir(filepath.Join(trashDir, id), 0755)
+		if err == nil {
+			break
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to create trash entry: %w", err)
+		}
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+
+	return &TrashEntry{
+		ID:           id,
+		Kind:         kind,
+		Name:         name,
+		OriginalPath: originalPath,
+		DeletedAt:    now,
+		dir:          filepath.Join(trashDir, id),
+	}, nil
+}
+
+func saveTrashEntry(entry *TrashEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(entry.dir, trashEntryFile), append(data, '\n'))
+}
+
+func readTrashEntry(dir string) (*TrashEntry, error) {
+	data, err := os.ReadFile(filepath.Join(dir, trashEntryFile))
+	if err != nil {
+		return nil, err
+	}
+	var entry TrashEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	entry.dir = dir
+	return &entry, nil
+}
+
+// moveTree moves a file or directory, copying it if a rename is not possible
+// (e.g., when the trash is on another filesystem)
+func moveTree(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	if err := copyTree(src, dst); err != nil {
+		os.RemoveAll(dst)
+		return err
+	}
+	return os.RemoveAll(src)
+}
+
+// copyTree recursively copies a file or directory, recreating symlinks as they are
+func copyTree(src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case info.Mode()&os.ModeSymlink != 0:
+		link, err := os.Readlink(src)
+		if err != nil {
+			return err
+		}
+		return os.Symlink(link, dst)
+	case info.IsDir():
+		if err := os.MkdirAll(dst, info.Mode().Perm()); err != nil {
+			return err
+		}
+		entries, err := os.ReadDir(src)
+		if err != nil {
+			return err
+		}
+		for _, e := range entries {
+			if err := copyTree(filepath.Join(src, e.Name()), filepath.Join(dst, e.Name())); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return copyTrashFile(src, dst)
+	}
+}
+
+// copyTrashFile copies a regular file, keeping its permissions
+func copyTrashFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// shortHash abbreviates a commit hash for output
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// ParseTrashAge parses an age such as "14d", "12h" or "1h30m" (days are 24 hours)
+func ParseTrashAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid age: %s", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid age: %s (use e.g. 14d or 12h)", s)
+	}
+	return d, nil
+}
@@ -0,0 +1,274 @@
+package global
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amaya382/baretree/internal/config"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
+)
+
+// gitOutput runs a git command in dir and returns its trimmed output
+func gitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func TestTrashWorktreeRestore(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	repo := createWorktreeTestRepo(t, t.TempDir(), "example.com/user/project")
+	mgr, err := repository.NewManager(repo.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wtMgr := worktree.NewManager(repo.Path, mgr.BareDir, mgr.Config)
+
+	// A feature worktree with a local commit, a staged file and an untracked file
+	featurePath := filepath.Join(repo.Path, "feature", "x")
+	runTestGit(t, mgr.BareDir, "worktree", "add", "-b", "feature/x", featurePath, "main")
+	runTestGit(t, featurePath, "commit", "--allow-empty", "-m", "local work")
+	head := gitOutput(t, featurePath, "rev-parse", "HEAD")
+	if err := os.WriteFile(filepath.Join(featurePath, "staged.txt"), []byte("staged"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runTestGit(t, featurePath, "add", "staged.txt")
+	if err := os.WriteFile(filepath.Join(featurePath, "notes.txt"), []byte("notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := TrashWorktree(wtMgr, featurePath)
+	if err != nil {
+		t.Fatalf("TrashWorktree() error = %v", err)
+	}
+	if entry.Kind != TrashKindWorktree || entry.Name != "feature/x" || entry.Branch != "feature/x" || entry.Head != head {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if _, err := os.Stat(featurePath); !os.IsNotExist(err) {
+		t.Errorf("worktree should be gone, stat error = %v", err)
+	}
+	if list := gitOutput(t, mgr.BareDir, "worktree", "list"); strings.Contains(list, featurePath) {
+		t.Errorf("worktree should be unregistered:\n%s", list)
+	}
+
+	// The commit survives deleting the branch
+	runTestGit(t, mgr.BareDir, "branch", "-D", "feature/x")
+
+	if err := RestoreTrashEntry(entry, io.Discard); err != nil {
+		t.Fatalf("RestoreTrashEntry() error = %v", err)
+	}
+	if got := gitOutput(t, featurePath, "rev-parse", "HEAD"); got != head {
+		t.Errorf("restored HEAD = %s, want %s", got, head)
+	}
+	if got := gitOutput(t, featurePath, "symbolic-ref", "--short", "HEAD"); got != "feature/x" {
+		t.Errorf("restored branch = %s, want feature/x", got)
+	}
+	if got := gitOutput(t, featurePath, "status", "--porcelain"); got != "A  staged.txt\n?? notes.txt" {
+		t.Errorf("restored status = %q, want staged.txt staged and notes.txt untracked", got)
+	}
+
+	entries, err := ListTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("trash should be empty after restore, got %+v", entries)
+	}
+	if refs := gitOutput(t, mgr.BareDir, "for-each-ref", trashRefPrefix); refs != "" {
+		t.Errorf("trash ref should be deleted, got %s", refs)
+	}
+}
+
+func TestTrashWorktreeKeepsMetadataAndSharedFiles(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	repo := createWorktreeTestRepo(t, t.TempDir(), "example.com/user/project")
+	mgr, err := repository.NewManager(repo.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wtMgr := worktree.NewManager(repo.Path, mgr.BareDir, mgr.Config)
+	wtMgr.Config.PostCreate = []config.PostCreateAction{{Source: ".env", Type: "symlink", Managed: true}}
+	sharedFile := filepath.Join(wtMgr.GetSharedDir(), ".env")
+	if err := os.MkdirAll(filepath.Dir(sharedFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(sharedFile, []byte("SECRET=1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	featurePath, _, err := wtMgr.AddWithOptions("feature/x", worktree.AddOptions{NewBranch: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := worktree.Metadata{Port: 3107, Templates: map[string]string{"app.env": "abc"}}
+	if err := wtMgr.SaveMetadata(featurePath, md); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := TrashWorktree(wtMgr, featurePath)
+	if err != nil {
+		t.Fatalf("TrashWorktree() error = %v", err)
+	}
+	if err := os.Remove(sharedFile); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RestoreTrashEntry(entry, io.Discard); err != nil {
+		t.Fatalf("RestoreTrashEntry() error = %v", err)
+	}
+	got, err := wtMgr.LoadMetadata(featurePath)
+	if err != nil || got == nil {
+		t.Fatalf("LoadMetadata() = %v, %v", got, err)
+	}
+	if got.Port != md.Port || got.Templates["app.env"] != "abc" {
+		t.Errorf("restored metadata = %+v, want port %d and template hashes", got, md.Port)
+	}
+	if data, err := os.ReadFile(filepath.Join(featurePath, ".env")); err != nil || string(data) != "SECRET=1" {
+		t.Errorf("restored .shared/.env via symlink = %q, %v", data, err)
+	}
+}
+
+func TestTrashRepositoryRestore(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	repo := createWorktreeTestRepo(t, t.TempDir(), "example.com/user/project")
+	sharedFile := filepath.Join(repo.Path, ".shared", ".env")
+	if err := os.MkdirAll(filepath.Dir(sharedFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(sharedFile, []byte("SECRET=1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := TrashRepository(repo)
+	if err != nil {
+		t.Fatalf("TrashRepository() error = %v", err)
+	}
+	if _, err := os.Stat(repo.Path); !os.IsNotExist(err) {
+		t.Errorf("repository should be gone, stat error = %v", err)
+	}
+
+	// Restoring refuses to overwrite
+	if err := os.MkdirAll(repo.Path, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := RestoreTrashEntry(entry, io.Discard); err == nil {
+		t.Error("RestoreTrashEntry() should refuse an existing path")
+	}
+	if err := os.Remove(repo.Path); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RestoreTrashEntry(entry, io.Discard); err != nil {
+		t.Fatalf("RestoreTrashEntry() error = %v", err)
+	}
+	if data, err := os.ReadFile(sharedFile); err != nil || string(data) != "SECRET=1" {
+		t.Errorf("shared file not restored: %q, %v", data, err)
+	}
+	if got := gitOutput(t, filepath.Join(repo.Path, "main"), "symbolic-ref", "--short", "HEAD"); got != "main" {
+		t.Errorf("restored worktree branch = %s, want main", got)
+	}
+}
+
+func TestFindTrashEntry(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	older, err := newTrashEntry(TrashKindWorktree, "feature/x", "/repo/feature/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	older.DeletedAt = time.Now().Add(-time.Hour)
+	newer, err := newTrashEntry(TrashKindWorktree, "feature/x", "/repo/feature/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := newTrashEntry(TrashKindRepository, "example.com/user/other", "/root/example.com/user/other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []*TrashEntry{older, newer, other} {
+		if err := saveTrashEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if newer.ID == older.ID {
+		t.Fatalf("entries removed in the same second should get unique IDs, got %s twice", newer.ID)
+	}
+
+	entries, err := ListTrash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 || entries[len(entries)-1].ID != older.ID {
+		t.Errorf("ListTrash() should list 3 entries, oldest last: %+v", entries)
+	}
+
+	for _, tt := range []struct {
+		query string
+		want  string
+	}{
+		{older.ID, older.ID},
+		{"feature/x", newer.ID},
+		{"example.com/user/other", other.ID},
+		{strings.TrimSuffix(other.ID, "other"), other.ID},
+	} {
+		got, err := FindTrashEntry(tt.query)
+		if err != nil {
+			t.Errorf("FindTrashEntry(%q) error = %v", tt.query, err)
+			continue
+		}
+		if got.ID != tt.want {
+			t.Errorf("FindTrashEntry(%q) = %s, want %s", tt.query, got.ID, tt.want)
+		}
+	}
+
+	if _, err := FindTrashEntry("missing"); err == nil {
+		t.Error("FindTrashEntry(missing) should fail")
+	}
+	if _, err := FindTrashEntry(older.ID[:8]); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("FindTrashEntry(date prefix) should be ambiguous, got %v", err)
+	}
+
+	if err := DeleteTrashEntry(other); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := ListTrash(); len(entries) != 2 {
+		t.Errorf("expected 2 entries after delete, got %d", len(entries))
+	}
+}
+
+func TestParseTrashAge(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"14d", 14 * 24 * time.Hour, false},
+		{"0d", 0, false},
+		{"12h", 12 * time.Hour, false},
+		{"1h30m", 90 * time.Minute, false},
+		{"d", 0, true},
+		{"-1d", 0, true},
+		{"3x", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTrashAge(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseTrashAge(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseTrashAge(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package global
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// workspacesVersion is the current version of the workspace store format
+const workspacesVersion = 1
+
+// workspacesFileName is the name of the workspace store file in the state directory
+const workspacesFileName = "workspaces.json"
+
+// Workspace is a branch checked out in several repositories at once (bt workspace)
+type Workspace struct {
+	// Name is the branch shared by all members (e.g., "feature/x")
+	Name      string            `json:"name"`
+	Members   []WorkspaceMember `json:"members"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// WorkspaceMember is the worktree of a workspace in one repository
+type WorkspaceMember struct {
+	// Repository is the slash-separated repository path relative to its root (e.g., "github.com/org/api")
+	Repository string `json:"repository"`
+	// RepositoryPath is the absolute path to the repository root
+	RepositoryPath string `json:"repository_path"`
+	WorktreePath   string `json:"worktree_path"`
+}
+
+type workspaceStore struct {
+	Version    int         `json:"version"`
+	Workspaces []Workspace `json:"workspaces"`
+}
+
+// WorkspacesPath returns the path of the workspace store file ($XDG_STATE_HOME/baretree/workspaces.json)
+func WorkspacesPath() (string, error) {
+	stateDir, err := StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(stateDir, workspacesFileName), nil
+}
+
+// ListWorkspaces returns all workspaces sorted by name
+func ListWorkspaces() ([]Workspace, error) {
+	path, err := WorkspacesPath()
+	if err != nil {
+		return nil, err
+	}
+	store, err := readWorkspaceStore(path)
+	if err != nil {
+		return nil, err
+	}
+	return store.Workspaces, nil
+}
+
+// GetWorkspace returns the workspace with the given name
+func GetWorkspace(name string) (*Workspace, error) {
+	workspaces, err := ListWorkspaces()
+	if err != nil {
+		return nil, err
+	}
+	for i := range workspaces {
+		if workspaces[i].Name == name {
+			return &workspaces[i], nil
+		}
+	}
+	return nil, fmt.Errorf("workspace not found: %s", name)
+}
+
+// SaveWorkspace adds a workspace, or replaces the workspace with the same name
+func SaveWorkspace(ws Workspace) error {
+	return updateWorkspaces(func(store *workspaceStore) error {
+		for i := range store.Workspaces {
+			if store.Workspaces[i].Name == ws.Name {
+				store.Workspaces[i] = ws
+				return nil
+			}
+		}
+		store.Workspaces = append(store.Workspaces, ws)
+		return nil
+	})
+}
+
+// RemoveWorkspace deletes the record of a workspace (its worktrees are left alone)
+func RemoveWorkspace(name string) error {
+	return updateWorkspaces(func(store *workspaceStore) error {
+		for i := range store.Workspaces {
+			if store.Workspaces[i].Name == name {
+				store.Workspaces = append(store.Workspaces[:i], store.Workspaces[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("workspace not found: %s", name)
+	})
+}
+
+// updateWorkspaces applies fn to the workspace store while holding its lock and saves the result
+func updateWorkspaces(fn func(store *workspaceStore) error) error {
+	path, err := WorkspacesPath()
+	if err != nil {
+		return err
+	}
+	return withFileLock(path, func() error {
+		store, err := readWorkspaceStore(path)
+		if err != nil {
+			return err
+		}
+		if err := fn(store); err != nil {
+			return err
+		}
+
+		sort.Slice(store.Workspaces, func(i, j int) bool {
+			return store.Workspaces[i].Name < store.Workspaces[j].Name
+		})
+		data, err := json.MarshalIndent(store, "", "  ")
+		if err != nil {
+			return err
+		}
+		return writeFileAtomic(path, append(data, '\n'))
+	})
+}
+
+// readWorkspaceStore reads the workspace store. Unlike caches, a broken store is an error:
+// it is the only record of which worktrees belong together.
+func readWorkspaceStore(path string) (*workspaceStore, error) {
+	store := &workspaceStore{Version: workspacesVersion}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspaces: %w", err)
+	}
+	if err := json.Unmarshal(data, store); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if store.Version != workspacesVersion {
+		return nil, fmt.Errorf("unsupported workspace store version %d in %s", store.Version, path)
+	}
+	return store, nil
+}
@@ -0,0 +1,121 @@
+package global
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWorkspaceStore(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	workspaces, err := ListWorkspaces()
+	if err != nil {
+		t.Fatalf("ListWorkspaces() on empty store error = %v", err)
+	}
+	if len(workspaces) != 0 {
+		t.Fatalf("ListWorkspaces() on empty store = %v, want none", workspaces)
+	}
+
+	feature := Workspace{
+		Name: "feature/x",
+		Members: []WorkspaceMember{
+			{Repository: "github.com/org/api", RepositoryPath: "/root/github.com/org/api", WorktreePath: "/root/github.com/org/api/feature/x"},
+			{Repository: "github.com/org/web", RepositoryPath: "/root/github.com/org/web", WorktreePath: "/root/github.com/org/web/feature/x"},
+		},
+		CreatedAt: time.Now().Truncate(time.Second),
+	}
+	bugfix := Workspace{
+		Name:      "bugfix/y",
+		Members:   []WorkspaceMember{feature.Members[0]},
+		CreatedAt: time.Now().Truncate(time.Second),
+	}
+	for _, ws := range []Workspace{feature, bugfix} {
+		if err := SaveWorkspace(ws); err != nil {
+			t.Fatalf("SaveWorkspace(%s) error = %v", ws.Name, err)
+		}
+	}
+
+	// Sorted by name
+	workspaces, err = ListWorkspaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workspaces) != 2 || workspaces[0].Name != "bugfix/y" || workspaces[1].Name != "feature/x" {
+		t.Fatalf("ListWorkspaces() = %v, want [bugfix/y feature/x]", workspaces)
+	}
+
+	got, err := GetWorkspace("feature/x")
+	if err != nil {
+		t.Fatalf("GetWorkspace() error = %v", err)
+	}
+	if len(got.Members) != 2 || got.Members[1].WorktreePath != feature.Members[1].WorktreePath {
+		t.Errorf("GetWorkspace() members = %v, want %v", got.Members, feature.Members)
+	}
+	if !got.CreatedAt.Equal(feature.CreatedAt) {
+		t.Errorf("GetWorkspace() CreatedAt = %v, want %v", got.CreatedAt, feature.CreatedAt)
+	}
+
+	// Saving a workspace with the same name replaces it
+	feature.Members = feature.Members[1:]
+	if err := SaveWorkspace(feature); err != nil {
+		t.Fatal(err)
+	}
+	got, err = GetWorkspace("feature/x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.Members) != 1 || got.Members[0].Repository != "github.com/org/web" {
+		t.Errorf("GetWorkspace() after update members = %v", got.Members)
+	}
+
+	if err := RemoveWorkspace("feature/x"); err != nil {
+		t.Fatalf("RemoveWorkspace() error = %v", err)
+	}
+	if _, err := GetWorkspace("feature/x"); err == nil || !strings.Contains(err.Error(), "workspace not found") {
+		t.Errorf("GetWorkspace() after removal error = %v, want not found", err)
+	}
+	if err := RemoveWorkspace("feature/x"); err == nil {
+		t.Error("RemoveWorkspace() of unknown workspace should fail")
+	}
+
+	workspaces, err = ListWorkspaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workspaces) != 1 || workspaces[0].Name != "bugfix/y" {
+		t.Errorf("ListWorkspaces() after removal = %v, want [bugfix/y]", workspaces)
+	}
+}
+
+func TestWorkspaceStoreCorrupt(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	path, err := WorkspacesPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A broken store is reported instead of being silently replaced
+	if _, err := ListWorkspaces(); err == nil {
+		t.Error("ListWorkspaces() with corrupt store should fail")
+	}
+	if err := SaveWorkspace(Workspace{Name: "feature/x"}); err == nil {
+		t.Error("SaveWorkspace() with corrupt store should fail")
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "{not json" {
+		t.Errorf("corrupt store was overwritten: %q", data)
+	}
+}
@@ -0,0 +1,100 @@
+package global
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/worktree"
+)
+
+// DefaultListJobs is the default number of repositories inspected concurrently
+const DefaultListJobs = 8
+
+// WorktreeInfo describes a worktree of a repository under a baretree root
+type WorktreeInfo struct {
+	// Repository is the repository path relative to its root (e.g., "github.com/user/repo")
+	Repository string `json:"repository"`
+	// RepositoryPath is the absolute path to the repository root
+	RepositoryPath string `json:"repository_path"`
+	Path           string `json:"path"`
+	Branch         string `json:"branch"`
+	Head           string `json:"head"`
+	// IsDefault is true for the worktree of the configured default branch
+	IsDefault bool `json:"is_default"`
+	// Dirty is true if the worktree has uncommitted or untracked changes
+	Dirty bool `json:"dirty"`
+	// LastCommit is the committer date of HEAD (zero if there are no commits)
+	LastCommit time.Time `json:"last_commit"`
+}
+
+// RepoWorktrees holds the worktrees of a single repository
+type RepoWorktrees struct {
+	Repo      RepoInfo
+	Worktrees []WorktreeInfo
+	Err       error
+}
+
+// ListAllWorktrees collects the worktrees of every repository, inspecting up to
+// jobs repositories concurrently (DefaultListJobs if <= 0).
+// Results are returned in the same order as repos.
+func ListAllWorktrees(repos []RepoInfo, jobs int) []RepoWorktrees {
+	if jobs <= 0 {
+		jobs = DefaultListJobs
+	}
+
+	results := make([]RepoWorktrees, len(repos))
+	runParallel(len(repos), jobs, func(i int) {
+		worktrees, err := listRepoWorktrees(repos[i])
+		results[i] = RepoWorktrees{Repo: repos[i], Worktrees: worktrees, Err: err}
+	})
+
+	return results
+}
+
+// listRepoWorktrees lists the worktrees of a single repository with their state
+func listRepoWorktrees(repo RepoInfo) ([]WorktreeInfo, error) {
+	bareDir, err := repository.GetBareRepoPath(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	mgr, err := repository.NewManager(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	wtMgr := worktree.NewManager(repo.Path, bareDir, mgr.Config)
+	worktrees, err := wtMgr.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var infos []WorktreeInfo
+	for _, wt := range worktrees {
+		info := WorktreeInfo{
+			Repository:     repo.RelativePath,
+			RepositoryPath: repo.Path,
+			Path:           wt.Path,
+			Branch:         wt.Branch,
+			Head:           wt.Head,
+			IsDefault:      wt.IsMain,
+		}
+
+		executor := git.NewExecutor(wt.Path)
+		if status, err := executor.Execute("status", "--porcelain"); err == nil {
+			info.Dirty = status != ""
+		}
+		if ts, err := executor.Execute("log", "-1", "--format=%ct"); err == nil {
+			if sec, err := strconv.ParseInt(strings.TrimSpace(ts), 10, 64); err == nil {
+				info.LastCommit = time.Unix(sec, 0)
+			}
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
@@ -0,0 +1,84 @@
+package global
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// runTestGit runs a git command in dir and fails the test on error
+func runTestGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+// createWorktreeTestRepo creates a baretree repository with a "main" worktree
+func createWorktreeTestRepo(t *testing.T, root, name string) RepoInfo {
+	t.Helper()
+	src := filepath.Join(t.TempDir(), "src")
+	runTestGit(t, "", "init", "-b", "main", src)
+	runTestGit(t, src, "commit", "--allow-empty", "-m", "initial")
+
+	repoPath := filepath.Join(root, name)
+	if err := os.MkdirAll(repoPath, 0755); err != nil {
+		t.Fatalf("failed to create repo dir: %v", err)
+	}
+	runTestGit(t, repoPath, "clone", "--bare", src, ".git")
+	runTestGit(t, filepath.Join(repoPath, ".git"), "config", "baretree.defaultbranch", "main")
+	runTestGit(t, filepath.Join(repoPath, ".git"), "worktree", "add", filepath.Join(repoPath, "main"), "main")
+
+	return RepoInfo{Path: repoPath, RelativePath: name, Name: filepath.Base(name)}
+}
+
+func TestListAllWorktrees(t *testing.T) {
+	root := t.TempDir()
+	clean := createWorktreeTestRepo(t, root, "example.com/user/clean")
+	dirty := createWorktreeTestRepo(t, root, "example.com/user/dirty")
+	if err := os.WriteFile(filepath.Join(dirty.Path, "main", "new.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	broken := RepoInfo{Path: filepath.Join(root, "missing"), RelativePath: "missing", Name: "missing"}
+
+	results := ListAllWorktrees([]RepoInfo{clean, dirty, broken}, 2)
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+
+	for i, want := range []struct {
+		repo  string
+		dirty bool
+	}{
+		{"example.com/user/clean", false},
+		{"example.com/user/dirty", true},
+	} {
+		r := results[i]
+		if r.Err != nil {
+			t.Fatalf("%s: unexpected error: %v", want.repo, r.Err)
+		}
+		if len(r.Worktrees) != 1 {
+			t.Fatalf("%s: expected 1 worktree, got %d", want.repo, len(r.Worktrees))
+		}
+		wt := r.Worktrees[0]
+		if wt.Repository != want.repo {
+			t.Errorf("expected repository %q, got %q", want.repo, wt.Repository)
+		}
+		if wt.Branch != "main" || !wt.IsDefault {
+			t.Errorf("%s: expected default worktree on main, got branch %q (default: %v)", want.repo, wt.Branch, wt.IsDefault)
+		}
+		if wt.Dirty != want.dirty {
+			t.Errorf("%s: expected dirty=%v, got %v", want.repo, want.dirty, wt.Dirty)
+		}
+		if wt.LastCommit.IsZero() {
+			t.Errorf("%s: expected last commit time to be set", want.repo)
+		}
+	}
+
+	if results[2].Err == nil {
+		t.Error("expected error for missing repository")
+	}
+}
@@ -3,24 +3,19 @@ package shell
 const FishScript = `# baretree shell integration for fish
 
 function bt
+    # Listing the directory history prints instead of changing directory
+    if contains -- "$argv[1]" cd go; and test "$argv[2]" = "--history"; or test "$argv[1]" = "repo" -a "$argv[2]" = "cd" -a "$argv[3]" = "--history"
+        command bt $argv
     # Handle cd command specially (worktree navigation)
-    if test "$argv[1]" = "cd"
+    else if test "$argv[1]" = "cd"
         set -l target_dir
-        if test (count $argv) -eq 1
-            set target_dir (command bt cd 2>/dev/null)
-        else
-            set target_dir (command bt cd $argv[2] 2>/dev/null)
-        end
+        set target_dir (command bt $argv 2>/dev/null)
 
         if test $status -eq 0 -a -n "$target_dir"
             cd $target_dir
         else
             # Show error from bt command
-            if test (count $argv) -eq 1
-                command bt cd
-            else
-                command bt cd $argv[2]
-            end
+            command bt $argv
         end
     # Handle repo cd command specially (repository navigation)
     else if test "$argv[1]" = "repo" -a "$argv[2]" = "cd"
@@ -44,6 +39,17 @@ function bt
             # Show error from bt command
             command bt go $argv[2]
         end
+    # Handle workspace cd command specially (workspace worktree navigation)
+    else if test "$argv[1]" = "workspace" -a "$argv[2]" = "cd"
+        set -l target_dir
+        set target_dir (command bt $argv 2>/dev/null)
+
+        if test $status -eq 0 -a -n "$target_dir"
+            cd $target_dir
+        else
+            # Show error from bt command
+            command bt $argv
+        end
     else
         # Pass through all other commands
         command bt $argv
@@ -3,24 +3,20 @@ package shell
 const ZshScript = `# baretree shell integration for zsh
 
 bt() {
+    # Listing the directory history prints instead of changing directory
+    if [[ ( "$1" == "cd" || "$1" == "go" ) && "$2" == "--history" ]] || \
+       [[ "$1" == "repo" && "$2" == "cd" && "$3" == "--history" ]]; then
+        command bt "$@"
     # Handle cd command specially (worktree navigation)
-    if [[ "$1" == "cd" ]]; then
+    elif [[ "$1" == "cd" ]]; then
         local target_dir
-        if [[ -z "$2" ]]; then
-            target_dir=$(command bt cd 2>/dev/null)
-        else
-            target_dir=$(command bt cd "$2" 2>/dev/null)
-        fi
+        target_dir=$(command bt "$@" 2>/dev/null)
 
         if [[ $? -eq 0 && -n "$target_dir" ]]; then
             cd "$target_dir"
         else
             # Show error from bt command
-            if [[ -z "$2" ]]; then
-                command bt cd
-            else
-                command bt cd "$2"
-            fi
+            command bt "$@"
         fi
     # Handle repo cd command specially (repository navigation)
     elif [[ "$1" == "repo" && "$2" == "cd" ]]; then
@@ -44,6 +40,17 @@ bt() {
             # Show error from bt command
             command bt go "$2"
         fi
+    # Handle workspace cd command specially (workspace worktree navigation)
+    elif [[ "$1" == "workspace" && "$2" == "cd" ]]; then
+        local target_dir
+        target_dir=$(command bt "$@" 2>/dev/null)
+
+        if [[ $? -eq 0 && -n "$target_dir" ]]; then
+            cd "$target_dir"
+        else
+            # Show error from bt command
+            command bt "$@"
+        fi
     else
         # Pass through all other commands
         command bt "$@"
@@ -4,67 +4,166 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"path/filepath"
 	"regexp"
 	"strings"
 )
 
+// LocalHost is the host directory for repositories cloned from local paths and file:// URLs
+const LocalHost = "local"
+
 // RepoPath represents a parsed repository path
 type RepoPath struct {
-	Host string // e.g., "github.com"
-	User string // e.g., "amaya382"
-	Repo string // e.g., "baretree"
+	Scheme string // e.g., "https", "ssh", "file" (empty for scp-style SSH URLs and short paths)
+	Host   string // e.g., "github.com" (empty for local repositories)
+	Port   string // e.g., "2222" (empty for the default port)
+	User   string // first path component: user, organization or top-level group (e.g., "amaya382")
+	Repo   string // rest of the path, including subgroups (e.g., "baretree" or "team/sub/repo")
 }
 
-// String returns the full path representation (host/user/repo)
+// String returns the full path representation (host/user/repo).
+// The port is not part of the path; local repositories are placed under LocalHost.
 func (r *RepoPath) String() string {
-	return path.Join(r.Host, r.User, r.Repo)
+	host := r.Host
+	if host == "" {
+		host = LocalHost
+	}
+	return path.Join(host, r.User, r.Repo)
+}
+
+// Namespace returns the path of the groups containing the repository
+// (e.g., "amaya382" for github.com/amaya382/baretree, "org/team/sub" for gitlab.com/org/team/sub/repo)
+func (r *RepoPath) Namespace() string {
+	namespace := path.Dir(path.Join(r.User, r.Repo))
+	if namespace == "." {
+		return ""
+	}
+	return namespace
+}
+
+// Name returns the repository name without its namespace
+func (r *RepoPath) Name() string {
+	return path.Base(r.Repo)
+}
+
+// Clone protocols supported by CloneURL
+const (
+	ProtocolSSH   = "ssh"
+	ProtocolHTTPS = "https"
+)
+
+// CloneURL builds the URL to clone the repository with the given protocol
+// ("ssh" for git@host:user/repo.git, "https" for https://host/user/repo.git)
+func (r *RepoPath) CloneURL(protocol string) (string, error) {
+	switch protocol {
+	case "", ProtocolSSH:
+		if r.Port != "" {
+			return fmt.Sprintf("ssh://git@%s:%s/%s/%s.git", r.Host, r.Port, r.User, r.Repo), nil
+		}
+		return fmt.Sprintf("git@%s:%s/%s.git", r.Host, r.User, r.Repo), nil
+	case ProtocolHTTPS:
+		host := r.Host
+		if r.Port != "" {
+			host += ":" + r.Port
+		}
+		return fmt.Sprintf("https://%s/%s/%s.git", host, r.User, r.Repo), nil
+	default:
+		return "", fmt.Errorf("unsupported protocol: %s (use %s or %s)", protocol, ProtocolSSH, ProtocolHTTPS)
+	}
+}
+
+// IsURL reports whether input is a clone URL (scheme://, scp-style SSH) or a local path
+// that can be passed to git clone as is, rather than a short path
+func IsURL(input string) bool {
+	input = strings.TrimSpace(input)
+	if schemeRegex.MatchString(input) || IsLocalPath(input) {
+		return true
+	}
+	return sshURLRegex.MatchString(input)
+}
+
+// IsLocalPath reports whether input is a local filesystem path
+// (absolute, or relative starting with ./ or ../) rather than a URL or short path
+func IsLocalPath(input string) bool {
+	if input == "." || input == ".." || filepath.IsAbs(input) || windowsPathRegex.MatchString(input) {
+		return true
+	}
+	for _, prefix := range []string{"./", "../", `.\`, `..\`} {
+		if strings.HasPrefix(input, prefix) {
+			return true
+		}
+	}
+	return false
 }
 
+// schemeRegex matches URLs with a scheme like https://, ssh://, git+ssh:// or file://
+var schemeRegex = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9+.-]*://`)
+
+// windowsPathRegex matches Windows paths with a drive letter like C:\repo or C:/repo
+var windowsPathRegex = regexp.MustCompile(`^[A-Za-z]:[\\/]`)
+
 // sshURLRegex matches SSH URLs like git@github.com:user/repo.git
 var sshURLRegex = regexp.MustCompile(`^(?:[\w-]+@)?([\w.-]+):(.+?)(?:\.git)?$`)
 
 // Parse parses a repository URL or path and returns its components
 // Supports:
 //   - SSH URL: git@github.com:user/repo.git
+//   - SSH URL with scheme and port: ssh://git@host:2222/user/repo.git
 //   - HTTPS URL: https://github.com/user/repo.git
+//   - File URL: file:///srv/git/repo.git
+//   - Local path: /srv/git/repo.git, ../repo
 //   - Short path: github.com/user/repo
 //   - User/repo (requires defaultHost): user/repo
 //   - Repo only (requires defaultHost and defaultUser): repo
+//
+// Nested namespaces such as GitLab subgroups (gitlab.com/org/team/sub/repo) are kept in Repo.
+// Local paths and file:// URLs have an empty Host and are placed under LocalHost.
 func Parse(input string, defaultHost string, defaultUser string) (*RepoPath, error) {
 	input = strings.TrimSpace(input)
 	if input == "" {
 		return nil, fmt.Errorf("empty repository path")
 	}
 
-	// Try HTTPS/HTTP URL format first: https://github.com/user/repo.git
-	if strings.HasPrefix(input, "http://") || strings.HasPrefix(input, "https://") {
+	// Local paths are checked first so that Windows drive letters are not taken for SSH hosts
+	if IsLocalPath(input) {
+		absPath, err := filepath.Abs(input)
+		if err != nil {
+			return nil, fmt.Errorf("invalid local path: %w", err)
+		}
+		return parseLocalPath(filepath.ToSlash(absPath), "")
+	}
+
+	// Try URL formats with a scheme: https://, ssh://, file://, ...
+	if schemeRegex.MatchString(input) {
 		u, err := url.Parse(input)
 		if err != nil {
 			return nil, fmt.Errorf("invalid URL: %w", err)
 		}
-		pathStr := strings.TrimPrefix(u.Path, "/")
-		pathStr = strings.TrimSuffix(pathStr, ".git")
-		parts := strings.Split(pathStr, "/")
-		if len(parts) >= 2 {
-			return &RepoPath{
-				Host: u.Host,
-				User: parts[0],
-				Repo: strings.Join(parts[1:], "/"),
-			}, nil
+		scheme := strings.ToLower(u.Scheme)
+		if scheme == "file" {
+			return parseLocalPath(u.Path, scheme)
+		}
+		user, repo, ok := splitRepoPath(u.Path)
+		if !ok || u.Hostname() == "" {
+			return nil, fmt.Errorf("invalid %s URL format: %s", strings.ToUpper(scheme), input)
 		}
-		return nil, fmt.Errorf("invalid HTTPS URL format: %s", input)
+		return &RepoPath{
+			Scheme: scheme,
+			Host:   u.Hostname(),
+			Port:   u.Port(),
+			User:   user,
+			Repo:   repo,
+		}, nil
 	}
 
 	// Try SSH URL format: git@github.com:user/repo.git
 	if matches := sshURLRegex.FindStringSubmatch(input); matches != nil {
-		host := matches[1]
-		repoPath := strings.TrimSuffix(matches[2], ".git")
-		parts := strings.Split(repoPath, "/")
-		if len(parts) >= 2 {
+		user, repo, ok := splitRepoPath(matches[2])
+		if ok {
 			return &RepoPath{
-				Host: host,
-				User: parts[0],
-				Repo: strings.Join(parts[1:], "/"),
+				Host: matches[1],
+				User: user,
+				Repo: repo,
 			}, nil
 		}
 		return nil, fmt.Errorf("invalid SSH URL format: %s", input)
@@ -106,6 +205,39 @@ func Parse(input string, defaultHost string, defaultUser string) (*RepoPath, err
 	}
 }
 
+// splitRepoPath splits a URL path like "/org/team/repo.git" into its first component
+// and the rest without the .git suffix. Returns false if there are fewer than two components.
+func splitRepoPath(p string) (user, repo string, ok bool) {
+	p = strings.TrimSuffix(strings.Trim(p, "/"), ".git")
+	user, repo, ok = strings.Cut(p, "/")
+	if !ok || user == "" || repo == "" {
+		return "", "", false
+	}
+	return user, repo, true
+}
+
+// parseLocalPath converts a slash-separated absolute path of a local repository into a RepoPath.
+// The drive letter of Windows paths becomes a path component (C:/repos/x -> C/repos/x).
+func parseLocalPath(p, scheme string) (*RepoPath, error) {
+	original := p
+	p = strings.Trim(p, "/")
+	if windowsPathRegex.MatchString(p) {
+		p = p[:1] + p[2:]
+	}
+	// Both /srv/repo.git (bare) and /srv/repo/.git (non-bare) map to srv/repo
+	p = strings.TrimSuffix(strings.TrimSuffix(p, "/.git"), ".git")
+	if p == "" {
+		return nil, fmt.Errorf("invalid local repository path: %s", original)
+	}
+
+	user, repo, ok := strings.Cut(p, "/")
+	if !ok {
+		// A repository directly under the filesystem root
+		user, repo = "", p
+	}
+	return &RepoPath{Scheme: scheme, User: user, Repo: repo}, nil
+}
+
 // ParseRemoteURL parses a git remote URL and returns the repository path
 func ParseRemoteURL(remoteURL string) (*RepoPath, error) {
 	return Parse(remoteURL, "", "")
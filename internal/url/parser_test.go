@@ -1,6 +1,9 @@
 package url
 
 import (
+	"path"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -104,6 +107,53 @@ func TestParse(t *testing.T) {
 			wantUser: "org",
 			wantRepo: "group/repo",
 		},
+		{
+			name:     "Short path with subgroups",
+			input:    "gitlab.com/org/team/sub/repo",
+			wantHost: "gitlab.com",
+			wantUser: "org",
+			wantRepo: "team/sub/repo",
+		},
+		{
+			name:     "HTTPS URL with port and subgroups",
+			input:    "https://git.example.com:8443/org/team/repo.git",
+			wantHost: "git.example.com",
+			wantUser: "org",
+			wantRepo: "team/repo",
+		},
+		{
+			name:     "SSH URL with scheme and port",
+			input:    "ssh://git@git.example.com:2222/org/repo.git",
+			wantHost: "git.example.com",
+			wantUser: "org",
+			wantRepo: "repo",
+		},
+		{
+			name:     "File URL",
+			input:    "file:///srv/git/repo.git",
+			wantHost: "",
+			wantUser: "srv",
+			wantRepo: "git/repo",
+		},
+		{
+			name:     "Local bare repository path",
+			input:    "/srv/git/repo.git",
+			wantHost: "",
+			wantUser: "srv",
+			wantRepo: "git/repo",
+		},
+		{
+			name:     "Local non-bare repository path",
+			input:    "/srv/git/repo/.git",
+			wantHost: "",
+			wantUser: "srv",
+			wantRepo: "git/repo",
+		},
+		{
+			name:    "SSH URL with scheme but without user",
+			input:   "ssh://git.example.com/repo.git",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -140,3 +190,114 @@ func TestRepoPath_String(t *testing.T) {
 		t.Errorf("RepoPath.String() = %v, want %v", got, want)
 	}
 }
+
+func TestRepoPath_CloneURL(t *testing.T) {
+	rp := &RepoPath{Host: "gitlab.example.com", User: "team", Repo: "group/project"}
+
+	tests := []struct {
+		protocol string
+		want     string
+		wantErr  bool
+	}{
+		{protocol: "", want: "git@gitlab.example.com:team/group/project.git"},
+		{protocol: "ssh", want: "git@gitlab.example.com:team/group/project.git"},
+		{protocol: "https", want: "https://gitlab.example.com/team/group/project.git"},
+		{protocol: "ftp", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := rp.CloneURL(tt.protocol)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("CloneURL(%q) error = %v, wantErr %v", tt.protocol, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("CloneURL(%q) = %q, want %q", tt.protocol, got, tt.want)
+		}
+	}
+}
+
+func TestIsURL(t *testing.T) {
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"https://github.com/user/repo.git", true},
+		{"http://git.example.com/user/repo", true},
+		{"git@github.com:user/repo.git", true},
+		{"ssh://git@host:2222/user/repo.git", true},
+		{"file:///srv/git/repo.git", true},
+		{"/srv/git/repo.git", true},
+		{"../repo", true},
+		{"github.com/user/repo", false},
+		{"user/repo", false},
+		{"repo", false},
+	}
+	for _, tt := range tests {
+		if got := IsURL(tt.input); got != tt.want {
+			t.Errorf("IsURL(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParse_SchemeAndPort(t *testing.T) {
+	tests := []struct {
+		input      string
+		wantScheme string
+		wantPort   string
+		wantString string
+	}{
+		{"ssh://git@git.example.com:2222/org/team/repo.git", "ssh", "2222", "git.example.com/org/team/repo"},
+		{"https://github.com/amaya382/baretree", "https", "", "github.com/amaya382/baretree"},
+		{"git@github.com:amaya382/baretree.git", "", "", "github.com/amaya382/baretree"},
+		{"file:///srv/git/repo.git", "file", "", "local/srv/git/repo"},
+		{"/srv/git/repo.git", "", "", "local/srv/git/repo"},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input, "", "")
+		if err != nil {
+			t.Errorf("Parse(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got.Scheme != tt.wantScheme || got.Port != tt.wantPort {
+			t.Errorf("Parse(%q) Scheme = %q, Port = %q, want %q, %q", tt.input, got.Scheme, got.Port, tt.wantScheme, tt.wantPort)
+		}
+		if got.String() != tt.wantString {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.input, got.String(), tt.wantString)
+		}
+	}
+}
+
+func TestParse_RelativeLocalPath(t *testing.T) {
+	got, err := Parse("../upstream.git", "", "")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	parent, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := path.Join(LocalHost, strings.TrimPrefix(filepath.ToSlash(parent), "/"), "upstream")
+	if got.String() != want {
+		t.Errorf("Parse().String() = %q, want %q", got.String(), want)
+	}
+}
+
+func TestRepoPath_NamespaceAndName(t *testing.T) {
+	tests := []struct {
+		rp            RepoPath
+		wantNamespace string
+		wantName      string
+	}{
+		{RepoPath{Host: "github.com", User: "amaya382", Repo: "baretree"}, "amaya382", "baretree"},
+		{RepoPath{Host: "gitlab.com", User: "org", Repo: "team/sub/repo"}, "org/team/sub", "repo"},
+		{RepoPath{Repo: "repo"}, "", "repo"},
+	}
+	for _, tt := range tests {
+		if got := tt.rp.Namespace(); got != tt.wantNamespace {
+			t.Errorf("%v Namespace() = %q, want %q", tt.rp, got, tt.wantNamespace)
+		}
+		if got := tt.rp.Name(); got != tt.wantName {
+			t.Errorf("%v Name() = %q, want %q", tt.rp, got, tt.wantName)
+		}
+	}
+}
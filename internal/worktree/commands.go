@@ -0,0 +1,484 @@
+package worktree
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/amaya382/baretree/internal/config"
+)
+
+// commandPlan is a post-create command with the commands it must wait for
+type commandPlan struct {
+	action config.PostCreateAction
+	// waitFor holds indexes of commands that must finish before this one starts
+	waitFor []int
+	// explicit is true if waitFor comes from depends_on; the command is skipped if any of them fails
+	explicit bool
+}
+
+// ValidatePostCreateCommands checks names, depends_on references, timeouts and retries of command-type actions
+func ValidatePostCreateCommands(actions []config.PostCreateAction) error {
+	var commands []config.PostCreateAction
+	for _, a := range actions {
+		if a.Type != "command" {
+			continue
+		}
+		if _, err := a.TimeoutDuration(); err != nil {
+			return err
+		}
+		if a.Retries < 0 {
+			return fmt.Errorf("invalid retries %d for %s: must not be negative", a.Retries, a.Label())
+		}
+		commands = append(commands, a)
+	}
+	_, err := planCommands(commands)
+	return err
+}
+
+// planCommands resolves the execution order of commands:
+//   - By default, a command waits for all commands before it (sequential execution)
+//   - A parallel command waits for commands up to the last non-parallel one before it,
+//     so adjacent parallel commands run concurrently
+//   - A command with depends_on waits only for the listed commands
+func planCommands(commands []config.PostCreateAction) ([]commandPlan, error) {
+	index := make(map[string]int)
+	for i, c := range commands {
+		if c.Name != "" {
+			if _, exists := index[c.Name]; exists {
+				return nil, fmt.Errorf("duplicate post-create command name: %s", c.Name)
+			}
+			index[c.Name] = i
+		}
+	}
+	// Commands can also be referenced by their command string
+	for i, c := range commands {
+		if _, exists := index[c.Source]; !exists {
+			index[c.Source] = i
+		}
+	}
+
+	plans := make([]commandPlan, len(commands))
+	lastSequential := -1
+	for i, c := range commands {
+		plans[i].action = c
+
+		switch {
+		case len(c.DependsOn) > 0:
+			plans[i].explicit = true
+			for _, dep := range c.DependsOn {
+				j, ok := index[dep]
+				if !ok {
+					return nil, fmt.Errorf("post-create command %q depends on unknown command %q", c.Label(), dep)
+				}
+				if j == i {
+					return nil, fmt.Errorf("post-create command %q depends on itself", c.Label())
+				}
+				plans[i].waitFor = append(plans[i].waitFor, j)
+			}
+		case c.Parallel:
+			for j := 0; j <= lastSequential; j++ {
+				plans[i].waitFor = append(plans[i].waitFor, j)
+			}
+		default:
+			for j := 0; j < i; j++ {
+				plans[i].waitFor = append(plans[i].waitFor, j)
+			}
+			lastSequential = i
+		}
+	}
+
+	if err := checkCommandCycles(plans); err != nil {
+		return nil, err
+	}
+	return plans, nil
+}
+
+// checkCommandCycles returns an error if the commands cannot be ordered
+func checkCommandCycles(plans []commandPlan) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(plans))
+
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visiting:
+			return fmt.Errorf("post-create commands have a dependency cycle involving %q", plans[i].action.Label())
+		case visited:
+			return nil
+		}
+		state[i] = visiting
+		for _, j := range plans[i].waitFor {
+			if err := visit(j); err != nil {
+				return err
+			}
+		}
+		state[i] = visited
+		return nil
+	}
+
+	for i := range plans {
+		if err := visit(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isConcurrent reports whether any command opts into parallel or dependency-based execution
+func isConcurrent(commands []config.PostCreateAction) bool {
+	for _, c := range commands {
+		if c.Parallel || len(c.DependsOn) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// runCommands runs post-create commands in dir and returns their results in configuration order.
+// Without parallel/depends_on options, commands run one after another with their output streamed
+// as-is. Otherwise, independent commands run concurrently, each output line is prefixed with the
+// command label, and a summary with per-command durations is printed at the end.
+// If a required command fails, running commands are stopped and the remaining ones are skipped.
+func runCommands(commands []config.PostCreateAction, dir string, env []string, writer io.Writer) []CommandResult {
+	if !isConcurrent(commands) {
+		return runCommandsSequentially(commands, dir, env, writer)
+	}
+
+	plans, err := planCommands(commands)
+	if err != nil {
+		// Fall back to sequential execution so a configuration mistake does not block worktree creation
+		if writer != nil {
+			fmt.Fprintf(writer, "  Warning: %v; running commands sequentially\n", err)
+		}
+		return runCommandsSequentially(commands, dir, env, writer)
+	}
+
+	var out io.Writer
+	if writer != nil {
+		out = &syncWriter{writer: writer}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var failedRequired failedCommand
+
+	results := make([]CommandResult, len(plans))
+	done := make([]chan struct{}, len(plans))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for i := range plans {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer close(done[i])
+
+			plan := plans[i]
+			var failedDep string
+			for _, j := range plan.waitFor {
+				<-done[j]
+				if plan.explicit && !results[j].Success && failedDep == "" {
+					failedDep = plans[j].action.Label()
+				}
+			}
+
+			reason := ""
+			if label := failedRequired.get(); label != "" {
+				reason = fmt.Sprintf("required command %q failed", label)
+			} else if failedDep != "" {
+				reason = fmt.Sprintf("dependency %q failed", failedDep)
+			}
+			if reason != "" {
+				results[i] = skippedCommand(plan.action, reason)
+				if out != nil {
+					fmt.Fprintf(out, "  [%s] - skipped (%s)\n", plan.action.Label(), reason)
+				}
+				return
+			}
+
+			results[i] = runCommand(ctx, plan.action, dir, env, out, plan.action.Label())
+			if plan.action.Required && !results[i].Success && failedRequired.set(plan.action.Label()) {
+				cancel()
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if writer != nil {
+		printCommandSummary(writer, results)
+	}
+
+	return results
+}
+
+// runCommandsSequentially runs commands one after another, streaming their output as-is
+func runCommandsSequentially(commands []config.PostCreateAction, dir string, env []string, writer io.Writer) []CommandResult {
+	var results []CommandResult
+	failedRequired := ""
+	for _, c := range commands {
+		if failedRequired != "" {
+			reason := fmt.Sprintf("required command %q failed", failedRequired)
+			if writer != nil {
+				fmt.Fprintf(writer, "  - %s (skipped: %s)\n", c.Source, reason)
+			}
+			results = append(results, skippedCommand(c, reason))
+			continue
+		}
+
+		result := runCommand(context.Background(), c, dir, env, writer, "")
+		results = append(results, result)
+		if c.Required && !result.Success {
+			failedRequired = c.Label()
+		}
+	}
+	return results
+}
+
+// failedCommand records the first required command that failed among concurrent commands
+type failedCommand struct {
+	mu    sync.Mutex
+	label string
+}
+
+// set records label if no failure was recorded yet and reports whether it did
+func (f *failedCommand) set(label string) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.label != "" {
+		return false
+	}
+	f.label = label
+	return true
+}
+
+func (f *failedCommand) get() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.label
+}
+
+// skippedCommand returns the result of a command that was not run
+func skippedCommand(action config.PostCreateAction, reason string) CommandResult {
+	return CommandResult{
+		Command:  action.Source,
+		Name:     action.Name,
+		Required: action.Required,
+		Skipped:  true,
+		Error:    reason,
+	}
+}
+
+// runCommand runs a post-create command, applying its timeout and retrying on failure.
+// The returned Duration covers all attempts.
+func runCommand(ctx context.Context, action config.PostCreateAction, dir string, env []string, writer io.Writer, label string) CommandResult {
+	timeout, err := action.TimeoutDuration()
+	if err != nil && writer != nil {
+		fmt.Fprintf(writer, "  Warning: %v; running without timeout\n", err)
+	}
+
+	var result CommandResult
+	var total time.Duration
+	attempts := 0
+	for attempts <= action.Retries {
+		if attempts > 0 && writer != nil {
+			fmt.Fprintf(writer, "%sRetrying (%d/%d)...\n", commandLinePrefix(label), attempts, action.Retries)
+		}
+		result = runShellCommand(ctx, action.Source, dir, env, writer, label, timeout)
+		total += result.Duration
+		attempts++
+		if result.Success || ctx.Err() != nil {
+			break
+		}
+	}
+
+	result.Name = action.Name
+	result.Required = action.Required
+	result.Attempts = attempts
+	result.Duration = total
+	return result
+}
+
+// printCommandSummary prints the status and duration of each command
+func printCommandSummary(writer io.Writer, results []CommandResult) {
+	maxLabelLen := 0
+	for _, r := range results {
+		if len(r.label()) > maxLabelLen {
+			maxLabelLen = len(r.label())
+		}
+	}
+
+	fmt.Fprintln(writer, "\nPost-create summary:")
+	for _, r := range results {
+		switch {
+		case r.Skipped:
+			fmt.Fprintf(writer, "  - %-*s  %6s  skipped: %s\n", maxLabelLen, r.label(), "-", r.Error)
+		case r.Success:
+			fmt.Fprintf(writer, "  ✓ %-*s  %6s%s\n", maxLabelLen, r.label(), formatCommandDuration(r.Duration), attemptsSuffix(r))
+		default:
+			fmt.Fprintf(writer, "  ✗ %-*s  %6s  %s%s\n", maxLabelLen, r.label(), formatCommandDuration(r.Duration), r.Error, attemptsSuffix(r))
+		}
+	}
+}
+
+// attemptsSuffix describes how many times a retried command was run (empty if it ran once)
+func attemptsSuffix(r CommandResult) string {
+	if r.Attempts <= 1 {
+		return ""
+	}
+	return fmt.Sprintf(" (%d attempts)", r.Attempts)
+}
+
+// label returns the name of the command, or the command string if unnamed
+func (r CommandResult) label() string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return r.Command
+}
+
+// commandWaitDelay bounds how long to wait for output of processes left behind by a killed command
+const commandWaitDelay = 2 * time.Second
+
+// runShellCommand runs command via 'sh -c' in dir with the given environment.
+// The command and its output are written to writer in real-time; if writer is nil, output is discarded.
+// If label is non-empty, output is written line by line with a "[label]" prefix so that
+// commands running concurrently can share the same writer.
+// The command is killed when ctx is cancelled or, if timeout is positive, after timeout.
+func runShellCommand(ctx context.Context, command, dir string, env []string, writer io.Writer, label string, timeout time.Duration) CommandResult {
+	result := CommandResult{
+		Command:  command,
+		Attempts: 1,
+	}
+
+	linePrefix := commandLinePrefix(label)
+
+	// Print command before execution
+	if writer != nil {
+		fmt.Fprintf(writer, "%s$ %s\n", linePrefix, command)
+	}
+
+	runCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(runCtx, "sh", "-c", command)
+	cmd.Dir = dir
+	cmd.Env = env
+	cmd.WaitDelay = commandWaitDelay
+
+	// Connect stdout and stderr to writer for real-time output
+	var lineOutput *linePrefixWriter
+	if writer != nil {
+		if label != "" {
+			lineOutput = &linePrefixWriter{writer: writer, prefix: linePrefix + "> "}
+			cmd.Stdout = lineOutput
+			cmd.Stderr = lineOutput
+		} else {
+			cmd.Stdout = &prefixWriter{writer: writer, prefix: "  > ", atNewLine: true}
+			cmd.Stderr = &prefixWriter{writer: writer, prefix: "  > ", atNewLine: true}
+		}
+	}
+
+	start := time.Now()
+	err := cmd.Run()
+	result.Duration = time.Since(start)
+	if lineOutput != nil {
+		lineOutput.Flush()
+	}
+
+	if err != nil {
+		result.Success = false
+		result.Error = err.Error()
+		if errors.Is(runCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil {
+			result.TimedOut = true
+			result.Error = fmt.Sprintf("timed out after %s", timeout)
+		} else if ctx.Err() != nil {
+			result.Error = "cancelled"
+		}
+		if writer != nil {
+			fmt.Fprintf(writer, "%s✗ %s (%s)\n", linePrefix, result.Error, formatCommandDuration(result.Duration))
+		}
+	} else {
+		result.Success = true
+		if writer != nil {
+			fmt.Fprintf(writer, "%s✓ (%s)\n", linePrefix, formatCommandDuration(result.Duration))
+		}
+	}
+
+	return result
+}
+
+// commandLinePrefix returns the prefix of output lines for a command
+func commandLinePrefix(label string) string {
+	if label == "" {
+		return "  "
+	}
+	return fmt.Sprintf("  [%s] ", label)
+}
+
+// formatCommandDuration formats a duration with one decimal of seconds
+func formatCommandDuration(d time.Duration) string {
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// syncWriter serializes writes to an underlying writer
+type syncWriter struct {
+	mu     sync.Mutex
+	writer io.Writer
+}
+
+func (sw *syncWriter) Write(p []byte) (int, error) {
+	sw.mu.Lock()
+	defer sw.mu.Unlock()
+	return sw.writer.Write(p)
+}
+
+// linePrefixWriter buffers output and writes complete lines with a prefix in a single Write call,
+// so lines from concurrent commands are not interleaved
+type linePrefixWriter struct {
+	writer io.Writer
+	prefix string
+	buf    bytes.Buffer
+}
+
+func (lw *linePrefixWriter) Write(p []byte) (int, error) {
+	lw.buf.Write(p)
+	for {
+		line, err := lw.buf.ReadString('\n')
+		if err != nil {
+			// Incomplete line: keep it buffered until more output arrives
+			lw.buf.Reset()
+			lw.buf.WriteString(line)
+			break
+		}
+		if _, err := io.WriteString(lw.writer, lw.prefix+line); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// Flush writes any buffered incomplete line
+func (lw *linePrefixWriter) Flush() {
+	if lw.buf.Len() > 0 {
+		line := strings.TrimRight(lw.buf.String(), "\n")
+		_, _ = io.WriteString(lw.writer, lw.prefix+line+"\n")
+		lw.buf.Reset()
+	}
+}
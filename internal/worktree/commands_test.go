@@ -0,0 +1,231 @@
+package worktree
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/amaya382/baretree/internal/config"
+)
+
+func TestPlanCommands(t *testing.T) {
+	cmd := func(source string) config.PostCreateAction {
+		return config.PostCreateAction{Source: source, Type: "command"}
+	}
+
+	tests := []struct {
+		name     string
+		commands []config.PostCreateAction
+		want     [][]int
+		wantErr  string
+	}{
+		{
+			name:     "sequential by default",
+			commands: []config.PostCreateAction{cmd("a"), cmd("b"), cmd("c")},
+			want:     [][]int{nil, {0}, {0, 1}},
+		},
+		{
+			name: "adjacent parallel commands",
+			commands: []config.PostCreateAction{
+				cmd("setup"),
+				{Source: "a", Type: "command", Parallel: true},
+				{Source: "b", Type: "command", Parallel: true},
+				cmd("after"),
+			},
+			want: [][]int{nil, {0}, {0}, {0, 1, 2}},
+		},
+		{
+			name: "depends_on by name and command",
+			commands: []config.PostCreateAction{
+				{Source: "npm install", Type: "command", Name: "deps"},
+				{Source: "docker compose up -d", Type: "command", Parallel: true},
+				{Source: "npm run build", Type: "command", DependsOn: []string{"deps"}},
+				{Source: "npm run migrate", Type: "command", DependsOn: []string{"docker compose up -d", "deps"}},
+			},
+			want: [][]int{nil, {0}, {0}, {1, 0}},
+		},
+		{
+			name: "duplicate name",
+			commands: []config.PostCreateAction{
+				{Source: "a", Type: "command", Name: "x"},
+				{Source: "b", Type: "command", Name: "x"},
+			},
+			wantErr: "duplicate",
+		},
+		{
+			name: "unknown dependency",
+			commands: []config.PostCreateAction{
+				{Source: "a", Type: "command", DependsOn: []string{"missing"}},
+			},
+			wantErr: "unknown command",
+		},
+		{
+			name: "self dependency",
+			commands: []config.PostCreateAction{
+				{Source: "a", Type: "command", Name: "a", DependsOn: []string{"a"}},
+			},
+			wantErr: "itself",
+		},
+		{
+			name: "cycle",
+			commands: []config.PostCreateAction{
+				{Source: "a", Type: "command", DependsOn: []string{"b"}},
+				{Source: "b", Type: "command", DependsOn: []string{"a"}},
+			},
+			wantErr: "cycle",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plans, err := planCommands(tt.commands)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got [][]int
+			for _, p := range plans {
+				got = append(got, p.waitFor)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("waitFor = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunCommandsParallel(t *testing.T) {
+	dir := t.TempDir()
+	commands := []config.PostCreateAction{
+		{Source: "sleep 0.5", Type: "command", Name: "one", Parallel: true},
+		{Source: "sleep 0.5", Type: "command", Name: "two", Parallel: true},
+		{Source: "touch after", Type: "command", Name: "after", DependsOn: []string{"one", "two"}},
+	}
+
+	var out strings.Builder
+	start := time.Now()
+	results := runCommands(commands, dir, os.Environ(), &out)
+	elapsed := time.Since(start)
+
+	if elapsed >= 900*time.Millisecond {
+		t.Errorf("parallel commands should run concurrently, took %s", elapsed)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	for _, r := range results {
+		if !r.Success {
+			t.Errorf("command %s failed: %s", r.label(), r.Error)
+		}
+	}
+	if results[0].Duration < 400*time.Millisecond {
+		t.Errorf("expected duration to be recorded, got %s", results[0].Duration)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "after")); err != nil {
+		t.Error("dependent command should have run")
+	}
+	if !strings.Contains(out.String(), "Post-create summary:") {
+		t.Errorf("expected summary in output:\n%s", out.String())
+	}
+}
+
+func TestRunCommandsSkipsOnFailedDependency(t *testing.T) {
+	dir := t.TempDir()
+	commands := []config.PostCreateAction{
+		{Source: "exit 1", Type: "command", Name: "broken"},
+		{Source: "touch independent", Type: "command", Parallel: true},
+		{Source: "touch dependent", Type: "command", DependsOn: []string{"broken"}},
+	}
+
+	results := runCommands(commands, dir, os.Environ(), nil)
+
+	if results[0].Success {
+		t.Error("expected first command to fail")
+	}
+	if !results[1].Success {
+		t.Errorf("independent command should succeed: %s", results[1].Error)
+	}
+	if !results[2].Skipped {
+		t.Error("command depending on a failed command should be skipped")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "dependent")); err == nil {
+		t.Error("skipped command should not run")
+	}
+}
+
+func TestRunCommandTimeoutAndRetries(t *testing.T) {
+	dir := t.TempDir()
+
+	result := runCommand(context.Background(), config.PostCreateAction{Source: "sleep 5", Timeout: "200ms"}, dir, os.Environ(), nil, "")
+	if result.Success || !result.TimedOut {
+		t.Errorf("expected command to time out, got %+v", result)
+	}
+	if result.Duration >= 2*time.Second {
+		t.Errorf("command should be killed after its timeout, took %s", result.Duration)
+	}
+
+	// Fails on the first two attempts and succeeds on the third
+	flaky := `n=$(cat count 2>/dev/null || echo 0); n=$((n+1)); echo $n > count; [ $n -ge 3 ]`
+	result = runCommand(context.Background(), config.PostCreateAction{Source: flaky, Retries: 2}, dir, os.Environ(), nil, "")
+	if !result.Success || result.Attempts != 3 {
+		t.Errorf("expected success on the third attempt, got success=%v attempts=%d", result.Success, result.Attempts)
+	}
+
+	result = runCommand(context.Background(), config.PostCreateAction{Source: "exit 1", Retries: 1}, dir, os.Environ(), nil, "")
+	if result.Success || result.Attempts != 2 {
+		t.Errorf("expected two failed attempts, got success=%v attempts=%d", result.Success, result.Attempts)
+	}
+}
+
+func TestRunCommandsRequiredFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		commands []config.PostCreateAction
+	}{
+		{
+			name: "sequential",
+			commands: []config.PostCreateAction{
+				{Source: "exit 1", Type: "command", Required: true},
+				{Source: "touch should-not-run", Type: "command"},
+			},
+		},
+		{
+			name: "concurrent",
+			commands: []config.PostCreateAction{
+				{Source: "exit 1", Type: "command", Required: true, Parallel: true},
+				{Source: "sleep 5; touch should-not-run", Type: "command", Parallel: true},
+				{Source: "touch should-not-run", Type: "command"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			start := time.Now()
+			results := runCommands(tt.commands, dir, os.Environ(), nil)
+
+			if time.Since(start) >= 4*time.Second {
+				t.Error("running commands should be stopped when a required command fails")
+			}
+			if failed := failedRequiredCommand(results); failed == nil || failed.Command != "exit 1" {
+				t.Errorf("expected the required command to be reported as failed, got %+v", results)
+			}
+			if last := results[len(results)-1]; !last.Skipped {
+				t.Errorf("expected remaining commands to be skipped, got %+v", last)
+			}
+			if _, err := os.Stat(filepath.Join(dir, "should-not-run")); err == nil {
+				t.Error("commands after a failed required command should not run")
+			}
+		})
+	}
+}
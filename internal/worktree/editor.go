@@ -0,0 +1,447 @@
+package worktree
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Editor workspace formats generated by 'bt editor workspace'
+const (
+	EditorFormatVSCode    = "vscode"    // <repo>.code-workspace (VS Code multi-root workspace)
+	EditorFormatJetBrains = "jetbrains" // .idea/modules.xml with one module per worktree
+)
+
+// EditorFormats lists all supported editor workspace formats
+var EditorFormats = []string{EditorFormatVSCode, EditorFormatJetBrains}
+
+// jetbrainsModuleDir is where the generated module files live, relative to the repository root.
+// All .iml files in it belong to baretree and are replaced on every update.
+const jetbrainsModuleDir = ".idea/baretree"
+
+// EditorFolder is a worktree as a folder of an editor workspace
+type EditorFolder struct {
+	Name string // Branch name, or the directory name for a detached HEAD
+	Path string // Absolute path of the worktree
+}
+
+// ValidateEditorFormat checks that format is a supported editor workspace format
+func ValidateEditorFormat(format string) error {
+	for _, f := range EditorFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown editor workspace format '%s' (must be one of: %s)", format, strings.Join(EditorFormats, ", "))
+}
+
+// EditorWorkspacePath returns the file that holds the editor workspace in the given format
+func (m *Manager) EditorWorkspacePath(format string) string {
+	if format == EditorFormatJetBrains {
+		return filepath.Join(m.RepoRoot, ".idea", "modules.xml")
+	}
+	return filepath.Join(m.RepoRoot, filepath.Base(m.RepoRoot)+".code-workspace")
+}
+
+// EditorFolders returns the worktrees as editor workspace folders, the default branch first
+func (m *Manager) EditorFolders() ([]EditorFolder, error) {
+	worktrees, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(worktrees, func(i, j int) bool {
+		if worktrees[i].IsMain != worktrees[j].IsMain {
+			return worktrees[i].IsMain
+		}
+		return worktrees[i].Path < worktrees[j].Path
+	})
+
+	folders := make([]EditorFolder, 0, len(worktrees))
+	for _, wt := range worktrees {
+		name := wt.Branch
+		if name == "" {
+			name = filepath.Base(wt.Path)
+		}
+		folders = append(folders, EditorFolder{Name: name, Path: wt.Path})
+	}
+	return folders, nil
+}
+
+// WriteEditorWorkspace generates or updates the editor workspace in the given format with one
+// folder per worktree. Settings and folders outside the repository that were added by hand are
+// kept. Returns the path of the workspace file and the number of worktree folders.
+func (m *Manager) WriteEditorWorkspace(format string) (string, int, error) {
+	if err := ValidateEditorFormat(format); err != nil {
+		return "", 0, err
+	}
+
+	folders, err := m.EditorFolders()
+	if err != nil {
+		return "", 0, err
+	}
+
+	path := m.EditorWorkspacePath(format)
+	if format == EditorFormatJetBrains {
+		err = m.writeJetBrainsModules(path, folders)
+	} else {
+		err = m.writeVSCodeWorkspace(path, folders)
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	return path, len(folders), nil
+}
+
+// RefreshEditorWorkspace updates the editor workspace if the repository is configured to keep
+// one current (see 'bt editor workspace --auto'). It does nothing otherwise. Worktree operations
+// of the manager don't call it; commands call it once after adding, removing or restoring worktrees.
+func (m *Manager) RefreshEditorWorkspace() error {
+	if m.Config == nil || m.Config.Repository.EditorWorkspace == "" {
+		return nil
+	}
+	_, _, err := m.WriteEditorWorkspace(m.Config.Repository.EditorWorkspace)
+	return err
+}
+
+// isManagedEditorFolder reports whether a folder of an editor workspace is a worktree location
+// that baretree maintains: anything inside the repository other than the repository root itself
+func (m *Manager) isManagedEditorFolder(path string) bool {
+	rel, err := filepath.Rel(m.RepoRoot, path)
+	if err != nil {
+		return false
+	}
+	return rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// editorFolderPath returns the path of a worktree relative to dir, slash-separated as editors
+// expect it in workspace files
+func editorFolderPath(dir, path string) string {
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(path)
+}
+
+// writeVSCodeWorkspace writes a VS Code multi-root workspace. Top-level keys other than "folders"
+// (settings, launch, extensions, ...) are kept as they are and in their original order. The file
+// may contain comments and trailing commas like VS Code allows, but comments are not preserved.
+func (m *Manager) writeVSCodeWorkspace(path string, folders []EditorFolder) error {
+	var doc vscodeWorkspace
+	var existing []map[string]json.RawMessage
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err == nil {
+		if err := doc.parse(stripJSONC(data)); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if raw, ok := doc.get("folders"); ok {
+			if err := json.Unmarshal(raw, &existing); err != nil {
+				return fmt.Errorf("failed to parse folders in %s: %w", path, err)
+			}
+		}
+	}
+
+	dir := filepath.Dir(path)
+	generated := make(map[string]bool)
+	var result []map[string]json.RawMessage
+	for _, f := range folders {
+		generated[filepath.Clean(f.Path)] = true
+		name, _ := json.Marshal(f.Name)
+		folderPath, _ := json.Marshal(editorFolderPath(dir, f.Path))
+		result = append(result, map[string]json.RawMessage{"name": name, "path": folderPath})
+	}
+
+	// Keep folders added by hand: the repository root itself, folders outside the repository
+	// and folders given by URI
+	for _, folder := range existing {
+		var folderPath string
+		if raw, ok := folder["path"]; ok {
+			_ = json.Unmarshal(raw, &folderPath)
+		}
+		if folderPath != "" {
+			absPath := filepath.FromSlash(folderPath)
+			if !filepath.IsAbs(absPath) {
+				absPath = filepath.Join(dir, absPath)
+			}
+			absPath = filepath.Clean(absPath)
+			if generated[absPath] || m.isManagedEditorFolder(absPath) {
+				continue
+			}
+		}
+		result = append(result, folder)
+	}
+
+	rawFolders, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+	doc.set("folders", rawFolders)
+	if _, ok := doc.get("settings"); !ok {
+		doc.set("settings", json.RawMessage("{}"))
+	}
+
+	out, err := doc.marshal()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(out, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// vscodeWorkspace is the top-level object of a .code-workspace file with its keys in file order
+type vscodeWorkspace struct {
+	keys   []string
+	values map[string]json.RawMessage
+}
+
+// parse reads a JSON object, remembering the order of its keys
+func (w *vscodeWorkspace) parse(data []byte) error {
+	w.keys = nil
+	w.values = make(map[string]json.RawMessage)
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("workspace file is not a JSON object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		w.set(key, value)
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return fmt.Errorf("unexpected content after the workspace object")
+	}
+	return nil
+}
+
+func (w *vscodeWorkspace) get(key string) (json.RawMessage, bool) {
+	value, ok := w.values[key]
+	return value, ok
+}
+
+// set replaces the value of key, appending the key if it is new
+func (w *vscodeWorkspace) set(key string, value json.RawMessage) {
+	if w.values == nil {
+		w.values = make(map[string]json.RawMessage)
+	}
+	if _, ok := w.values[key]; !ok {
+		w.keys = append(w.keys, key)
+	}
+	w.values[key] = value
+}
+
+// marshal writes the object tab-indented with the keys in order
+func (w *vscodeWorkspace) marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range w.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(w.values[key])
+	}
+	buf.WriteByte('}')
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "\t"); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// stripJSONC turns JSON with comments (as VS Code reads it) into plain JSON: line and block
+// comments outside strings and commas before a closing bracket are removed
+func stripJSONC(data []byte) []byte {
+	out := make([]byte, 0, len(data))
+	pendingComma := -1 // index in out of a comma that may turn out to be trailing
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"':
+			pendingComma = -1
+			start := i
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			end := i + 1
+			if end > len(data) {
+				end = len(data)
+			}
+			out = append(out, data[start:end]...)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			if i < len(data) {
+				out = append(out, '\n')
+			}
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				i = len(data)
+			} else {
+				i += end + 3
+			}
+			out = append(out, ' ')
+		case c == ',':
+			pendingComma = len(out)
+			out = append(out, c)
+		case c == '}' || c == ']':
+			if pendingComma >= 0 {
+				out[pendingComma] = ' '
+				pendingComma = -1
+			}
+			out = append(out, c)
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			out = append(out, c)
+		default:
+			pendingComma = -1
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// jetbrainsProject is the content of .idea/modules.xml
+type jetbrainsProject struct {
+	XMLName   xml.Name           `xml:"project"`
+	Version   string             `xml:"version,attr"`
+	Component jetbrainsComponent `xml:"component"`
+}
+
+type jetbrainsComponent struct {
+	Name    string            `xml:"name,attr"`
+	Modules []jetbrainsModule `xml:"modules>module"`
+}
+
+type jetbrainsModule struct {
+	FileURL  string `xml:"fileurl,attr"`
+	FilePath string `xml:"filepath,attr"`
+}
+
+// jetbrainsModuleTemplate is a module whose content root is a worktree. The module file lives in
+// .idea/baretree, so the worktree is referenced relative to it.
+const jetbrainsModuleTemplate = `<?xml version="1.0" encoding="UTF-8"?>
+<module type="WEB_MODULE" version="4">
+  <component name="NewModuleRootManager">
+    <content url="file://$MODULE_DIR$/%s" />
+    <orderEntry type="inheritedJdk" />
+    <orderEntry type="sourceFolder" forTests="false" />
+  </component>
+</module>
+`
+
+// writeJetBrainsModules writes one module per worktree to .idea/baretree and lists them in
+// .idea/modules.xml. Modules defined elsewhere are kept.
+func (m *Manager) writeJetBrainsModules(path string, folders []EditorFolder) error {
+	project := jetbrainsProject{
+		Version:   "4",
+		Component: jetbrainsComponent{Name: "ProjectModuleManager"},
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if err == nil {
+		if err := xml.Unmarshal(data, &project); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	managedPrefix := "$PROJECT_DIR$/" + jetbrainsModuleDir + "/"
+	var modules []jetbrainsModule
+	for _, mod := range project.Component.Modules {
+		if !strings.HasPrefix(mod.FilePath, managedPrefix) {
+			modules = append(modules, mod)
+		}
+	}
+
+	moduleDir := filepath.Join(m.RepoRoot, filepath.FromSlash(jetbrainsModuleDir))
+	if err := os.MkdirAll(moduleDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", moduleDir, err)
+	}
+
+	written := make(map[string]bool)
+	for _, f := range folders {
+		fileName := jetbrainsModuleFileName(f.Name, written)
+		written[fileName] = true
+
+		content := fmt.Sprintf(jetbrainsModuleTemplate, editorFolderPath(moduleDir, f.Path))
+		if err := os.WriteFile(filepath.Join(moduleDir, fileName), []byte(content), 0644); err != nil {
+			return fmt.Errorf("failed to write module for %s: %w", f.Name, err)
+		}
+		modules = append(modules, jetbrainsModule{
+			FileURL:  "file://" + managedPrefix + fileName,
+			FilePath: managedPrefix + fileName,
+		})
+	}
+
+	// Remove modules of worktrees that no longer exist
+	entries, err := os.ReadDir(moduleDir)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", moduleDir, err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".iml") && !written[entry.Name()] {
+			_ = os.Remove(filepath.Join(moduleDir, entry.Name()))
+		}
+	}
+
+	project.Component.Name = "ProjectModuleManager"
+	project.Component.Modules = modules
+	out, err := xml.MarshalIndent(project, "", "  ")
+	if err != nil {
+		return err
+	}
+	content := xml.Header + string(out) + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// jetbrainsModuleFileName returns a unique module file name for a worktree
+// (feature/auth -> feature-auth.iml)
+func jetbrainsModuleFileName(name string, taken map[string]bool) string {
+	base := strings.NewReplacer("/", "-", "\\", "-", ":", "-").Replace(name)
+	fileName := base + ".iml"
+	for i := 2; taken[fileName]; i++ {
+		fileName = fmt.Sprintf("%s-%d.iml", base, i)
+	}
+	return fileName
+}
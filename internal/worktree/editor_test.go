@@ -0,0 +1,202 @@
+package worktree
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidateEditorFormat(t *testing.T) {
+	for _, format := range EditorFormats {
+		if err := ValidateEditorFormat(format); err != nil {
+			t.Errorf("ValidateEditorFormat(%q) error = %v", format, err)
+		}
+	}
+	if err := ValidateEditorFormat("emacs"); err == nil {
+		t.Error("ValidateEditorFormat(\"emacs\") should fail")
+	}
+}
+
+func TestJetBrainsModuleFileName(t *testing.T) {
+	taken := map[string]bool{}
+	for _, tt := range []struct{ name, want string }{
+		{"main", "main.iml"},
+		{"feature/auth", "feature-auth.iml"},
+		{"feature-auth", "feature-auth-2.iml"},
+	} {
+		got := jetbrainsModuleFileName(tt.name, taken)
+		if got != tt.want {
+			t.Errorf("jetbrainsModuleFileName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+		taken[got] = true
+	}
+}
+
+func TestWriteVSCodeWorkspace(t *testing.T) {
+	m := createPruneTestRepo(t)
+	runTestGit(t, m.BareDir, "worktree", "add", "-b", "feature/auth", filepath.Join(m.RepoRoot, "feature", "auth"))
+
+	// Settings and folders outside the repository are kept, stale worktrees are dropped
+	path := m.EditorWorkspacePath(EditorFormatVSCode)
+	existing := `{
+	"folders": [
+		{"path": "gone"},
+		{"path": ".", "name": "root"},
+		{"path": "../docs"}
+	],
+	"settings": {"editor.tabSize": 2}
+}`
+	if err := os.WriteFile(path, []byte(existing), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	gotPath, count, err := m.WriteEditorWorkspace(EditorFormatVSCode)
+	if err != nil {
+		t.Fatalf("WriteEditorWorkspace() error = %v", err)
+	}
+	if gotPath != path || count != 2 {
+		t.Errorf("WriteEditorWorkspace() = %s, %d, want %s, 2", gotPath, count, path)
+	}
+
+	var doc struct {
+		Folders  []map[string]string `json:"folders"`
+		Settings map[string]any      `json:"settings"`
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("invalid workspace file: %v\n%s", err, data)
+	}
+
+	want := []map[string]string{
+		{"name": "main", "path": "main"},
+		{"name": "feature/auth", "path": "feature/auth"},
+		{"name": "root", "path": "."},
+		{"path": "../docs"},
+	}
+	if !reflect.DeepEqual(doc.Folders, want) {
+		t.Errorf("folders = %v, want %v", doc.Folders, want)
+	}
+	if doc.Settings["editor.tabSize"] != float64(2) {
+		t.Errorf("settings not kept: %v", doc.Settings)
+	}
+
+	// Comments and trailing commas are accepted and the key order is kept
+	jsonc := `{
+	// Shared settings
+	"settings": {"files.exclude": {"**/.git": true,},},
+	/* managed by baretree */
+	"folders": [{"path": "main"},],
+	"extensions": {"recommendations": ["golang.go"]},
+}`
+	if err := os.WriteFile(path, []byte(jsonc), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := m.WriteEditorWorkspace(EditorFormatVSCode); err != nil {
+		t.Fatalf("WriteEditorWorkspace() error = %v", err)
+	}
+	data, err = os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	settings := strings.Index(content, `"settings"`)
+	foldersKey := strings.Index(content, `"folders"`)
+	extensions := strings.Index(content, `"extensions"`)
+	if settings < 0 || !(settings < foldersKey && foldersKey < extensions) {
+		t.Errorf("key order not kept:\n%s", content)
+	}
+	if !strings.Contains(content, `"**/.git": true`) || !strings.Contains(content, `"feature/auth"`) {
+		t.Errorf("unexpected workspace file:\n%s", content)
+	}
+
+	// Invalid JSON is not overwritten
+	if err := os.WriteFile(path, []byte("{ \"folders\": [ }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := m.WriteEditorWorkspace(EditorFormatVSCode); err == nil {
+		t.Error("WriteEditorWorkspace() should fail for a file that is not valid JSON")
+	}
+}
+
+func TestStripJSONC(t *testing.T) {
+	tests := []struct{ input, want string }{
+		{`{"a": 1, // note` + "\n" + `}`, `{"a": 1  ` + "\n" + `}`},
+		{`{"a": /* x */ [1, 2,]}`, `{"a":   [1, 2 ]}`},
+		{`{"url": "http://example.com/*x*/", "s": "a\"//b"}`, `{"url": "http://example.com/*x*/", "s": "a\"//b"}`},
+	}
+	for _, tt := range tests {
+		if got := string(stripJSONC([]byte(tt.input))); got != tt.want {
+			t.Errorf("stripJSONC(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestWriteJetBrainsModules(t *testing.T) {
+	m := createPruneTestRepo(t)
+	featurePath := filepath.Join(m.RepoRoot, "feature", "auth")
+	runTestGit(t, m.BareDir, "worktree", "add", "-b", "feature/auth", featurePath)
+
+	// A module defined by hand is kept
+	ideaDir := filepath.Join(m.RepoRoot, ".idea")
+	if err := os.MkdirAll(ideaDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	modulesXML := `<?xml version="1.0" encoding="UTF-8"?>
+<project version="4">
+  <component name="ProjectModuleManager">
+    <modules>
+      <module fileurl="file://$PROJECT_DIR$/.idea/root.iml" filepath="$PROJECT_DIR$/.idea/root.iml" />
+    </modules>
+  </component>
+</project>`
+	if err := os.WriteFile(filepath.Join(ideaDir, "modules.xml"), []byte(modulesXML), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, count, err := m.WriteEditorWorkspace(EditorFormatJetBrains); err != nil || count != 2 {
+		t.Fatalf("WriteEditorWorkspace() = %d, %v", count, err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(ideaDir, "modules.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{".idea/root.iml", ".idea/baretree/main.iml", ".idea/baretree/feature-auth.iml"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("modules.xml does not contain %s:\n%s", want, data)
+		}
+	}
+
+	module, err := os.ReadFile(filepath.Join(ideaDir, "baretree", "feature-auth.iml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(module), `url="file://$MODULE_DIR$/../../feature/auth"`) {
+		t.Errorf("unexpected module content:\n%s", module)
+	}
+
+	// Refreshing with auto-update configured drops the module of a removed worktree
+	m.Config.Repository.EditorWorkspace = EditorFormatJetBrains
+	if err := m.Remove(featurePath, true); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.RefreshEditorWorkspace(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(ideaDir, "baretree", "feature-auth.iml")); !os.IsNotExist(err) {
+		t.Errorf("module of removed worktree still exists (err = %v)", err)
+	}
+	data, err = os.ReadFile(filepath.Join(ideaDir, "modules.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "feature-auth") || !strings.Contains(string(data), "root.iml") {
+		t.Errorf("unexpected modules.xml after removal:\n%s", data)
+	}
+}
@@ -0,0 +1,206 @@
+package worktree
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ForeachTarget is a directory 'bt foreach' runs a command in
+type ForeachTarget struct {
+	Label string   // Name shown in front of output lines (branch or repository path)
+	Path  string   // Working directory of the command
+	Names []string // Names matched by --filter in addition to Label (e.g., relative directory)
+	Env   []string // Additional environment variables
+}
+
+// ForeachResult is the outcome of running a command in a single target
+type ForeachResult struct {
+	Target   ForeachTarget
+	Success  bool
+	ExitCode int    // -1 if the command could not be started
+	Error    string // empty on success
+	Output   string // combined stdout and stderr (only if ForeachOptions.CaptureOutput is set)
+	Duration time.Duration
+}
+
+// ForeachOptions configures RunForeach
+type ForeachOptions struct {
+	// Parallel is the maximum number of commands running at the same time (1 if <= 0)
+	Parallel int
+	// Writer receives output lines prefixed with "[label] "; if nil, output is discarded
+	Writer io.Writer
+	// CaptureOutput stores the output of each command in its result
+	CaptureOutput bool
+}
+
+// ForeachTargets returns a foreach target for each worktree of the repository, labelled with its branch
+// (or its directory if detached). Each command gets the BT_REPO_ROOT, BT_WORKTREE_PATH and
+// BT_BRANCH environment variables.
+func (m *Manager) ForeachTargets() ([]ForeachTarget, error) {
+	worktrees, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []ForeachTarget
+	for _, wt := range worktrees {
+		if wt.IsBare {
+			continue
+		}
+		relPath, err := filepath.Rel(m.RepoRoot, wt.Path)
+		if err != nil {
+			relPath = wt.Path
+		}
+		relPath = filepath.ToSlash(relPath)
+
+		label := wt.Branch
+		if label == "" {
+			label = relPath
+		}
+		targets = append(targets, ForeachTarget{
+			Label: label,
+			Path:  wt.Path,
+			Names: []string{relPath},
+			Env: []string{
+				"BT_REPO_ROOT=" + m.RepoRoot,
+				"BT_WORKTREE_PATH=" + wt.Path,
+				"BT_BRANCH=" + wt.Branch,
+			},
+		})
+	}
+	return targets, nil
+}
+
+// MatchForeachFilter reports whether the label or one of the names of a target matches a glob
+// pattern (path.Match syntax, e.g. "feature/*"). An empty pattern matches every target.
+func MatchForeachFilter(target ForeachTarget, pattern string) (bool, error) {
+	if pattern == "" {
+		return true, nil
+	}
+	for _, name := range append([]string{target.Label}, target.Names...) {
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("invalid filter %q: %w", pattern, err)
+		}
+		if matched {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RunForeach runs a command in every target and returns the results in the order of targets.
+// A single argument is run as a shell command ('sh -c'), so it may use pipes and &&; several
+// arguments are run as a program with its arguments. A failing command does not stop the others.
+func RunForeach(targets []ForeachTarget, args []string, opts ForeachOptions) []ForeachResult {
+	parallel := opts.Parallel
+	if parallel <= 0 {
+		parallel = 1
+	}
+
+	var out io.Writer
+	if opts.Writer != nil {
+		out = &syncWriter{writer: opts.Writer}
+	}
+
+	results := make([]ForeachResult, len(targets))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = runForeachCommand(targets[i], args, out, opts.CaptureOutput)
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// runForeachCommand runs the command in a single target, writing its output line by line
+// with a "[label] " prefix so that concurrent commands can share out
+func runForeachCommand(target ForeachTarget, args []string, out io.Writer, capture bool) ForeachResult {
+	result := ForeachResult{Target: target}
+
+	var cmd *exec.Cmd
+	if len(args) == 1 {
+		cmd = exec.Command("sh", "-c", args[0])
+	} else {
+		cmd = exec.Command(args[0], args[1:]...)
+	}
+	cmd.Dir = target.Path
+	cmd.Env = append(os.Environ(), target.Env...)
+	cmd.WaitDelay = commandWaitDelay
+
+	prefix := fmt.Sprintf("[%s] ", target.Label)
+	var writers []io.Writer
+	var lineOutput *linePrefixWriter
+	if out != nil {
+		lineOutput = &linePrefixWriter{writer: out, prefix: prefix}
+		writers = append(writers, lineOutput)
+	}
+	var captured bytes.Buffer
+	if capture {
+		writers = append(writers, &captured)
+	}
+	if len(writers) > 0 {
+		w := io.MultiWriter(writers...)
+		cmd.Stdout = w
+		cmd.Stderr = w
+	}
+
+	start := time.Now()
+	err := cmd.Run()
+	result.Duration = time.Since(start)
+	if lineOutput != nil {
+		lineOutput.Flush()
+	}
+	result.Output = captured.String()
+
+	if err != nil {
+		result.Error = err.Error()
+		result.ExitCode = -1
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.ExitCode = exitErr.ExitCode()
+		}
+		if out != nil {
+			fmt.Fprintf(out, "%s✗ %s (%s)\n", prefix, result.Error, formatCommandDuration(result.Duration))
+		}
+		return result
+	}
+
+	result.Success = true
+	if out != nil {
+		fmt.Fprintf(out, "%s✓ (%s)\n", prefix, formatCommandDuration(result.Duration))
+	}
+	return result
+}
+
+// FormatForeachCommand returns a command line for display
+func FormatForeachCommand(args []string) string {
+	if len(args) == 1 {
+		return args[0]
+	}
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\$`&|;<>()*?[]#~") {
+			quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		} else {
+			quoted[i] = arg
+		}
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,104 @@
+package worktree
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatchForeachFilter(t *testing.T) {
+	target := ForeachTarget{Label: "feature/auth", Names: []string{"feature-auth"}}
+
+	tests := []struct {
+		pattern string
+		want    bool
+	}{
+		{"", true},
+		{"feature/*", true},
+		{"feature-*", true},
+		{"feature", false},
+		{"*auth", true},
+		{"bugfix/*", false},
+	}
+
+	for _, tt := range tests {
+		got, err := MatchForeachFilter(target, tt.pattern)
+		if err != nil {
+			t.Fatalf("MatchForeachFilter(%q) error = %v", tt.pattern, err)
+		}
+		if got != tt.want {
+			t.Errorf("MatchForeachFilter(%q) = %v, want %v", tt.pattern, got, tt.want)
+		}
+	}
+
+	if _, err := MatchForeachFilter(target, "["); err == nil {
+		t.Error("expected error for invalid pattern")
+	}
+}
+
+func TestFormatForeachCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"make test && echo ok"}, "make test && echo ok"},
+		{[]string{"git", "status", "-s"}, "git status -s"},
+		{[]string{"git", "commit", "-m", "it's done"}, `git commit -m 'it'\''s done'`},
+	}
+
+	for _, tt := range tests {
+		if got := FormatForeachCommand(tt.args); got != tt.want {
+			t.Errorf("FormatForeachCommand(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestRunForeach(t *testing.T) {
+	targets := []ForeachTarget{
+		{Label: "main", Path: t.TempDir(), Env: []string{"BT_BRANCH=main"}},
+		{Label: "feature/auth", Path: t.TempDir(), Env: []string{"BT_BRANCH=feature/auth"}},
+	}
+
+	var out bytes.Buffer
+	results := RunForeach(targets, []string{`echo "branch $BT_BRANCH"; test "$BT_BRANCH" = main || exit 3`}, ForeachOptions{
+		Parallel:      2,
+		Writer:        &out,
+		CaptureOutput: true,
+	})
+
+	if len(results) != 2 {
+		t.Fatalf("expected 2 results, got %d", len(results))
+	}
+	if !results[0].Success || results[0].ExitCode != 0 {
+		t.Errorf("expected main to succeed, got %+v", results[0])
+	}
+	if results[1].Success || results[1].ExitCode != 3 {
+		t.Errorf("expected feature/auth to fail with exit code 3, got %+v", results[1])
+	}
+	if results[1].Output != "branch feature/auth\n" {
+		t.Errorf("unexpected captured output: %q", results[1].Output)
+	}
+
+	for _, line := range []string{"[main] branch main\n", "[main] ✓", "[feature/auth] branch feature/auth\n", "[feature/auth] ✗ exit status 3"} {
+		if !strings.Contains(out.String(), line) {
+			t.Errorf("expected output to contain %q, got:\n%s", line, out.String())
+		}
+	}
+}
+
+func TestRunForeachProgram(t *testing.T) {
+	dir := t.TempDir()
+	results := RunForeach([]ForeachTarget{{Label: "main", Path: dir}}, []string{"sh", "-c", "pwd"}, ForeachOptions{CaptureOutput: true})
+	if !results[0].Success {
+		t.Fatalf("expected success, got %+v", results[0])
+	}
+	if !strings.HasSuffix(strings.TrimSpace(results[0].Output), filepath.Base(dir)) {
+		t.Errorf("expected command to run in %s, got %q", dir, results[0].Output)
+	}
+
+	results = RunForeach([]ForeachTarget{{Label: "main", Path: dir}}, []string{"bt-foreach-no-such-program"}, ForeachOptions{})
+	if results[0].Success || results[0].ExitCode == 0 {
+		t.Errorf("expected failure for missing program, got %+v", results[0])
+	}
+}
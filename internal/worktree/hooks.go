@@ -0,0 +1,159 @@
+package worktree
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/amaya382/baretree/internal/config"
+)
+
+// HookContext describes the worktree a lifecycle hook runs for
+type HookContext struct {
+	WorktreePath string
+	Branch       string
+	// Dir is the working directory for hook commands (defaults to WorktreePath)
+	Dir string
+	// OldWorktreePath and OldBranch are set for post-rename hooks
+	OldWorktreePath string
+	OldBranch       string
+}
+
+// HookError is returned when a hook command fails for an event that can veto the operation
+type HookError struct {
+	Event   string
+	Command string
+	Err     string
+}
+
+func (e *HookError) Error() string {
+	return fmt.Sprintf("%s hook failed: %s: %s", e.Event, e.Command, e.Err)
+}
+
+// IsVetoEvent reports whether a failing hook for event aborts the operation
+func IsVetoEvent(event string) bool {
+	return strings.HasPrefix(event, "pre-")
+}
+
+// HookEnv returns the environment variables passed to hook commands:
+//
+//	BT_HOOK              lifecycle event (e.g., "pre-remove")
+//	BT_REPO_ROOT         repository root directory
+//	BT_WORKTREE_PATH     path of the worktree
+//	BT_BRANCH            branch of the worktree
+//	BT_DEFAULT_BRANCH    configured default branch
+//	BT_SHARED_DIR        directory holding managed shared files (.shared)
+//	BT_OLD_WORKTREE_PATH previous worktree path (post-rename only)
+//	BT_OLD_BRANCH        previous branch name (post-rename only)
+func (m *Manager) HookEnv(event string, ctx HookContext) []string {
+	env := []string{
+		"BT_HOOK=" + event,
+		"BT_REPO_ROOT=" + m.RepoRoot,
+		"BT_WORKTREE_PATH=" + ctx.WorktreePath,
+		"BT_BRANCH=" + ctx.Branch,
+		"BT_DEFAULT_BRANCH=" + m.GetDefaultBranch(),
+		"BT_SHARED_DIR=" + m.GetSharedDir(),
+	}
+	if event == config.HookPostRename {
+		env = append(env,
+			"BT_OLD_WORKTREE_PATH="+ctx.OldWorktreePath,
+			"BT_OLD_BRANCH="+ctx.OldBranch,
+		)
+	}
+	return env
+}
+
+// RunHooks executes the commands configured for a lifecycle event.
+// Output is written to the provided writer in real-time. If writer is nil, output is discarded.
+// For pre-* events, execution stops at the first failing command and a *HookError is returned
+// so the caller can abort the operation. For other events, failures are reported in the results only.
+func (m *Manager) RunHooks(event string, ctx HookContext, writer io.Writer) ([]CommandResult, error) {
+	hooks := m.Config.Hooks(event)
+	if hooks == nil {
+		return nil, fmt.Errorf("unknown hook event: %s", event)
+	}
+	if len(*hooks) == 0 {
+		return nil, nil
+	}
+
+	dir := ctx.Dir
+	if dir == "" {
+		dir = ctx.WorktreePath
+	}
+	env := append(os.Environ(), m.HookEnv(event, ctx)...)
+
+	if writer != nil {
+		fmt.Fprintf(writer, "\n%s hooks:\n", hookTitle(event))
+	}
+
+	var results []CommandResult
+	for _, hook := range *hooks {
+		result := runShellCommand(context.Background(), hook.Command, dir, env, writer, "", 0)
+		results = append(results, result)
+
+		if !result.Success && IsVetoEvent(event) {
+			return results, &HookError{Event: event, Command: hook.Command, Err: result.Error}
+		}
+	}
+
+	return results, nil
+}
+
+// AddHook adds a command to the hook list of an event and saves the configuration
+func (m *Manager) AddHook(event, command string) error {
+	hooks := m.Config.Hooks(event)
+	if hooks == nil {
+		return fmt.Errorf("unknown hook event: %s (must be one of: %s)", event, strings.Join(config.HookEvents, ", "))
+	}
+
+	for _, h := range *hooks {
+		if h.Command == command {
+			return fmt.Errorf("%s hook is already configured: %s", event, command)
+		}
+	}
+
+	*hooks = append(*hooks, config.HookAction{Command: command})
+
+	if err := config.SaveConfig(m.RepoRoot, m.Config); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// RemoveHook removes a command from the hook list of an event and saves the configuration
+func (m *Manager) RemoveHook(event, command string) error {
+	hooks := m.Config.Hooks(event)
+	if hooks == nil {
+		return fmt.Errorf("unknown hook event: %s (must be one of: %s)", event, strings.Join(config.HookEvents, ", "))
+	}
+
+	var remaining []config.HookAction
+	found := false
+	for _, h := range *hooks {
+		if h.Command == command {
+			found = true
+			continue
+		}
+		remaining = append(remaining, h)
+	}
+	if !found {
+		return fmt.Errorf("%s hook not found: %s", event, command)
+	}
+
+	*hooks = remaining
+
+	if err := config.SaveConfig(m.RepoRoot, m.Config); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
+// hookTitle returns a display title for an event (e.g., "pre-remove" -> "Pre-remove")
+func hookTitle(event string) string {
+	if event == "" {
+		return event
+	}
+	return strings.ToUpper(event[:1]) + event[1:]
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/amaya382/baretree/internal/config"
 	"github.com/amaya382/baretree/internal/git"
@@ -60,6 +61,18 @@ func (e *ErrRefConflict) Error() string {
 		e.BranchName, e.ConflictingRef, e.ConflictingRef, e.ConflictingRef)
 }
 
+// ErrRequiredCommandFailed is returned when a required post-create command fails and
+// the new worktree has been rolled back
+type ErrRequiredCommandFailed struct {
+	BranchName string
+	Command    string
+	Err        string
+}
+
+func (e *ErrRequiredCommandFailed) Error() string {
+	return fmt.Sprintf("required post-create command failed for '%s': %s: %s", e.BranchName, e.Command, e.Err)
+}
+
 // AddOptions contains options for adding a worktree
 type AddOptions struct {
 	NewBranch  bool   // Create a new branch
@@ -79,9 +92,12 @@ func (m *Manager) Add(branchName string, newBranch bool, baseBranch string, cmdO
 // Returns the worktree path, post-create results, and any error
 // cmdOutput is the writer for post-create command output (pass nil to discard)
 func (m *Manager) AddWithOptions(branchName string, opts AddOptions, cmdOutput io.Writer) (string, *PostCreateResult, error) {
-	// Construct worktree path from branch name
+	// Construct worktree path from branch name and the naming template
 	// feature/auth -> {repoRoot}/feature/auth
-	worktreePath := filepath.Join(m.RepoRoot, branchName)
+	worktreePath, err := m.WorktreePath(branchName)
+	if err != nil {
+		return "", nil, err
+	}
 
 	// Check if the branch already has a worktree
 	if !opts.NewBranch {
@@ -132,15 +148,95 @@ func (m *Manager) AddWithOptions(branchName string, opts AddOptions, cmdOutput i
 		fmt.Fprintf(cmdOutput, "Worktree created at %s\n", worktreePath)
 	}
 
+	// Record lifecycle metadata (best effort, used by 'bt prune')
+	baseBranch := m.baseBranchFor(opts)
+	baseCommit, _ := git.NewExecutor(worktreePath).Execute("rev-parse", "HEAD")
+	_ = m.SaveMetadata(worktreePath, Metadata{
+		CreatedAt:  time.Now(),
+		BaseBranch: baseBranch,
+		BaseCommit: baseCommit,
+	})
+
 	// Apply post-create configuration (files and commands)
-	postCreateResult, err := m.ApplyPostCreateConfig(worktreePath, cmdOutput)
+	postCreateResult, err := m.applyPostCreateConfig(PostCreateContext{
+		WorktreePath: worktreePath,
+		Branch:       branchName,
+		BaseBranch:   baseBranch,
+	}, cmdOutput)
 	if err != nil {
 		return "", nil, fmt.Errorf("failed to apply post-create config: %w", err)
 	}
 
+	// Roll back instead of leaving a half-initialized worktree behind
+	if failed := failedRequiredCommand(postCreateResult.CommandResults); failed != nil {
+		createdBranch := opts.NewBranch || opts.TrackRef != ""
+		m.rollbackAdd(worktreePath, branchName, createdBranch, cmdOutput)
+		return "", postCreateResult, &ErrRequiredCommandFailed{
+			BranchName: branchName,
+			Command:    failed.label(),
+			Err:        failed.Error,
+		}
+	}
+
 	return worktreePath, postCreateResult, nil
 }
 
+// failedRequiredCommand returns the first required command that did not succeed, or nil
+func failedRequiredCommand(results []CommandResult) *CommandResult {
+	for i, r := range results {
+		if r.Required && !r.Success {
+			return &results[i]
+		}
+	}
+	return nil
+}
+
+// rollbackAdd removes a worktree created by AddWithOptions and, if the branch was
+// created along with it, deletes the branch
+func (m *Manager) rollbackAdd(worktreePath, branchName string, deleteBranch bool, cmdOutput io.Writer) {
+	if cmdOutput != nil {
+		fmt.Fprintln(cmdOutput, "\nRequired post-create command failed, rolling back:")
+	}
+
+	if err := m.Remove(worktreePath, true); err != nil {
+		if cmdOutput != nil {
+			fmt.Fprintf(cmdOutput, "  ✗ %v\n", err)
+		}
+		return
+	}
+	if cmdOutput != nil {
+		fmt.Fprintf(cmdOutput, "  ✓ Worktree removed: %s\n", worktreePath)
+	}
+
+	if !deleteBranch {
+		return
+	}
+	if _, err := m.Executor.Execute("branch", "-D", branchName); err != nil {
+		if cmdOutput != nil {
+			fmt.Fprintf(cmdOutput, "  ✗ failed to delete branch '%s': %v\n", branchName, err)
+		}
+		return
+	}
+	if cmdOutput != nil {
+		fmt.Fprintf(cmdOutput, "  ✓ Branch '%s' deleted\n", branchName)
+	}
+}
+
+// baseBranchFor returns the branch or commit a worktree added with opts was created from
+func (m *Manager) baseBranchFor(opts AddOptions) string {
+	switch {
+	case opts.NewBranch && opts.BaseBranch != "":
+		return opts.BaseBranch
+	case opts.NewBranch:
+		// git worktree add -b without a start point branches from HEAD
+		return m.Executor.ResolveHEAD()
+	case opts.TrackRef != "":
+		return opts.TrackRef
+	default:
+		return ""
+	}
+}
+
 // Remove removes a worktree
 func (m *Manager) Remove(worktreePath string, force bool) error {
 	args := []string{"worktree", "remove"}
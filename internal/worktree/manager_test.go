@@ -4,6 +4,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/amaya382/baretree/internal/config"
@@ -230,3 +231,90 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+func TestPostCreateEnv(t *testing.T) {
+	mgr := &Manager{
+		RepoRoot: "/home/user/project",
+		BareDir:  "/home/user/project/.git",
+		Config: &config.Config{
+			Repository: config.Repository{DefaultBranch: "develop"},
+		},
+	}
+
+	env := mgr.PostCreateEnv(PostCreateContext{
+		WorktreePath: "/home/user/project/feature/auth",
+		Branch:       "feature/auth",
+		BaseBranch:   "origin/develop",
+	})
+
+	expected := []string{
+		"BT_REPO_ROOT=/home/user/project",
+		"BT_WORKTREE_PATH=/home/user/project/feature/auth",
+		"BT_BRANCH=feature/auth",
+		"BT_BASE_BRANCH=origin/develop",
+		"BT_DEFAULT_BRANCH=develop",
+		"BT_SHARED_DIR=" + filepath.Join("/home/user/project", SharedDir),
+	}
+	if len(env) != len(expected) {
+		t.Fatalf("expected %d variables, got %d: %v", len(expected), len(env), env)
+	}
+	for i := range expected {
+		if env[i] != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], env[i])
+		}
+	}
+}
+
+func TestHookEnv(t *testing.T) {
+	mgr := &Manager{
+		RepoRoot: "/home/user/project",
+		Config:   config.DefaultConfig(),
+	}
+
+	ctx := HookContext{
+		WorktreePath:    "/home/user/project/feature/new",
+		Branch:          "feature/new",
+		OldWorktreePath: "/home/user/project/feature/old",
+		OldBranch:       "feature/old",
+	}
+
+	env := strings.Join(mgr.HookEnv(config.HookPostRename, ctx), "\n")
+	for _, want := range []string{"BT_HOOK=post-rename", "BT_BRANCH=feature/new", "BT_OLD_BRANCH=feature/old", "BT_DEFAULT_BRANCH=main"} {
+		if !strings.Contains(env, want) {
+			t.Errorf("expected %q in post-rename env:\n%s", want, env)
+		}
+	}
+
+	env = strings.Join(mgr.HookEnv(config.HookPreRemove, ctx), "\n")
+	if strings.Contains(env, "BT_OLD_BRANCH") {
+		t.Errorf("BT_OLD_BRANCH should only be set for post-rename:\n%s", env)
+	}
+}
+
+func TestRunHooksVeto(t *testing.T) {
+	dir := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.PreRemove = []config.HookAction{{Command: "exit 3"}, {Command: "touch should-not-run"}}
+	cfg.PostRemove = []config.HookAction{{Command: "exit 1"}, {Command: "touch did-run"}}
+	mgr := &Manager{RepoRoot: dir, Config: cfg}
+
+	results, err := mgr.RunHooks(config.HookPreRemove, HookContext{WorktreePath: dir}, nil)
+	var hookErr *HookError
+	if !errors.As(err, &hookErr) {
+		t.Fatalf("expected HookError for failing pre-remove hook, got %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("expected execution to stop after the first failure, got %d results", len(results))
+	}
+	if _, err := os.Stat(filepath.Join(dir, "should-not-run")); err == nil {
+		t.Error("hook after the failing pre-remove hook should not run")
+	}
+
+	results, err = mgr.RunHooks(config.HookPostRemove, HookContext{WorktreePath: dir}, nil)
+	if err != nil {
+		t.Fatalf("post-remove failures should not return an error, got %v", err)
+	}
+	if len(results) != 2 || results[0].Success || !results[1].Success {
+		t.Errorf("unexpected post-remove results: %+v", results)
+	}
+}
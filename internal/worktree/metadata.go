@@ -0,0 +1,88 @@
+package worktree
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/amaya382/baretree/internal/git"
+)
+
+// metadataFile is the name of the lifecycle metadata file in a worktree's administrative
+// directory (.git/worktrees/<name>/), so it is removed together with the worktree
+const metadataFile = "baretree.json"
+
+// Metadata records lifecycle information about a worktree created by bt
+type Metadata struct {
+	CreatedAt  time.Time `json:"created_at"`
+	BaseBranch string    `json:"base_branch,omitempty"` // branch or commit the branch was created from
+	BaseCommit string    `json:"base_commit,omitempty"` // commit checked out when the worktree was created
+	Port       int       `json:"port,omitempty"`        // .Port assigned when a template was first rendered (see templatePort)
+	// Templates holds the SHA-256 of each rendered post-create template by source path, so an
+	// unchanged rendering is not mistaken for work of its own (see isPostCreateFile)
+	Templates map[string]string `json:"templates,omitempty"`
+}
+
+// metadataPath returns the path of the metadata file of a worktree
+func metadataPath(worktreePath string) (string, error) {
+	gitDir, err := git.NewExecutor(worktreePath).Execute("rev-parse", "--absolute-git-dir")
+	if err != nil {
+		return "", fmt.Errorf("failed to locate git directory of %s: %w", worktreePath, err)
+	}
+	return filepath.Join(gitDir, metadataFile), nil
+}
+
+// SaveMetadata writes the lifecycle metadata of a worktree
+func (m *Manager) SaveMetadata(worktreePath string, md Metadata) error {
+	path, err := metadataPath(worktreePath)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(md, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode worktree metadata: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write worktree metadata: %w", err)
+	}
+	return nil
+}
+
+// LoadMetadata reads the lifecycle metadata of a worktree.
+// Returns nil without error for worktrees that were not created by bt.
+func (m *Manager) LoadMetadata(worktreePath string) (*Metadata, error) {
+	path, err := metadataPath(worktreePath)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read worktree metadata: %w", err)
+	}
+
+	var md Metadata
+	if err := json.Unmarshal(data, &md); err != nil {
+		return nil, fmt.Errorf("failed to parse worktree metadata %s: %w", path, err)
+	}
+	return &md, nil
+}
+
+// updateMetadata applies fn to the metadata of a worktree (empty if it has none) and saves it
+func (m *Manager) updateMetadata(worktreePath string, fn func(md *Metadata)) error {
+	md, err := m.LoadMetadata(worktreePath)
+	if err != nil {
+		return err
+	}
+	if md == nil {
+		md = &Metadata{}
+	}
+	fn(md)
+	return m.SaveMetadata(worktreePath, *md)
+}
@@ -0,0 +1,146 @@
+package worktree
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"github.com/amaya382/baretree/internal/config"
+)
+
+// NamingData holds the variables available to the worktree naming template
+type NamingData struct {
+	Branch string // branch name (e.g., "users/alice/JIRA-1234-fix-login")
+	Slug   string // branch sanitized for use in names (e.g., "users-alice-jira-1234-fix-login")
+	Base   string // last component of the branch name (e.g., "JIRA-1234-fix-login")
+}
+
+// namingFuncs are the functions available to the worktree naming template.
+// Like in pipelines, the value to transform is the last argument:
+// {{ .Branch | trimPrefix "users/alice/" | replace "/" "-" | lower | trunc 30 }}
+var namingFuncs = template.FuncMap{
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+	"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+	"trunc":      truncate,
+	"regexReplace": func(pattern, repl, s string) (string, error) {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return "", err
+		}
+		return re.ReplaceAllString(s, repl), nil
+	},
+}
+
+// truncate shortens s to at most n characters, dropping a trailing "-" or "/" left by the cut
+func truncate(n int, s string) string {
+	runes := []rune(s)
+	if n < 0 || len(runes) <= n {
+		return s
+	}
+	return strings.TrimRight(string(runes[:n]), "-/")
+}
+
+// WorktreeDir maps a branch name to the directory of its worktree, relative to the repository
+// root and slash-separated, by rendering the naming template (e.g., {{ .Branch | replace "/" "-" }}).
+// An empty template keeps the branch name as is.
+func WorktreeDir(namingTemplate, branch string) (string, error) {
+	if namingTemplate == "" {
+		return branch, nil
+	}
+	if branch == "" {
+		return "", fmt.Errorf("cannot name a worktree without a branch")
+	}
+
+	tmpl, err := template.New("worktree_template").Funcs(namingFuncs).Option("missingkey=error").Parse(namingTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid worktree naming template: %w", err)
+	}
+
+	var buf bytes.Buffer
+	data := NamingData{
+		Branch: branch,
+		Slug:   BranchSlug(branch),
+		Base:   path.Base(branch),
+	}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render worktree naming template for '%s': %w", branch, err)
+	}
+
+	dir := strings.TrimSpace(buf.String())
+	if err := validateWorktreeDir(dir); err != nil {
+		return "", fmt.Errorf("worktree naming template maps '%s' to %w", branch, err)
+	}
+	return dir, nil
+}
+
+// validateWorktreeDir checks that a rendered worktree directory stays inside the repository root
+// and does not clash with the directories baretree manages itself
+func validateWorktreeDir(dir string) error {
+	if dir == "" {
+		return fmt.Errorf("an empty directory")
+	}
+	if strings.Contains(dir, `\`) || path.IsAbs(dir) || filepath.IsAbs(dir) {
+		return fmt.Errorf("%q, which is not a relative slash-separated path", dir)
+	}
+	for _, part := range strings.Split(dir, "/") {
+		switch part {
+		case "", ".", "..":
+			return fmt.Errorf("%q, which is not a clean path inside the repository root", dir)
+		case config.BareDir, SharedDir:
+			return fmt.Errorf("%q, which is reserved for baretree", dir)
+		}
+	}
+	return nil
+}
+
+// ValidateNamingTemplate checks that a naming template parses and maps sample branch names
+// to valid directories
+func ValidateNamingTemplate(namingTemplate string) error {
+	for _, branch := range []string{"main", "feature/auth", "users/alice/JIRA-1234-fix-login"} {
+		if _, err := WorktreeDir(namingTemplate, branch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WorktreeDir returns the directory of the worktree for a branch relative to the repository root,
+// as given by the repository's naming template (the branch name if none is configured)
+func (m *Manager) WorktreeDir(branch string) (string, error) {
+	namingTemplate := ""
+	if m.Config != nil {
+		namingTemplate = m.Config.Repository.WorktreeTemplate
+	}
+	dir, err := WorktreeDir(namingTemplate, branch)
+	if err != nil {
+		return "", err
+	}
+	return filepath.FromSlash(dir), nil
+}
+
+// WorktreePath returns the absolute path of the worktree for a branch
+func (m *Manager) WorktreePath(branch string) (string, error) {
+	dir, err := m.WorktreeDir(branch)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(m.RepoRoot, dir), nil
+}
+
+// ExpectedWorktreeDir is like WorktreeDir, but falls back to the branch name if the naming
+// template cannot be applied. It is used to detect worktrees whose directory does not match
+// their branch, where an error would only hide the actual state.
+func (m *Manager) ExpectedWorktreeDir(branch string) string {
+	dir, err := m.WorktreeDir(branch)
+	if err != nil {
+		return filepath.FromSlash(branch)
+	}
+	return dir
+}
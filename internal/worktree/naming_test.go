@@ -0,0 +1,93 @@
+package worktree
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/amaya382/baretree/internal/config"
+)
+
+func TestWorktreeDir(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		branch   string
+		want     string
+		wantErr  bool
+	}{
+		{"no template", "", "users/alice/JIRA-1234-fix-login", "users/alice/JIRA-1234-fix-login", false},
+		{"flatten", `{{ .Branch | replace "/" "-" }}`, "feature/auth", "feature-auth", false},
+		{"slug", `{{ .Slug }}`, "Feature/JIRA-123_Fix", "feature-jira-123-fix", false},
+		{"base", `{{ .Base }}`, "users/alice/fix-login", "fix-login", false},
+		{
+			"strip prefix, lowercase and truncate",
+			`{{ .Branch | trimPrefix "users/alice/" | lower | trunc 10 }}`,
+			"users/alice/JIRA-1234-fix-login", "jira-1234", false,
+		},
+		{"prefix not present", `{{ .Branch | trimPrefix "users/alice/" }}`, "main", "main", false},
+		{"regex", `{{ .Branch | regexReplace "^[^/]+/" "" }}`, "feature/auth", "auth", false},
+		{"surrounding whitespace", "\n{{ .Branch }}\n", "main", "main", false},
+		{"parse error", `{{ .Branch`, "main", "", true},
+		{"unknown field", `{{ .Name }}`, "main", "", true},
+		{"empty result", `{{ .Branch | trimPrefix "main" }}`, "main", "", true},
+		{"escapes the root", `../{{ .Branch }}`, "main", "", true},
+		{"absolute", `/tmp/{{ .Branch }}`, "main", "", true},
+		{"empty component", `{{ .Branch }}/`, "main", "", true},
+		{"bare directory", `{{ .Branch | trimPrefix "x" }}`, config.BareDir, "", true},
+		{"shared directory", `{{ .Base }}`, "feature/" + SharedDir, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := WorktreeDir(tt.template, tt.branch)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("WorktreeDir() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("WorktreeDir() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateNamingTemplate(t *testing.T) {
+	if err := ValidateNamingTemplate(`{{ .Branch | replace "/" "-" | lower | trunc 30 }}`); err != nil {
+		t.Errorf("expected valid template, got %v", err)
+	}
+	// Maps "main" to an empty directory
+	if err := ValidateNamingTemplate(`{{ .Branch | trimPrefix "main" }}`); err == nil {
+		t.Error("expected error for template producing an empty directory")
+	}
+	if err := ValidateNamingTemplate(`{{ .Branch | regexReplace "(" "" }}`); err == nil {
+		t.Error("expected error for invalid regular expression")
+	}
+}
+
+func TestManagerWorktreePath(t *testing.T) {
+	repoRoot := t.TempDir()
+	cfg := config.DefaultConfig()
+	m := NewManager(repoRoot, filepath.Join(repoRoot, config.BareDir), cfg)
+
+	path, err := m.WorktreePath("feature/auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(repoRoot, "feature", "auth"); path != want {
+		t.Errorf("WorktreePath() without template = %s, want %s", path, want)
+	}
+
+	cfg.Repository.WorktreeTemplate = `{{ .Branch | replace "/" "-" }}`
+	path, err = m.WorktreePath("feature/auth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(repoRoot, "feature-auth"); path != want {
+		t.Errorf("WorktreePath() with template = %s, want %s", path, want)
+	}
+
+	// A branch the template cannot name falls back to the branch name
+	cfg.Repository.WorktreeTemplate = `{{ .Branch | trimPrefix "main" }}`
+	if got := m.ExpectedWorktreeDir("main"); got != "main" {
+		t.Errorf("ExpectedWorktreeDir() = %s, want main", got)
+	}
+}
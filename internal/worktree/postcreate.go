@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"time"
 
 	"github.com/amaya382/baretree/internal/config"
 	"github.com/amaya382/baretree/internal/git"
@@ -44,10 +44,16 @@ type PostCreateStatus struct {
 
 // CommandResult represents the result of executing a command
 type CommandResult struct {
-	Command string
-	Success bool
-	Output  string
-	Error   string
+	Command  string
+	Name     string // name of the command (post-create commands only, empty if unnamed)
+	Success  bool
+	Skipped  bool // not run because a dependency or a required command failed
+	Required bool // failure rolls back worktree creation (post-create commands only)
+	TimedOut bool // killed after exceeding its timeout
+	Attempts int  // number of times the command was run (including retries)
+	Output   string
+	Error    string
+	Duration time.Duration // total run time across attempts
 }
 
 // FileActionResult represents the result of applying a file action
@@ -168,14 +174,8 @@ func (m *Manager) AddPostCreate(source string, actionType string, managed bool)
 
 	// For command type, just add to config (no file operations needed)
 	if actionType == "command" {
-		newAction := config.PostCreateAction{
-			Source: source,
-			Type:   actionType,
-		}
-		m.Config.PostCreate = append(m.Config.PostCreate, newAction)
-
-		if err := config.SaveConfig(m.RepoRoot, m.Config); err != nil {
-			return nil, fmt.Errorf("failed to save config: %w", err)
+		if err := m.AddPostCreateCommand(config.PostCreateAction{Source: source}); err != nil {
+			return nil, err
 		}
 
 		return &PostCreateApplyResult{
@@ -205,6 +205,13 @@ func (m *Manager) AddPostCreate(source string, actionType string, managed bool)
 		return nil, fmt.Errorf("source file does not exist: %s", sourcePath)
 	}
 
+	// Reject broken templates before moving files or saving config
+	if actionType == "template" {
+		if err := ValidateTemplate(sourcePath); err != nil {
+			return nil, err
+		}
+	}
+
 	// For managed: move source to .shared directory
 	if managed {
 		sharedDir := m.GetSharedDir()
@@ -243,6 +250,28 @@ func (m *Manager) AddPostCreate(source string, actionType string, managed bool)
 	return result, nil
 }
 
+// AddPostCreateCommand adds a command-type post-create action, including its name,
+// parallel and depends_on options, after validating the resulting execution order
+func (m *Manager) AddPostCreateCommand(action config.PostCreateAction) error {
+	action.Type = "command"
+	for _, a := range m.Config.PostCreate {
+		if a.Source == action.Source {
+			return fmt.Errorf("post-create action %s is already configured", action.Source)
+		}
+	}
+
+	actions := append(append([]config.PostCreateAction{}, m.Config.PostCreate...), action)
+	if err := ValidatePostCreateCommands(actions); err != nil {
+		return err
+	}
+	m.Config.PostCreate = actions
+
+	if err := config.SaveConfig(m.RepoRoot, m.Config); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	return nil
+}
+
 // applyPostCreateToAllWorktrees applies a post-create configuration to all worktrees
 func (m *Manager) applyPostCreateToAllWorktrees(action config.PostCreateAction) (*PostCreateApplyResult, error) {
 	// Commands are not applied to existing worktrees
@@ -313,6 +342,10 @@ func (m *Manager) applyPostCreateToAllWorktrees(action config.PostCreateAction)
 			if err := copyFile(sourcePath, targetPath); err != nil {
 				return nil, fmt.Errorf("failed to copy to %s: %w", targetPath, err)
 			}
+		case "template":
+			if err := m.renderWorktreeTemplate(action, sourcePath, wt.Path, wt.Branch); err != nil {
+				return nil, fmt.Errorf("failed to render template to %s: %w", targetPath, err)
+			}
 		default:
 			return nil, fmt.Errorf("unknown post-create type: %s", action.Type)
 		}
@@ -348,7 +381,11 @@ func (m *Manager) RemovePostCreate(source string, removeAll bool) (*PostCreateRe
 
 	// For command type, just remove from config
 	if found.Type == "command" {
-		m.Config.PostCreate = append(m.Config.PostCreate[:foundIndex], m.Config.PostCreate[foundIndex+1:]...)
+		remaining := append(append([]config.PostCreateAction{}, m.Config.PostCreate[:foundIndex]...), m.Config.PostCreate[foundIndex+1:]...)
+		if err := ValidatePostCreateCommands(remaining); err != nil {
+			return nil, fmt.Errorf("cannot remove %s: %w", source, err)
+		}
+		m.Config.PostCreate = remaining
 		if err := config.SaveConfig(m.RepoRoot, m.Config); err != nil {
 			return nil, fmt.Errorf("failed to save config: %w", err)
 		}
@@ -618,60 +655,64 @@ func (e *PostCreateConflictError) Error() string {
 	return fmt.Sprintf("conflicts detected in %d location(s)", len(e.Conflicts))
 }
 
+// PostCreateContext describes the worktree that post-create commands run for
+type PostCreateContext struct {
+	WorktreePath string
+	Branch       string
+	// BaseBranch is the branch or commit the worktree's branch was created from (empty if unknown)
+	BaseBranch string
+}
+
+// newPostCreateContext builds a context for an existing worktree, reading the branch from its HEAD
+func newPostCreateContext(worktreePath string) PostCreateContext {
+	branch, _ := git.NewExecutor(worktreePath).Execute("symbolic-ref", "--short", "HEAD")
+	return PostCreateContext{WorktreePath: worktreePath, Branch: branch}
+}
+
+// PostCreateEnv returns the environment variables passed to post-create commands:
+//
+//	BT_REPO_ROOT       repository root directory
+//	BT_WORKTREE_PATH   path of the new worktree
+//	BT_BRANCH          branch checked out in the new worktree
+//	BT_BASE_BRANCH     branch or commit the branch was created from (empty if unknown)
+//	BT_DEFAULT_BRANCH  configured default branch
+//	BT_SHARED_DIR      directory holding managed shared files (.shared)
+func (m *Manager) PostCreateEnv(ctx PostCreateContext) []string {
+	return []string{
+		"BT_REPO_ROOT=" + m.RepoRoot,
+		"BT_WORKTREE_PATH=" + ctx.WorktreePath,
+		"BT_BRANCH=" + ctx.Branch,
+		"BT_BASE_BRANCH=" + ctx.BaseBranch,
+		"BT_DEFAULT_BRANCH=" + m.GetDefaultBranch(),
+		"BT_SHARED_DIR=" + m.GetSharedDir(),
+	}
+}
+
 // ExecutePostCreateCommands executes all command-type post-create actions in a worktree
 // Output is written to the provided writer in real-time. If writer is nil, output is discarded.
 // Returns the results for each command.
 func (m *Manager) ExecutePostCreateCommands(worktreePath string, writer io.Writer) []CommandResult {
-	var results []CommandResult
-	headerPrinted := false
+	return m.executePostCreateCommands(newPostCreateContext(worktreePath), writer)
+}
 
+// executePostCreateCommands executes command-type post-create actions with the BT_* environment of ctx
+func (m *Manager) executePostCreateCommands(ctx PostCreateContext, writer io.Writer) []CommandResult {
+	var commands []config.PostCreateAction
 	for _, action := range m.Config.PostCreate {
-		if action.Type != "command" {
-			continue
-		}
-
-		result := CommandResult{
-			Command: action.Source,
-		}
-
-		// Print section header before the first command
-		if writer != nil && !headerPrinted {
-			fmt.Fprintln(writer, "\nPost-create commands:")
-			headerPrinted = true
-		}
-
-		// Print command before execution
-		if writer != nil {
-			fmt.Fprintf(writer, "  $ %s\n", action.Source)
-		}
-
-		cmd := exec.Command("sh", "-c", action.Source)
-		cmd.Dir = worktreePath
-
-		// Connect stdout and stderr to writer for real-time output
-		if writer != nil {
-			cmd.Stdout = &prefixWriter{writer: writer, prefix: "  > ", atNewLine: true}
-			cmd.Stderr = &prefixWriter{writer: writer, prefix: "  > ", atNewLine: true}
-		}
-
-		err := cmd.Run()
-		if err != nil {
-			result.Success = false
-			result.Error = err.Error()
-			if writer != nil {
-				fmt.Fprintf(writer, "  ✗ %s\n", err.Error())
-			}
-		} else {
-			result.Success = true
-			if writer != nil {
-				fmt.Fprintln(writer, "  ✓")
-			}
+		if action.Type == "command" {
+			commands = append(commands, action)
 		}
+	}
+	if len(commands) == 0 {
+		return nil
+	}
 
-		results = append(results, result)
+	if writer != nil {
+		fmt.Fprintln(writer, "\nPost-create commands:")
 	}
 
-	return results
+	env := append(os.Environ(), m.PostCreateEnv(ctx)...)
+	return runCommands(commands, ctx.WorktreePath, env, writer)
 }
 
 // prefixWriter wraps an io.Writer and adds a prefix to each line
@@ -715,6 +756,31 @@ type PostCreateResult struct {
 // and executes any configured commands. Output is written to the provided writer in real-time.
 // If writer is nil, output is discarded.
 func (m *Manager) ApplyPostCreateConfig(worktreePath string, writer io.Writer) (*PostCreateResult, error) {
+	return m.applyPostCreateConfig(newPostCreateContext(worktreePath), writer)
+}
+
+// applyPostCreateConfig applies post-create configuration to the worktree described by ctx
+func (m *Manager) applyPostCreateConfig(ctx PostCreateContext, writer io.Writer) (*PostCreateResult, error) {
+	result, err := m.applyPostCreateFiles(ctx, writer)
+	if err != nil {
+		return nil, err
+	}
+
+	// Execute commands after file operations
+	result.CommandResults = m.executePostCreateCommands(ctx, writer)
+
+	return result, nil
+}
+
+// ApplyPostCreateFiles applies only the file/directory actions of the post-create configuration
+// to a worktree, without running commands. Existing files are left alone.
+func (m *Manager) ApplyPostCreateFiles(worktreePath string, writer io.Writer) (*PostCreateResult, error) {
+	return m.applyPostCreateFiles(newPostCreateContext(worktreePath), writer)
+}
+
+// applyPostCreateFiles applies the file/directory actions to the worktree described by ctx
+func (m *Manager) applyPostCreateFiles(ctx PostCreateContext, writer io.Writer) (*PostCreateResult, error) {
+	worktreePath := ctx.WorktreePath
 	result := &PostCreateResult{}
 	fileHeaderPrinted := false
 
@@ -786,6 +852,15 @@ func (m *Manager) ApplyPostCreateConfig(worktreePath string, writer io.Writer) (
 				fmt.Fprintf(writer, "  %s (%s)\n", action.Source, action.Type)
 			}
 
+		case "template":
+			if err := m.renderWorktreeTemplate(action, sourcePath, worktreePath, ctx.Branch); err != nil {
+				return nil, fmt.Errorf("failed to render template %s to %s: %w", sourcePath, targetPath, err)
+			}
+			fileResult.Applied = true
+			if writer != nil {
+				fmt.Fprintf(writer, "  %s (%s)\n", action.Source, action.Type)
+			}
+
 		default:
 			return nil, fmt.Errorf("unknown post-create type: %s", action.Type)
 		}
@@ -793,9 +868,6 @@ func (m *Manager) ApplyPostCreateConfig(worktreePath string, writer io.Writer) (
 		result.FileActions = append(result.FileActions, fileResult)
 	}
 
-	// Execute commands after file operations
-	result.CommandResults = m.ExecutePostCreateCommands(worktreePath, writer)
-
 	return result, nil
 }
 
@@ -805,7 +877,7 @@ func (m *Manager) getMainWorktreePath() (string, error) {
 	if defaultBranch == "" {
 		defaultBranch = "main"
 	}
-	return filepath.Join(m.RepoRoot, defaultBranch), nil
+	return m.WorktreePath(defaultBranch)
 }
 
 // GetDefaultBranch returns the default branch name
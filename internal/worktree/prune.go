@@ -0,0 +1,151 @@
+package worktree
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/amaya382/baretree/internal/git"
+)
+
+// Reasons a worktree or branch is a prune candidate
+const (
+	PruneReasonMerged = "merged"        // branch is merged into the default branch
+	PruneReasonGone   = "upstream-gone" // branch's upstream was deleted on the remote
+	PruneReasonStale  = "stale"         // no commits or file changes for the configured number of days
+	PruneReasonBroken = "broken"        // worktree directory was moved or deleted
+)
+
+// PruneOptions configures prune candidate detection
+type PruneOptions struct {
+	// StaleDays marks worktrees without commits or file changes for this many days as stale (0 disables)
+	StaleDays int
+	// Now is the reference time for staleness (defaults to time.Now())
+	Now time.Time
+}
+
+// PruneCandidate is a worktree, or a branch without a worktree, that can be pruned
+type PruneCandidate struct {
+	Branch       string
+	Path         string // worktree path (empty for branches without a worktree)
+	Reasons      []string
+	Dirty        bool      // worktree has uncommitted changes
+	LastActivity time.Time // latest of last commit, worktree creation and file modification
+}
+
+// FindPruneCandidates returns the worktrees and orphan branches (branches without a worktree)
+// that are merged into the default branch, lost their upstream, or are stale.
+// The default branch and detached worktrees are never candidates.
+func (m *Manager) FindPruneCandidates(worktrees []git.Worktree, orphanBranches []string, opts PruneOptions) ([]PruneCandidate, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	defaultBranch := m.GetDefaultBranch()
+
+	mergedList, err := m.Executor.ListMergedBranches(defaultBranch)
+	if err != nil {
+		return nil, err
+	}
+	merged := toSet(mergedList)
+
+	goneList, err := m.Executor.ListGoneBranches()
+	if err != nil {
+		return nil, err
+	}
+	gone := toSet(goneList)
+
+	defaultTip, _ := m.Executor.Execute("rev-parse", "refs/heads/"+defaultBranch)
+
+	var candidates []PruneCandidate
+	for _, wt := range worktrees {
+		if wt.IsBare || wt.Branch == "" || wt.Branch == defaultBranch {
+			continue
+		}
+
+		md, _ := m.LoadMetadata(wt.Path)
+		c := PruneCandidate{Branch: wt.Branch, Path: wt.Path}
+		c.Dirty, c.LastActivity = worktreeActivity(wt.Path)
+		if md != nil && md.CreatedAt.After(c.LastActivity) {
+			c.LastActivity = md.CreatedAt
+		}
+
+		baseCommit := defaultTip
+		if md != nil && md.BaseCommit != "" {
+			baseCommit = md.BaseCommit
+		}
+		c.Reasons = m.pruneReasons(wt.Branch, wt.Head, baseCommit, merged, gone, c.LastActivity, opts)
+		if len(c.Reasons) > 0 {
+			candidates = append(candidates, c)
+		}
+	}
+
+	for _, branch := range orphanBranches {
+		if branch == defaultBranch {
+			continue
+		}
+
+		tip, err := m.Executor.Execute("rev-parse", "refs/heads/"+branch)
+		if err != nil {
+			continue
+		}
+		c := PruneCandidate{Branch: branch}
+		c.LastActivity, _ = m.Executor.LastCommitTime("refs/heads/" + branch)
+		c.Reasons = m.pruneReasons(branch, tip, defaultTip, merged, gone, c.LastActivity, opts)
+		if len(c.Reasons) > 0 {
+			candidates = append(candidates, c)
+		}
+	}
+
+	return candidates, nil
+}
+
+// pruneReasons returns why a branch can be pruned.
+// A branch still pointing at the commit it was created from has no work of its own,
+// so it is not considered merged even though its tip is reachable from the default branch.
+func (m *Manager) pruneReasons(branch, tip, baseCommit string, merged, gone map[string]bool, lastActivity time.Time, opts PruneOptions) []string {
+	var reasons []string
+	if merged[branch] && tip != baseCommit {
+		reasons = append(reasons, PruneReasonMerged)
+	}
+	if gone[branch] {
+		reasons = append(reasons, PruneReasonGone)
+	}
+	if opts.StaleDays > 0 && !lastActivity.IsZero() &&
+		opts.Now.Sub(lastActivity) > time.Duration(opts.StaleDays)*24*time.Hour {
+		reasons = append(reasons, PruneReasonStale)
+	}
+	return reasons
+}
+
+// worktreeActivity reports whether a worktree has uncommitted changes and the time of its
+// latest activity: the last commit or the newest modification of a changed or untracked file
+func worktreeActivity(worktreePath string) (dirty bool, last time.Time) {
+	executor := git.NewExecutor(worktreePath)
+	last, _ = executor.LastCommitTime("HEAD")
+
+	var changed []string
+	if output, err := executor.Execute("diff", "--name-only", "HEAD"); err == nil && output != "" {
+		changed = append(changed, strings.Split(output, "\n")...)
+	}
+	if output, err := executor.Execute("ls-files", "--others", "--exclude-standard"); err == nil && output != "" {
+		changed = append(changed, strings.Split(output, "\n")...)
+	}
+
+	for _, file := range changed {
+		dirty = true
+		if info, err := os.Stat(filepath.Join(worktreePath, file)); err == nil && info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+	}
+	return dirty, last
+}
+
+// toSet converts a slice of strings to a set
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		set[item] = true
+	}
+	return set
+}
@@ -0,0 +1,148 @@
+package worktree
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/amaya382/baretree/internal/config"
+)
+
+// runTestGit runs a git command in dir and fails the test on error
+func runTestGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+// createPruneTestRepo creates a baretree repository with a "main" worktree and returns its manager
+func createPruneTestRepo(t *testing.T) *Manager {
+	t.Helper()
+	src := filepath.Join(t.TempDir(), "src")
+	runTestGit(t, "", "init", "-b", "main", src)
+	runTestGit(t, src, "commit", "--allow-empty", "-m", "initial")
+
+	repoRoot := t.TempDir()
+	bareDir := filepath.Join(repoRoot, ".git")
+	runTestGit(t, repoRoot, "clone", "--bare", src, ".git")
+	runTestGit(t, bareDir, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*")
+	runTestGit(t, bareDir, "worktree", "add", filepath.Join(repoRoot, "main"), "main")
+
+	return NewManager(repoRoot, bareDir, config.DefaultConfig())
+}
+
+func TestFindPruneCandidates(t *testing.T) {
+	mgr := createPruneTestRepo(t)
+	mainDir := filepath.Join(mgr.RepoRoot, "main")
+
+	// merged: has its own commit, which is then merged into main
+	if _, _, err := mgr.AddWithOptions("merged", AddOptions{NewBranch: true}, nil); err != nil {
+		t.Fatal(err)
+	}
+	runTestGit(t, filepath.Join(mgr.RepoRoot, "merged"), "commit", "--allow-empty", "-m", "work")
+	runTestGit(t, mainDir, "merge", "--ff-only", "merged")
+
+	// fresh: created from main without commits of its own
+	if _, _, err := mgr.AddWithOptions("fresh", AddOptions{NewBranch: true, BaseBranch: "main~1"}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// gone: upstream configured but missing on the remote
+	if _, _, err := mgr.AddWithOptions("gone", AddOptions{NewBranch: true}, nil); err != nil {
+		t.Fatal(err)
+	}
+	runTestGit(t, filepath.Join(mgr.RepoRoot, "gone"), "commit", "--allow-empty", "-m", "unmerged work")
+	runTestGit(t, mgr.BareDir, "config", "branch.gone.remote", "origin")
+	runTestGit(t, mgr.BareDir, "config", "branch.gone.merge", "refs/heads/gone")
+
+	// orphan: merged branch without a worktree
+	runTestGit(t, mgr.BareDir, "branch", "orphan", "main~1")
+
+	worktrees, err := mgr.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reasons := func(candidates []PruneCandidate) map[string][]string {
+		got := make(map[string][]string)
+		for _, c := range candidates {
+			got[c.Branch] = c.Reasons
+		}
+		return got
+	}
+
+	candidates, err := mgr.FindPruneCandidates(worktrees, []string{"orphan"}, PruneOptions{})
+	if err != nil {
+		t.Fatalf("FindPruneCandidates failed: %v", err)
+	}
+	want := map[string][]string{
+		"merged": {PruneReasonMerged},
+		"gone":   {PruneReasonGone},
+		"orphan": {PruneReasonMerged},
+	}
+	if got := reasons(candidates); !reflect.DeepEqual(got, want) {
+		t.Errorf("candidates = %v, want %v", got, want)
+	}
+
+	// Everything but the default branch becomes stale far enough in the future
+	candidates, err = mgr.FindPruneCandidates(worktrees, nil, PruneOptions{
+		StaleDays: 30,
+		Now:       time.Now().Add(31 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reasons(candidates)
+	if !reflect.DeepEqual(got["fresh"], []string{PruneReasonStale}) {
+		t.Errorf("expected fresh to be stale, got %v", got["fresh"])
+	}
+	if _, ok := got["main"]; ok {
+		t.Error("default branch should never be a candidate")
+	}
+
+	// Recent file changes keep a worktree active
+	if err := os.WriteFile(filepath.Join(mgr.RepoRoot, "fresh", "new.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	future := time.Now().Add(31 * 24 * time.Hour)
+	if err := os.Chtimes(filepath.Join(mgr.RepoRoot, "fresh", "new.txt"), future, future); err != nil {
+		t.Fatal(err)
+	}
+	candidates, err = mgr.FindPruneCandidates(worktrees, nil, PruneOptions{StaleDays: 30, Now: future})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range candidates {
+		if c.Branch == "fresh" {
+			t.Errorf("worktree with recent changes should not be stale: %+v", c)
+		}
+	}
+}
+
+func TestMetadataRoundTrip(t *testing.T) {
+	mgr := createPruneTestRepo(t)
+	mainDir := filepath.Join(mgr.RepoRoot, "main")
+
+	md, err := mgr.LoadMetadata(mainDir)
+	if err != nil || md != nil {
+		t.Fatalf("expected no metadata for a worktree not created by bt, got %+v, %v", md, err)
+	}
+
+	path, _, err := mgr.AddWithOptions("feature", AddOptions{NewBranch: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	md, err = mgr.LoadMetadata(path)
+	if err != nil || md == nil {
+		t.Fatalf("expected metadata, got %+v, %v", md, err)
+	}
+	if md.CreatedAt.IsZero() || md.BaseCommit == "" || md.BaseBranch != "main" {
+		t.Errorf("unexpected metadata: %+v", md)
+	}
+}
@@ -0,0 +1,143 @@
+package worktree
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/amaya382/baretree/internal/git"
+)
+
+// RequestKind is the kind of review request a worktree is created from
+type RequestKind string
+
+const (
+	// PullRequest is a GitHub-style pull request (bt add --pr)
+	PullRequest RequestKind = "pr"
+	// MergeRequest is a GitLab-style merge request (bt add --mr)
+	MergeRequest RequestKind = "mr"
+)
+
+// Git config keys in the branch section that remember which request a branch was fetched from
+const (
+	requestRemoteKey = "baretreeRequestRemote"
+	requestRefKey    = "baretreeRequestRef"
+)
+
+// Request is a pull or merge request fetched into a local branch
+type Request struct {
+	Remote string // Remote the request is fetched from (e.g., "origin")
+	Ref    string // Ref of the request on the remote (e.g., "refs/pull/123/head")
+	Branch string // Local branch (e.g., "pr/123")
+}
+
+// RequestBranch returns the local branch name for a request (e.g., "pr/123")
+func RequestBranch(kind RequestKind, number int) string {
+	return fmt.Sprintf("%s/%d", kind, number)
+}
+
+// RequestRef expands a ref pattern (e.g., "refs/pull/{number}/head") for a request number
+func RequestRef(pattern string, number int) string {
+	return strings.ReplaceAll(pattern, "{number}", strconv.Itoa(number))
+}
+
+// LoadRequest returns the request a local branch was fetched from,
+// or nil if the branch was not created by FetchRequest
+func (m *Manager) LoadRequest(branch string) *Request {
+	remote, err := m.Executor.Execute("config", "--get", "branch."+branch+"."+requestRemoteKey)
+	if err != nil || remote == "" {
+		return nil
+	}
+	ref, err := m.Executor.Execute("config", "--get", "branch."+branch+"."+requestRefKey)
+	if err != nil || ref == "" {
+		return nil
+	}
+	return &Request{Remote: remote, Ref: ref, Branch: branch}
+}
+
+// RequestUpdate describes the result of FetchRequest
+type RequestUpdate struct {
+	Created      bool   // The local branch was created
+	OldCommit    string // Commit of the branch before the update (empty if created)
+	NewCommit    string // Commit of the request on the remote
+	WorktreePath string // Worktree the branch is checked out in (empty if none)
+}
+
+// UpToDate reports whether the branch already pointed to the fetched commit
+func (u *RequestUpdate) UpToDate() bool {
+	return !u.Created && u.OldCommit == u.NewCommit
+}
+
+// FetchRequest fetches a request from its remote into its local branch. A missing branch is
+// created and remembers the request, so later calls refresh it; an existing branch is
+// fast-forwarded, in its worktree if it has one. Branches not created from the same request
+// and branches that cannot be fast-forwarded (e.g., after a force push) are left untouched.
+func (m *Manager) FetchRequest(req Request) (*RequestUpdate, error) {
+	exists := m.Executor.IsCommitHash("refs/heads/" + req.Branch)
+	if exists {
+		if stored := m.LoadRequest(req.Branch); stored == nil || *stored != req {
+			return nil, fmt.Errorf("branch '%s' already exists and was not fetched from %s %s", req.Branch, req.Remote, req.Ref)
+		}
+	}
+
+	if _, err := m.Executor.Execute("fetch", req.Remote, req.Ref); err != nil {
+		return nil, fmt.Errorf("failed to fetch %s from %s: %w", req.Ref, req.Remote, err)
+	}
+	commit, err := m.Executor.Execute("rev-parse", "FETCH_HEAD^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve fetched commit: %w", err)
+	}
+
+	update := &RequestUpdate{NewCommit: commit}
+	if !exists {
+		if _, err := m.Executor.Execute("branch", req.Branch, commit); err != nil {
+			if refErr := parseRefConflictError(err, req.Branch); refErr != nil {
+				return nil, refErr
+			}
+			return nil, fmt.Errorf("failed to create branch '%s': %w", req.Branch, err)
+		}
+		if _, err := m.Executor.Execute("config", "branch."+req.Branch+"."+requestRemoteKey, req.Remote); err != nil {
+			return nil, fmt.Errorf("failed to record request: %w", err)
+		}
+		if _, err := m.Executor.Execute("config", "branch."+req.Branch+"."+requestRefKey, req.Ref); err != nil {
+			return nil, fmt.Errorf("failed to record request: %w", err)
+		}
+		update.Created = true
+		return update, nil
+	}
+
+	update.OldCommit, err = m.Executor.Execute("rev-parse", "refs/heads/"+req.Branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve '%s': %w", req.Branch, err)
+	}
+
+	worktrees, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, wt := range worktrees {
+		if wt.Branch == req.Branch {
+			update.WorktreePath = wt.Path
+			break
+		}
+	}
+
+	if update.UpToDate() {
+		return update, nil
+	}
+	if _, err := m.Executor.Execute("merge-base", "--is-ancestor", update.OldCommit, commit); err != nil {
+		return nil, fmt.Errorf("cannot fast-forward '%s': it has local commits or the request was force-pushed", req.Branch)
+	}
+
+	if update.WorktreePath != "" {
+		// Updates the working tree too, and refuses to overwrite local changes
+		if _, err := git.NewExecutor(update.WorktreePath).Execute("merge", "--ff-only", commit); err != nil {
+			return nil, fmt.Errorf("failed to fast-forward '%s': %w", req.Branch, err)
+		}
+		return update, nil
+	}
+	if _, err := m.Executor.Execute("update-ref", "refs/heads/"+req.Branch, commit, update.OldCommit); err != nil {
+		return nil, fmt.Errorf("failed to update '%s': %w", req.Branch, err)
+	}
+	return update, nil
+}
@@ -0,0 +1,15 @@
+package worktree
+
+import "testing"
+
+func TestRequestBranchAndRef(t *testing.T) {
+	if got := RequestBranch(PullRequest, 123); got != "pr/123" {
+		t.Errorf("RequestBranch(pr) = %q, want pr/123", got)
+	}
+	if got := RequestBranch(MergeRequest, 42); got != "mr/42" {
+		t.Errorf("RequestBranch(mr) = %q, want mr/42", got)
+	}
+	if got := RequestRef("refs/pull/{number}/head", 123); got != "refs/pull/123/head" {
+		t.Errorf("RequestRef() = %q, want refs/pull/123/head", got)
+	}
+}
@@ -2,9 +2,11 @@ package worktree
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/amaya382/baretree/internal/fuzzy"
 	"github.com/amaya382/baretree/internal/git"
 )
 
@@ -26,46 +28,52 @@ func (m *Manager) Resolve(name string) (string, error) {
 
 // ResolveFromCwd resolves a worktree name to its path, with cwd used for empty name resolution
 func (m *Manager) ResolveFromCwd(name string, cwd string) (string, error) {
-	// Get all worktrees
 	worktrees, err := m.List()
 	if err != nil {
 		return "", err
 	}
+	return resolveWorktree(worktrees, m.RepoRoot, name, cwd, false, nil)
+}
+
+// ResolveFuzzy resolves a worktree name like ResolveFromCwd, but also accepts names that only
+// partially match a branch or directory (e.g. "auth" for feature/auth-refresh, or the
+// characters in order such as "far"). If several worktrees match, the one with the highest
+// score wins; score may be nil, and ties or zero scores still give an AmbiguousMatchError.
+func (m *Manager) ResolveFuzzy(name string, cwd string, score func(path string) float64) (string, error) {
+	worktrees, err := m.List()
+	if err != nil {
+		return "", err
+	}
+	return resolveWorktree(worktrees, m.RepoRoot, name, cwd, true, score)
+}
 
+// resolveWorktree resolves name to a worktree path.
+// Resolution order:
+//  1. Empty name: the worktree containing cwd, or the default worktree
+//  2. @: the default worktree
+//  3. Exact branch name
+//  4. Path relative to the repository root
+//  5. Directory name
+//  6. (fuzzy only) Substring of the branch or relative path
+//  7. (fuzzy only) Characters of name in order in the branch or relative path
+func resolveWorktree(worktrees []git.Worktree, repoRoot, name, cwd string, fuzzyMatch bool, score func(path string) float64) (string, error) {
 	// Special case: empty string means current worktree root
 	if name == "" {
-		if cwd == "" {
-			// Fallback to default worktree if no cwd provided
+		if cwd != "" {
+			// Find which worktree contains cwd
 			for _, wt := range worktrees {
-				if wt.IsMain {
+				if strings.HasPrefix(cwd, wt.Path+string(filepath.Separator)) || cwd == wt.Path {
 					return wt.Path, nil
 				}
 			}
-			return "", fmt.Errorf("default worktree not found")
-		}
-		// Find which worktree contains cwd
-		for _, wt := range worktrees {
-			if strings.HasPrefix(cwd, wt.Path+string(filepath.Separator)) || cwd == wt.Path {
-				return wt.Path, nil
-			}
-		}
-		// Not in a worktree (e.g., at repo root) - fall back to default worktree
-		for _, wt := range worktrees {
-			if wt.IsMain {
-				return wt.Path, nil
-			}
 		}
-		return "", fmt.Errorf("default worktree not found")
+		// No cwd, or not in a worktree (e.g., at repo root) - fall back to default worktree
+		return defaultWorktreePath(worktrees)
 	}
 
 	// Special case: @ means default worktree
 	if name == "@" {
-		for _, wt := range worktrees {
-			if wt.IsMain {
-				return wt.Path, nil
-			}
-		}
-		return "", fmt.Errorf("default worktree not found")
+		return defaultWorktreePath(worktrees)
 	}
 
 	// Try exact branch name match
@@ -76,34 +84,120 @@ func (m *Manager) ResolveFromCwd(name string, cwd string) (string, error) {
 	}
 
 	// Try path-based match (relative to repo root)
-	candidatePath := filepath.Join(m.RepoRoot, name)
+	candidatePath := filepath.Join(repoRoot, name)
 	for _, wt := range worktrees {
 		if wt.Path == candidatePath {
 			return wt.Path, nil
 		}
 	}
 
-	// Try directory name match - collect all matches
-	var matches []git.Worktree
-	for _, wt := range worktrees {
-		if filepath.Base(wt.Path) == name {
+	matchers := []func(wt git.Worktree) bool{
+		// Directory name
+		func(wt git.Worktree) bool { return filepath.Base(wt.Path) == name },
+	}
+	if fuzzyMatch {
+		query := strings.ToLower(name)
+		relPath := func(wt git.Worktree) string {
+			rel, err := filepath.Rel(repoRoot, wt.Path)
+			if err != nil {
+				return wt.Path
+			}
+			return filepath.ToSlash(rel)
+		}
+		matchers = append(matchers,
+			func(wt git.Worktree) bool {
+				return strings.Contains(strings.ToLower(wt.Branch), query) ||
+					strings.Contains(strings.ToLower(relPath(wt)), query)
+			},
+			func(wt git.Worktree) bool {
+				return fuzzy.Match(wt.Branch, name) || fuzzy.Match(relPath(wt), name)
+			},
+		)
+	}
+
+	// Use the first kind of match that finds anything; pick by score if there are several
+	for _, matcher := range matchers {
+		var matches []git.Worktree
+		for _, wt := range worktrees {
+			if wt.IsBare || !matcher(wt) {
+				continue
+			}
 			matches = append(matches, wt)
 		}
+
+		if len(matches) == 1 {
+			return matches[0].Path, nil
+		}
+		if len(matches) > 1 {
+			if score != nil {
+				scores := make([]float64, len(matches))
+				for i, wt := range matches {
+					scores[i] = score(wt.Path)
+				}
+				if best := fuzzy.Best(scores); best >= 0 {
+					return matches[best].Path, nil
+				}
+			}
+			return "", &AmbiguousMatchError{
+				Name:     name,
+				Matches:  matches,
+				RepoRoot: repoRoot,
+			}
+		}
+	}
+
+	return "", fmt.Errorf("worktree not found: %s", name)
+}
+
+// CorrespondingPath returns the directory in the worktree at targetPath that is at the same
+// relative path as cwd in the worktree containing it (e.g. services/api in another worktree),
+// or its nearest existing parent. It returns targetPath if cwd is not in another worktree.
+func (m *Manager) CorrespondingPath(targetPath, cwd string) (string, error) {
+	worktrees, err := m.List()
+	if err != nil {
+		return "", err
+	}
+	return correspondingPath(worktrees, targetPath, cwd), nil
+}
+
+// correspondingPath finds the subdirectory of targetPath matching cwd (see CorrespondingPath)
+func correspondingPath(worktrees []git.Worktree, targetPath, cwd string) string {
+	// Find the innermost worktree containing cwd
+	current := ""
+	for _, wt := range worktrees {
+		if wt.IsBare || len(wt.Path) <= len(current) {
+			continue
+		}
+		if strings.HasPrefix(cwd, wt.Path+string(filepath.Separator)) || cwd == wt.Path {
+			current = wt.Path
+		}
+	}
+	if current == "" || current == targetPath {
+		return targetPath
 	}
 
-	if len(matches) == 1 {
-		return matches[0].Path, nil
+	rel, err := filepath.Rel(current, cwd)
+	if err != nil || rel == "." {
+		return targetPath
 	}
 
-	if len(matches) > 1 {
-		return "", &AmbiguousMatchError{
-			Name:     name,
-			Matches:  matches,
-			RepoRoot: m.RepoRoot,
+	// Fall back to the nearest parent that exists in the target worktree
+	for path := filepath.Join(targetPath, rel); path != targetPath; path = filepath.Dir(path) {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			return path
 		}
 	}
+	return targetPath
+}
 
-	return "", fmt.Errorf("worktree not found: %s", name)
+// defaultWorktreePath returns the path of the default (main) worktree
+func defaultWorktreePath(worktrees []git.Worktree) (string, error) {
+	for _, wt := range worktrees {
+		if wt.IsMain {
+			return wt.Path, nil
+		}
+	}
+	return "", fmt.Errorf("default worktree not found")
 }
 
 // GetBranchName returns the branch name from a worktree path
@@ -1,6 +1,8 @@
 package worktree
 
 import (
+	"errors"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -177,3 +179,106 @@ func TestManagerConfig(t *testing.T) {
 		t.Errorf("expected 2 post-create configs, got %d", len(mgr.Config.PostCreate))
 	}
 }
+
+func TestResolveWorktreeFuzzy(t *testing.T) {
+	repoRoot := "/home/user/project"
+	worktrees := []git.Worktree{
+		{Path: repoRoot + "/.git", IsBare: true},
+		{Path: repoRoot + "/main", Branch: "main", IsMain: true},
+		{Path: repoRoot + "/feature/auth-refresh", Branch: "feature/auth-refresh"},
+		{Path: repoRoot + "/feature/auth-login", Branch: "feature/auth-login"},
+		{Path: repoRoot + "/bugfix/typo", Branch: "bugfix/typo"},
+	}
+	scores := map[string]float64{
+		repoRoot + "/feature/auth-login": 3,
+		repoRoot + "/bugfix/typo":        1,
+	}
+	score := func(path string) float64 { return scores[path] }
+
+	tests := []struct {
+		name      string
+		query     string
+		fuzzy     bool
+		score     func(string) float64
+		want      string
+		ambiguous bool
+		wantErr   bool
+	}{
+		{name: "exact branch still wins", query: "main", fuzzy: true, score: score, want: repoRoot + "/main"},
+		{name: "unique substring", query: "typo", fuzzy: true, want: repoRoot + "/bugfix/typo"},
+		{name: "unique subsequence", query: "arfr", fuzzy: true, want: repoRoot + "/feature/auth-refresh"},
+		{name: "ambiguous substring picks highest score", query: "auth", fuzzy: true, score: score, want: repoRoot + "/feature/auth-login"},
+		{name: "ambiguous substring without scores", query: "auth", fuzzy: true, ambiguous: true},
+		{name: "strict resolution ignores partial matches", query: "typ", fuzzy: false, wantErr: true},
+		{name: "no match", query: "zzz", fuzzy: true, score: score, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveWorktree(worktrees, repoRoot, tt.query, "", tt.fuzzy, tt.score)
+			if tt.ambiguous {
+				var ambiguousErr *AmbiguousMatchError
+				if !errors.As(err, &ambiguousErr) || len(ambiguousErr.Matches) != 2 {
+					t.Fatalf("expected AmbiguousMatchError with 2 matches, got %v", err)
+				}
+				return
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolveWorktree(%q) error = %v, wantErr %v", tt.query, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolveWorktree(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCorrespondingPath(t *testing.T) {
+	repoRoot := t.TempDir()
+	main := filepath.Join(repoRoot, "main")
+	feature := filepath.Join(repoRoot, "feature", "auth")
+	worktrees := []git.Worktree{
+		{Path: filepath.Join(repoRoot, ".git"), IsBare: true},
+		{Path: main, Branch: "main", IsMain: true},
+		{Path: feature, Branch: "feature/auth"},
+	}
+	for _, dir := range []string{
+		filepath.Join(main, "services", "api", "handlers"),
+		filepath.Join(feature, "services", "web"),
+	} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		target string
+		cwd    string
+		want   string
+	}{
+		{name: "missing subdirectory falls back to parent", target: main, cwd: filepath.Join(feature, "services", "web"), want: filepath.Join(main, "services")},
+		{name: "nearest existing parent", target: feature, cwd: filepath.Join(main, "services", "api", "handlers"), want: filepath.Join(feature, "services")},
+		{name: "worktree root", target: feature, cwd: main, want: feature},
+		{name: "target is current worktree", target: main, cwd: filepath.Join(main, "services", "api"), want: main},
+		{name: "outside worktrees", target: main, cwd: repoRoot, want: main},
+		{name: "nested worktree directory", target: main, cwd: filepath.Join(repoRoot, "feature"), want: main},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := correspondingPath(worktrees, tt.target, tt.cwd); got != tt.want {
+				t.Errorf("correspondingPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// Full path when it exists in the target worktree
+	if err := os.MkdirAll(filepath.Join(main, "services", "web"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	want := filepath.Join(main, "services", "web")
+	if got := correspondingPath(worktrees, main, filepath.Join(feature, "services", "web")); got != want {
+		t.Errorf("correspondingPath() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,313 @@
+package worktree
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/amaya382/baretree/internal/git"
+)
+
+// reportItemLimit is the number of files, commits or stashes listed per category in a report
+const reportItemLimit = 5
+
+// RemovalCheck is the work that would be lost by removing a worktree
+type RemovalCheck struct {
+	WorktreePath string
+	Branch       string   // Empty for a detached HEAD
+	Changed      []string // Tracked files with staged or unstaged changes
+	Untracked    []string // Untracked files that are not ignored
+	Stashes      []string // Stash entries made on the branch (git stash list format)
+	// UnpushedCommits are commits missing from every remote (git log --oneline format).
+	// Without remotes, they are the commits not on any other local branch.
+	UnpushedCommits []string
+	NoRemotes       bool
+}
+
+// HasRisks reports whether removing the worktree would lose any work
+func (c *RemovalCheck) HasRisks() bool {
+	return len(c.Changed) > 0 || len(c.Untracked) > 0 || len(c.Stashes) > 0 || len(c.UnpushedCommits) > 0
+}
+
+// Name returns the branch of the worktree, or its path for a detached HEAD
+func (c *RemovalCheck) Name() string {
+	if c.Branch != "" {
+		return c.Branch
+	}
+	return c.WorktreePath
+}
+
+// WriteReport writes what would be lost, one category per section, each line indented by indent
+func (c *RemovalCheck) WriteReport(w io.Writer, indent string) {
+	writeReportSection(w, indent, "uncommitted change(s)", c.Changed)
+	writeReportSection(w, indent, "untracked file(s)", c.Untracked)
+	if c.NoRemotes {
+		writeReportSection(w, indent, "commit(s) not on any other branch (no remotes configured)", c.UnpushedCommits)
+	} else {
+		writeReportSection(w, indent, "commit(s) not pushed to any remote", c.UnpushedCommits)
+	}
+	writeReportSection(w, indent, "stash(es) made on this branch", c.Stashes)
+}
+
+// BranchRemovalCheck is a local branch without a worktree whose commits would be lost
+// by removing the repository
+type BranchRemovalCheck struct {
+	Branch          string
+	UnpushedCommits []string
+}
+
+// RepositoryRemovalCheck is the work that would be lost by removing a whole repository
+type RepositoryRemovalCheck struct {
+	Worktrees []RemovalCheck       // Worktrees with risks
+	Branches  []BranchRemovalCheck // Branches without a worktree that have unpushed commits (see RemovalCheck)
+	Stashes   []string             // All stash entries
+	NoRemotes bool                 // The repository has no remotes, so nothing exists elsewhere
+}
+
+// HasRisks reports whether removing the repository would lose any work. Missing remotes alone
+// are not a risk, since a repository without remotes is often meant to stay local; callers warn
+// about NoRemotes separately.
+func (c *RepositoryRemovalCheck) HasRisks() bool {
+	return len(c.Worktrees) > 0 || len(c.Branches) > 0 || len(c.Stashes) > 0
+}
+
+// WriteReport writes what would be lost, grouped by worktree and branch
+func (c *RepositoryRemovalCheck) WriteReport(w io.Writer) {
+	if c.NoRemotes {
+		fmt.Fprintln(w, "  No remotes configured: the entire history exists only in this repository")
+	}
+	for i := range c.Worktrees {
+		wt := &c.Worktrees[i]
+		fmt.Fprintf(w, "  Worktree %s (%s):\n", wt.Name(), wt.WorktreePath)
+		wt.WriteReport(w, "    ")
+	}
+	for _, b := range c.Branches {
+		fmt.Fprintf(w, "  Branch %s:\n", b.Branch)
+		if c.NoRemotes {
+			writeReportSection(w, "    ", "commit(s) not on any other branch", b.UnpushedCommits)
+		} else {
+			writeReportSection(w, "    ", "commit(s) not pushed to any remote", b.UnpushedCommits)
+		}
+	}
+	if len(c.Stashes) > 0 {
+		fmt.Fprintln(w, "  Stashes:")
+		writeReportSection(w, "    ", "stash(es)", c.Stashes)
+	}
+}
+
+// writeReportSection writes a count and up to reportItemLimit items of one category
+func writeReportSection(w io.Writer, indent, label string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%s%d %s:\n", indent, len(items), label)
+	for i, item := range items {
+		if i == reportItemLimit {
+			fmt.Fprintf(w, "%s  ... and %d more\n", indent, len(items)-reportItemLimit)
+			break
+		}
+		fmt.Fprintf(w, "%s  %s\n", indent, item)
+	}
+}
+
+// CheckRemoval inspects a worktree for work that removing it would lose: uncommitted changes,
+// untracked files, commits missing from every remote and stashes made on its branch
+func (m *Manager) CheckRemoval(worktreePath string) (*RemovalCheck, error) {
+	branch, _ := m.GetBranchName(worktreePath)
+	if branch == "HEAD" {
+		// Detached HEAD
+		branch = ""
+	}
+
+	check, err := m.checkWorktree(worktreePath, branch, m.Executor.HasRemotes())
+	if err != nil {
+		return nil, err
+	}
+
+	if branch != "" {
+		stashes, err := m.listStashes()
+		if err != nil {
+			return nil, err
+		}
+		for _, stash := range stashes {
+			if strings.Contains(stash, ": WIP on "+branch+": ") || strings.Contains(stash, ": On "+branch+": ") {
+				check.Stashes = append(check.Stashes, stash)
+			}
+		}
+	}
+	return check, nil
+}
+
+// CheckRepositoryRemoval inspects all worktrees, branches and stashes of the repository for work
+// that removing it would lose
+func (m *Manager) CheckRepositoryRemoval() (*RepositoryRemovalCheck, error) {
+	hasRemotes := m.Executor.HasRemotes()
+	result := &RepositoryRemovalCheck{NoRemotes: !hasRemotes}
+
+	worktrees, err := m.List()
+	if err != nil {
+		return nil, err
+	}
+	checkedOut := make(map[string]bool)
+	for _, wt := range worktrees {
+		if wt.Branch != "" {
+			checkedOut[wt.Branch] = true
+		}
+		check, err := m.checkWorktree(wt.Path, wt.Branch, hasRemotes)
+		if err != nil {
+			return nil, err
+		}
+		if !hasRemotes {
+			// Every commit is lost along with the repository; NoRemotes reports that once
+			check.UnpushedCommits = nil
+		}
+		if check.HasRisks() {
+			result.Worktrees = append(result.Worktrees, *check)
+		}
+	}
+
+	// Branches without a worktree
+	branches, err := m.ListLocalBranches()
+	if err != nil {
+		return nil, err
+	}
+	for _, branch := range branches {
+		if checkedOut[branch] {
+			continue
+		}
+		commits, err := unpushedCommits(m.Executor, "refs/heads/"+branch, branch, hasRemotes)
+		if err != nil {
+			return nil, err
+		}
+		if len(commits) > 0 {
+			result.Branches = append(result.Branches, BranchRemovalCheck{Branch: branch, UnpushedCommits: commits})
+		}
+	}
+
+	result.Stashes, err = m.listStashes()
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// checkWorktree collects changes, untracked files and unpushed commits of a worktree
+func (m *Manager) checkWorktree(worktreePath, branch string, hasRemotes bool) (*RemovalCheck, error) {
+	check := &RemovalCheck{WorktreePath: worktreePath, Branch: branch, NoRemotes: !hasRemotes}
+	executor := git.NewExecutor(worktreePath)
+
+	// A worktree without commits has nothing to compare with; all its files are untracked
+	if _, err := executor.Execute("rev-parse", "--verify", "--quiet", "HEAD"); err == nil {
+		changed, err := executor.Execute("diff", "--name-only", "HEAD")
+		if err != nil {
+			return nil, fmt.Errorf("failed to check changes in %s: %w", worktreePath, err)
+		}
+		check.Changed = splitLines(changed)
+
+		check.UnpushedCommits, err = unpushedCommits(executor, "HEAD", branch, hasRemotes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	untracked, err := executor.Execute("ls-files", "--others", "--exclude-standard")
+	if err != nil {
+		return nil, fmt.Errorf("failed to check untracked files in %s: %w", worktreePath, err)
+	}
+	for _, file := range splitLines(untracked) {
+		if !m.isPostCreateFile(worktreePath, file) {
+			check.Untracked = append(check.Untracked, file)
+		}
+	}
+
+	return check, nil
+}
+
+// isPostCreateFile reports whether an untracked file was created by a post-create action and
+// still matches its source (a symlink to it, an unchanged copy, or a template rendering that
+// has not been edited since), so removing it loses nothing
+func (m *Manager) isPostCreateFile(worktreePath, file string) bool {
+	for _, action := range m.Config.PostCreate {
+		source := postCreatePath(action.Source)
+		if file != source {
+			continue
+		}
+		targetPath := filepath.Join(worktreePath, file)
+		switch action.Type {
+		case "symlink":
+			linkTarget, err := os.Readlink(targetPath)
+			if err != nil {
+				return false
+			}
+			expected, err := m.getExpectedSymlinkTarget(action.Source, action.Managed, worktreePath)
+			return err == nil && linkTarget == expected
+		case "copy":
+			sourcePath, err := m.GetPostCreateSourcePath(action)
+			if err != nil {
+				return false
+			}
+			source, err := os.ReadFile(sourcePath)
+			if err != nil {
+				return false
+			}
+			target, err := os.ReadFile(targetPath)
+			return err == nil && bytes.Equal(source, target)
+		case "template":
+			md, err := m.LoadMetadata(worktreePath)
+			if err != nil || md == nil {
+				return false
+			}
+			hash, err := fileSHA256(targetPath)
+			return err == nil && md.Templates[source] != "" && md.Templates[source] == hash
+		}
+	}
+	return false
+}
+
+// unpushedCommits lists the commits of ref missing from every remote. Without remotes, it lists
+// the commits that are not on any local branch other than branch.
+func unpushedCommits(executor *git.Executor, ref, branch string, hasRemotes bool) ([]string, error) {
+	args := []string{"log", "--format=%h %s", ref, "--not"}
+	if hasRemotes {
+		args = append(args, "--remotes")
+	} else {
+		if branch != "" {
+			// --exclude takes names relative to refs/heads/ when applied to --branches
+			args = append(args, "--exclude="+branch)
+		}
+		args = append(args, "--branches")
+	}
+
+	output, err := executor.Execute(args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check unpushed commits of %s: %w", ref, err)
+	}
+	return splitLines(output), nil
+}
+
+// listStashes returns all stash entries of the repository in 'git stash list' format.
+// 'git stash list' itself refuses to run in the bare repository, so the reflog is read directly.
+func (m *Manager) listStashes() ([]string, error) {
+	if _, err := m.Executor.Execute("rev-parse", "--verify", "--quiet", "refs/stash"); err != nil {
+		return nil, nil
+	}
+	output, err := m.Executor.Execute("log", "--walk-reflogs", "--format=%gd: %gs", "refs/stash", "--")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list stashes: %w", err)
+	}
+	return splitLines(output), nil
+}
+
+// splitLines splits command output into non-empty lines
+func splitLines(output string) []string {
+	var lines []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,221 @@
+package worktree
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/amaya382/baretree/internal/config"
+)
+
+func TestCheckRemoval(t *testing.T) {
+	m := createPruneTestRepo(t)
+	runTestGit(t, m.BareDir, "fetch", "origin")
+	featurePath := filepath.Join(m.RepoRoot, "feature")
+	runTestGit(t, m.BareDir, "worktree", "add", "-b", "feature", featurePath, "main")
+
+	check, err := m.CheckRemoval(featurePath)
+	if err != nil {
+		t.Fatalf("CheckRemoval() error = %v", err)
+	}
+	if check.HasRisks() {
+		t.Fatalf("fresh worktree should have no risks: %+v", check)
+	}
+
+	// Stash a change, then leave a commit, a modified file and an untracked file behind
+	if err := os.WriteFile(filepath.Join(featurePath, "stashed.txt"), []byte("stash"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runTestGit(t, featurePath, "add", "stashed.txt")
+	runTestGit(t, featurePath, "stash")
+	if err := os.WriteFile(filepath.Join(featurePath, "committed.txt"), []byte("a"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runTestGit(t, featurePath, "add", "committed.txt")
+	runTestGit(t, featurePath, "commit", "-m", "local work")
+	if err := os.WriteFile(filepath.Join(featurePath, "committed.txt"), []byte("b"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(featurePath, "notes.txt"), []byte("notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check, err = m.CheckRemoval(featurePath)
+	if err != nil {
+		t.Fatalf("CheckRemoval() error = %v", err)
+	}
+	if len(check.Changed) != 1 || check.Changed[0] != "committed.txt" {
+		t.Errorf("Changed = %v, want [committed.txt]", check.Changed)
+	}
+	if len(check.Untracked) != 1 || check.Untracked[0] != "notes.txt" {
+		t.Errorf("Untracked = %v, want [notes.txt]", check.Untracked)
+	}
+	if len(check.UnpushedCommits) != 1 || !strings.HasSuffix(check.UnpushedCommits[0], " local work") {
+		t.Errorf("UnpushedCommits = %v, want [local work]", check.UnpushedCommits)
+	}
+	if len(check.Stashes) != 1 || !strings.Contains(check.Stashes[0], "WIP on feature:") {
+		t.Errorf("Stashes = %v, want one stash on feature", check.Stashes)
+	}
+
+	var report bytes.Buffer
+	check.WriteReport(&report, "  ")
+	for _, want := range []string{"1 uncommitted change(s)", "1 untracked file(s)", "1 commit(s) not pushed to any remote", "1 stash(es) made on this branch"} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("report does not contain %q:\n%s", want, report.String())
+		}
+	}
+
+	// The stash belongs to feature, not to main
+	check, err = m.CheckRemoval(filepath.Join(m.RepoRoot, "main"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.HasRisks() {
+		t.Errorf("main should have no risks: %+v", check)
+	}
+}
+
+func TestCheckRemovalWithoutRemotes(t *testing.T) {
+	m := createPruneTestRepo(t)
+	runTestGit(t, m.BareDir, "remote", "remove", "origin")
+	featurePath := filepath.Join(m.RepoRoot, "feature")
+	runTestGit(t, m.BareDir, "worktree", "add", "-b", "feature", featurePath, "main")
+
+	// Commits on other branches are not at risk
+	check, err := m.CheckRemoval(featurePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.HasRisks() {
+		t.Fatalf("worktree without own commits should have no risks: %+v", check)
+	}
+
+	runTestGit(t, featurePath, "commit", "--allow-empty", "-m", "only here")
+	check, err = m.CheckRemoval(featurePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !check.NoRemotes || len(check.UnpushedCommits) != 1 {
+		t.Errorf("expected one commit only on feature, got %+v", check)
+	}
+}
+
+func TestCheckRepositoryRemoval(t *testing.T) {
+	m := createPruneTestRepo(t)
+	runTestGit(t, m.BareDir, "fetch", "origin")
+
+	check, err := m.CheckRepositoryRemoval()
+	if err != nil {
+		t.Fatalf("CheckRepositoryRemoval() error = %v", err)
+	}
+	if check.HasRisks() {
+		t.Fatalf("pushed repository should have no risks: %+v", check)
+	}
+
+	// A branch without a worktree with a local commit
+	featurePath := filepath.Join(m.RepoRoot, "feature")
+	runTestGit(t, m.BareDir, "worktree", "add", "-b", "feature", featurePath, "main")
+	runTestGit(t, featurePath, "commit", "--allow-empty", "-m", "unpushed")
+	runTestGit(t, m.BareDir, "worktree", "remove", featurePath)
+	// An untracked file in main
+	if err := os.WriteFile(filepath.Join(m.RepoRoot, "main", "notes.txt"), []byte("notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	check, err = m.CheckRepositoryRemoval()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(check.Branches) != 1 || check.Branches[0].Branch != "feature" {
+		t.Errorf("Branches = %+v, want feature", check.Branches)
+	}
+	if len(check.Worktrees) != 1 || check.Worktrees[0].Branch != "main" || len(check.Worktrees[0].Untracked) != 1 {
+		t.Errorf("Worktrees = %+v, want main with one untracked file", check.Worktrees)
+	}
+
+	// Without remotes, branches holding the only copy of their commits are at risk
+	runTestGit(t, m.BareDir, "remote", "remove", "origin")
+	check, err = m.CheckRepositoryRemoval()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !check.NoRemotes || !check.HasRisks() {
+		t.Errorf("repository without remotes should be at risk: %+v", check)
+	}
+
+	// Missing remotes alone are not a risk
+	runTestGit(t, m.BareDir, "branch", "-D", "feature")
+	if err := os.Remove(filepath.Join(m.RepoRoot, "main", "notes.txt")); err != nil {
+		t.Fatal(err)
+	}
+	check, err = m.CheckRepositoryRemoval()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !check.NoRemotes || check.HasRisks() {
+		t.Errorf("clean repository without remotes should have no risks: %+v", check)
+	}
+}
+
+func TestCheckRemovalIgnoresPostCreateFiles(t *testing.T) {
+	m := createPruneTestRepo(t)
+	runTestGit(t, m.BareDir, "fetch", "origin")
+	sharedDir := filepath.Join(m.RepoRoot, SharedDir)
+	if err := os.MkdirAll(sharedDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sharedDir, ".env"), []byte("SECRET=1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sharedDir, "settings.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(sharedDir, "app.env"), []byte("PORT={{.Port}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	m.Config.PostCreate = []config.PostCreateAction{
+		{Source: ".env", Type: "symlink", Managed: true},
+		{Source: "settings.json", Type: "copy", Managed: true},
+		{Source: "app.env", Type: "template", Managed: true},
+	}
+
+	featurePath, _, err := m.AddWithOptions("feature", AddOptions{NewBranch: true}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check, err := m.CheckRemoval(featurePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if check.HasRisks() {
+		t.Errorf("post-create files should not be at risk: %+v", check)
+	}
+
+	// A regular file in place of the symlink, an edited copy and an edited rendering hold their own content
+	for file, content := range map[string]string{
+		"settings.json": `{"edited": true}`,
+		"app.env":       "PORT=1\n",
+	} {
+		if err := os.WriteFile(filepath.Join(featurePath, file), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Remove(filepath.Join(featurePath, ".env")); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(featurePath, ".env"), []byte("LOCAL=1"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	check, err = m.CheckRemoval(featurePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{".env", "app.env", "settings.json"}
+	if !reflect.DeepEqual(check.Untracked, want) {
+		t.Errorf("Untracked = %v, want %v", check.Untracked, want)
+	}
+}
@@ -0,0 +1,218 @@
+package worktree
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/amaya382/baretree/internal/config"
+)
+
+const (
+	// DefaultTemplatePortBase is the lowest port assigned by the .Port template variable
+	DefaultTemplatePortBase = 3000
+	// TemplatePortRange is the number of distinct ports a branch can be assigned from a base port
+	TemplatePortRange = 1000
+)
+
+// TemplateData holds the variables available to "template" post-create files
+type TemplateData struct {
+	Branch        string // branch checked out in the worktree (e.g., "feature/auth")
+	Slug          string // branch sanitized for use in names (e.g., "feature-auth")
+	Index         int    // position of the worktree among the repository's worktrees sorted by path, starting at 0
+	Port          int    // per-worktree port in [3000, 4000), distinct among the repository's worktrees
+	WorktreePath  string // path of the worktree
+	RepoRoot      string // repository root directory
+	RepoName      string // base name of the repository root
+	DefaultBranch string // configured default branch
+}
+
+// BranchSlug sanitizes a branch name for use in file, container and project names:
+// lowercase, with runs of characters other than [a-z0-9] replaced by a single "-"
+func BranchSlug(branch string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(branch) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		if !dash && b.Len() > 0 {
+			b.WriteByte('-')
+			dash = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+// BranchPort returns a port derived from the branch name, in [base, base+TemplatePortRange).
+// The same branch always gets the same port; different branches usually get different ports.
+func BranchPort(branch string, base int) int {
+	h := fnv.New32a()
+	h.Write([]byte(branch))
+	return base + int(h.Sum32()%TemplatePortRange)
+}
+
+// templateData builds the template variables for a worktree
+func (m *Manager) templateData(worktreePath, branch string) TemplateData {
+	return TemplateData{
+		Branch:        branch,
+		Slug:          BranchSlug(branch),
+		Index:         m.worktreeIndex(worktreePath),
+		Port:          m.templatePort(worktreePath, branch),
+		WorktreePath:  worktreePath,
+		RepoRoot:      m.RepoRoot,
+		RepoName:      filepath.Base(m.RepoRoot),
+		DefaultBranch: m.GetDefaultBranch(),
+	}
+}
+
+// templatePort returns the .Port of a worktree. The first time, the branch port (see BranchPort)
+// is moved up to the next port not used by another worktree of the repository and recorded
+// in the worktree metadata, so the worktree keeps it even if other worktrees come and go.
+// Worktrees without a recorded port are assumed to use their branch port.
+func (m *Manager) templatePort(worktreePath, branch string) int {
+	if md, _ := m.LoadMetadata(worktreePath); md != nil && md.Port != 0 {
+		return md.Port
+	}
+
+	used := make(map[int]bool)
+	if worktrees, err := m.listWorktrees(); err == nil {
+		for _, wt := range worktrees {
+			if wt.IsBare || pathsEqual(wt.Path, worktreePath) {
+				continue
+			}
+			if md, _ := m.LoadMetadata(wt.Path); md != nil && md.Port != 0 {
+				used[md.Port] = true
+			} else if wt.Branch != "" {
+				used[BranchPort(wt.Branch, DefaultTemplatePortBase)] = true
+			}
+		}
+	}
+
+	port := BranchPort(branch, DefaultTemplatePortBase)
+	for i := 0; i < TemplatePortRange && used[port]; i++ {
+		port = DefaultTemplatePortBase + (port-DefaultTemplatePortBase+1)%TemplatePortRange
+	}
+
+	// Best effort: without metadata, the same port is computed again as long as nothing changes
+	_ = m.updateMetadata(worktreePath, func(md *Metadata) { md.Port = port })
+	return port
+}
+
+// worktreeIndex returns the position of a worktree among all worktrees sorted by path.
+// A path that is not (yet) a worktree gets the index it would have.
+func (m *Manager) worktreeIndex(worktreePath string) int {
+	worktrees, err := m.listWorktrees()
+	if err != nil {
+		return 0
+	}
+
+	var paths []string
+	for _, wt := range worktrees {
+		if wt.IsBare || pathsEqual(wt.Path, worktreePath) {
+			continue
+		}
+		paths = append(paths, wt.Path)
+	}
+	sort.Strings(paths)
+	return sort.SearchStrings(paths, worktreePath)
+}
+
+// parseTemplate parses a post-create template file.
+// Besides the TemplateData fields, templates can use:
+//
+//	port BASE   the .Port of the worktree moved to [BASE, BASE+1000)
+//	add A B     sum of two integers (e.g., {{add 8000 .Index}})
+func parseTemplate(sourcePath string, data TemplateData) (*template.Template, error) {
+	content, err := os.ReadFile(sourcePath)
+	if err != nil {
+		return nil, err
+	}
+
+	funcs := template.FuncMap{
+		"port": func(base int) int { return base + data.Port - DefaultTemplatePortBase },
+		"add":  func(a, b int) int { return a + b },
+	}
+	return template.New(filepath.Base(sourcePath)).
+		Funcs(funcs).
+		Option("missingkey=error").
+		Parse(string(content))
+}
+
+// ValidateTemplate checks that a template file parses and renders with sample data
+func ValidateTemplate(sourcePath string) error {
+	data := TemplateData{Branch: "main", Slug: "main", Port: DefaultTemplatePortBase}
+	tmpl, err := parseTemplate(sourcePath, data)
+	if err != nil {
+		return fmt.Errorf("invalid template %s: %w", sourcePath, err)
+	}
+	if err := tmpl.Execute(io.Discard, data); err != nil {
+		return fmt.Errorf("invalid template %s: %w", sourcePath, err)
+	}
+	return nil
+}
+
+// renderWorktreeTemplate renders the template of a post-create action into a worktree and records
+// the hash of the result in the worktree metadata
+func (m *Manager) renderWorktreeTemplate(action config.PostCreateAction, sourcePath, worktreePath, branch string) error {
+	targetPath := filepath.Join(worktreePath, action.Source)
+	if err := renderTemplate(sourcePath, targetPath, m.templateData(worktreePath, branch)); err != nil {
+		return err
+	}
+
+	// Best effort: without the hash, the rendered file is treated as untracked work
+	if hash, err := fileSHA256(targetPath); err == nil {
+		_ = m.updateMetadata(worktreePath, func(md *Metadata) {
+			if md.Templates == nil {
+				md.Templates = make(map[string]string)
+			}
+			md.Templates[postCreatePath(action.Source)] = hash
+		})
+	}
+	return nil
+}
+
+// postCreatePath returns the source of a post-create action as a slash-separated relative path,
+// the form git uses for paths in a worktree
+func postCreatePath(source string) string {
+	return filepath.ToSlash(filepath.Clean(source))
+}
+
+// fileSHA256 returns the hex-encoded SHA-256 of a file's content
+func fileSHA256(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// renderTemplate renders the template at src into dst, keeping the file permissions of src
+func renderTemplate(src, dst string, data TemplateData) error {
+	tmpl, err := parseTemplate(src, data)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return err
+	}
+
+	sourceInfo, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(dst, buf.Bytes(), sourceInfo.Mode().Perm())
+}
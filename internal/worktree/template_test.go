@@ -0,0 +1,140 @@
+package worktree
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestBranchSlug(t *testing.T) {
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{"main", "main"},
+		{"feature/auth", "feature-auth"},
+		{"Feature/JIRA-123_Fix login", "feature-jira-123-fix-login"},
+		{"/leading//trailing/", "leading-trailing"},
+	}
+
+	for _, tt := range tests {
+		if got := BranchSlug(tt.branch); got != tt.want {
+			t.Errorf("BranchSlug(%q) = %q, want %q", tt.branch, got, tt.want)
+		}
+	}
+}
+
+func TestBranchPort(t *testing.T) {
+	port := BranchPort("feature/auth", 8000)
+	if port < 8000 || port >= 8000+TemplatePortRange {
+		t.Errorf("port %d out of range", port)
+	}
+	if BranchPort("feature/auth", 8000) != port {
+		t.Error("port should be deterministic")
+	}
+	if BranchPort("feature/auth", 9000)-9000 != port-8000 {
+		t.Error("port offset should not depend on the base")
+	}
+	if BranchPort("feature/other", 8000) == port {
+		t.Error("expected different branches to get different ports")
+	}
+}
+
+func TestTemplatePortDistinct(t *testing.T) {
+	mgr := createPruneTestRepo(t)
+
+	// Two branches whose branch ports collide
+	first := "feature/a"
+	second := ""
+	for i := 0; second == ""; i++ {
+		if name := fmt.Sprintf("feature/b%d", i); BranchPort(name, DefaultTemplatePortBase) == BranchPort(first, DefaultTemplatePortBase) {
+			second = name
+		}
+	}
+	firstPath := filepath.Join(mgr.RepoRoot, "a")
+	secondPath := filepath.Join(mgr.RepoRoot, "b")
+	runTestGit(t, mgr.BareDir, "worktree", "add", "-b", first, firstPath, "main")
+	runTestGit(t, mgr.BareDir, "worktree", "add", "-b", second, secondPath, "main")
+
+	firstPort := mgr.templatePort(firstPath, first)
+	secondPort := mgr.templatePort(secondPath, second)
+	if secondPort == firstPort {
+		t.Errorf("worktrees with colliding branch ports should get distinct ports, both got %d", firstPort)
+	}
+
+	// The assigned port is kept even when the other worktree is gone
+	runTestGit(t, mgr.BareDir, "worktree", "remove", firstPath)
+	if got := mgr.templatePort(secondPath, second); got != secondPort {
+		t.Errorf("port should be kept, got %d, want %d", got, secondPort)
+	}
+	if md, err := mgr.LoadMetadata(secondPath); err != nil || md == nil || md.Port != secondPort {
+		t.Errorf("port should be recorded in the metadata, got %+v, %v", md, err)
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "env.tmpl")
+	dst := filepath.Join(dir, ".env")
+
+	content := "PORT={{.Port}}\nDB_PORT={{port 5432}}\nCOMPOSE_PROJECT_NAME={{.RepoName}}-{{.Slug}}\nOFFSET={{add 100 .Index}}\n"
+	if err := os.WriteFile(src, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	data := TemplateData{
+		Branch:   "feature/auth",
+		Slug:     "feature-auth",
+		Index:    2,
+		Port:     BranchPort("feature/auth", DefaultTemplatePortBase),
+		RepoName: "app",
+	}
+	if err := renderTemplate(src, dst, data); err != nil {
+		t.Fatalf("renderTemplate failed: %v", err)
+	}
+
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "PORT=" + strconv.Itoa(data.Port) + "\nDB_PORT=" + strconv.Itoa(BranchPort("feature/auth", 5432)) +
+		"\nCOMPOSE_PROJECT_NAME=app-feature-auth\nOFFSET=102\n"
+	if string(got) != want {
+		t.Errorf("rendered:\n%s\nwant:\n%s", got, want)
+	}
+
+	info, err := os.Stat(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("expected permissions 0600, got %o", info.Mode().Perm())
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "valid")
+	if err := os.WriteFile(valid, []byte("PORT={{.Port}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidateTemplate(valid); err != nil {
+		t.Errorf("expected valid template, got %v", err)
+	}
+
+	for name, content := range map[string]string{
+		"syntax":        "PORT={{.Port\n",
+		"unknown field": "PORT={{.Prot}}\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := ValidateTemplate(path); err == nil {
+			t.Errorf("expected %s error", name)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+// Package baretree provides a public Go API for baretree repositories.
+//
+// It exposes the same operations the bt command uses (opening a repository,
+// listing/adding/removing worktrees, and reading/writing the [baretree]
+// git-config section) as typed Go calls, so tools can integrate with baretree
+// without shelling out to bt and parsing its output.
+//
+// The API follows semantic versioning together with the module. All types are
+// defined in this package and converted from the internal ones at the boundary,
+// so changes to bt's internals do not change the API. Errors returned by this
+// package can be matched with errors.As against the exported error types.
+package baretree
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+
+	"github.com/amaya382/baretree/internal/config"
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/amaya382/baretree/internal/repository"
+	"github.com/amaya382/baretree/internal/url"
+	"github.com/amaya382/baretree/internal/worktree"
+)
+
+// BareDir is the fixed directory name of the bare repository inside a repository root
+const BareDir = config.BareDir
+
+// Repository is an opened baretree repository
+type Repository struct {
+	// Root is the repository root (the directory containing .git)
+	Root string
+	// BareDir is the path to the bare repository
+	BareDir string
+
+	mgr *worktree.Manager
+}
+
+// Open opens the baretree repository containing path.
+// path may be the repository root, a worktree, or any directory inside them.
+func Open(path string) (*Repository, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absolute path: %w", err)
+	}
+
+	repoRoot, err := repository.FindRoot(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("not in a baretree repository: %w", err)
+	}
+
+	bareDir, err := repository.GetBareRepoPath(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.LoadConfig(repoRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Repository{
+		Root:    repoRoot,
+		BareDir: bareDir,
+		mgr:     worktree.NewManager(repoRoot, bareDir, cfg),
+	}, nil
+}
+
+// Config returns the repository configuration loaded when the repository was opened
+// (or last saved/reloaded). Modifications are not persisted until SaveConfig is called.
+func (r *Repository) Config() *Config {
+	return fromInternalConfig(r.mgr.Config)
+}
+
+// ReloadConfig re-reads the configuration from git-config
+func (r *Repository) ReloadConfig() error {
+	cfg, err := config.LoadConfig(r.Root)
+	if err != nil {
+		return err
+	}
+	r.mgr.Config = cfg
+	return nil
+}
+
+// SaveConfig writes cfg to git-config and makes it the active configuration
+func (r *Repository) SaveConfig(cfg *Config) error {
+	internal := cfg.toInternal()
+	if err := config.SaveConfig(r.Root, internal); err != nil {
+		return err
+	}
+	r.mgr.Config = internal
+	return nil
+}
+
+// DefaultBranch returns the configured default branch name
+func (r *Repository) DefaultBranch() string {
+	return r.mgr.GetDefaultBranch()
+}
+
+// Worktrees returns all worktrees (excluding the bare repository itself)
+func (r *Repository) Worktrees() ([]Worktree, error) {
+	worktrees, err := r.mgr.List()
+	if err != nil {
+		return nil, err
+	}
+	return fromInternalWorktrees(worktrees), nil
+}
+
+// ResolveWorktree resolves a worktree name (branch name, path relative to the root,
+// directory name, or "@" for the default worktree) to its absolute path.
+// Returns *AmbiguousMatchError when several worktrees match.
+func (r *Repository) ResolveWorktree(name string) (string, error) {
+	path, err := r.mgr.Resolve(name)
+	return path, convertError(err)
+}
+
+// AddWorktree creates a worktree for branchName and applies post-create configuration.
+// Post-create output is written to output (pass nil to discard).
+// Returns the worktree path and the post-create results.
+// Call RefreshEditorWorkspace afterwards to update the editor workspace.
+func (r *Repository) AddWorktree(branchName string, opts AddOptions, output io.Writer) (string, *PostCreateResult, error) {
+	path, result, err := r.mgr.AddWithOptions(branchName, opts.toInternal(), output)
+	return path, fromInternalPostCreateResult(result), convertError(err)
+}
+
+// RemoveWorktree removes the worktree identified by name (see ResolveWorktree).
+// With force, the worktree is removed even if it has uncommitted changes.
+// Call RefreshEditorWorkspace afterwards to update the editor workspace.
+func (r *Repository) RemoveWorktree(name string, force bool) error {
+	worktreePath, err := r.mgr.Resolve(name)
+	if err != nil {
+		return convertError(err)
+	}
+	return r.mgr.Remove(worktreePath, force)
+}
+
+// RefreshEditorWorkspace updates the editor workspace file if the repository keeps one
+// current ('bt editor workspace --auto'). AddWorktree and RemoveWorktree don't update it,
+// so callers can refresh once after a batch of changes.
+func (r *Repository) RefreshEditorWorkspace() error {
+	return r.mgr.RefreshEditorWorkspace()
+}
+
+// DeleteBranch deletes a local branch. With force, unmerged branches are deleted too.
+func (r *Repository) DeleteBranch(branchName string, force bool) error {
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	if _, err := r.mgr.Executor.Execute("branch", flag, branchName); err != nil {
+		return fmt.Errorf("failed to delete branch: %w", err)
+	}
+	return nil
+}
+
+// LoadGlobalConfig loads the global configuration (roots and user) from git-config
+// and the BARETREE_ROOT environment variable
+func LoadGlobalConfig() (*GlobalConfig, error) {
+	cfg, err := global.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	return fromInternalGlobalConfig(cfg), nil
+}
+
+// ScanRepositories finds all baretree repositories under the given root directories
+func ScanRepositories(roots []string) ([]RepoInfo, error) {
+	repos, err := global.ScanRepositories(roots)
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]RepoInfo, len(repos))
+	for i, repo := range repos {
+		infos[i] = RepoInfo{Path: repo.Path, RelativePath: repo.RelativePath, Name: repo.Name}
+	}
+	return infos, nil
+}
+
+// ParseRepoPath parses a repository URL (SSH, HTTPS, ssh:// with port, file://), local path
+// or short path (host/user/repo, user/repo, repo).
+// defaultHost and defaultUser fill in the missing components of short forms.
+func ParseRepoPath(input, defaultHost, defaultUser string) (*RepoPath, error) {
+	repoPath, err := url.Parse(input, defaultHost, defaultUser)
+	if err != nil {
+		return nil, err
+	}
+	return fromInternalRepoPath(repoPath), nil
+}
@@ -0,0 +1,169 @@
+package baretree
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// runGit runs a git command in dir and fails the test on error
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+}
+
+// createTestRepo creates a baretree repository with a main worktree
+func createTestRepo(t *testing.T) string {
+	t.Helper()
+	tempDir := t.TempDir()
+
+	srcDir := filepath.Join(tempDir, "src")
+	if err := os.MkdirAll(srcDir, 0755); err != nil {
+		t.Fatalf("failed to create src dir: %v", err)
+	}
+	runGit(t, srcDir, "init", "-b", "main")
+	runGit(t, srcDir, "-c", "user.name=Test", "-c", "user.email=test@example.com", "commit", "--allow-empty", "-m", "Initial commit")
+
+	repoRoot := filepath.Join(tempDir, "repo")
+	runGit(t, tempDir, "clone", "--bare", srcDir, filepath.Join(repoRoot, BareDir))
+	runGit(t, filepath.Join(repoRoot, BareDir), "config", "baretree.defaultbranch", "main")
+	runGit(t, filepath.Join(repoRoot, BareDir), "worktree", "add", filepath.Join(repoRoot, "main"), "main")
+
+	return repoRoot
+}
+
+func TestOpen(t *testing.T) {
+	repoRoot := createTestRepo(t)
+
+	t.Run("from repository root", func(t *testing.T) {
+		repo, err := Open(repoRoot)
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if repo.Root != repoRoot {
+			t.Errorf("expected root %q, got %q", repoRoot, repo.Root)
+		}
+		if repo.DefaultBranch() != "main" {
+			t.Errorf("expected default branch 'main', got %q", repo.DefaultBranch())
+		}
+	})
+
+	t.Run("from inside a worktree", func(t *testing.T) {
+		repo, err := Open(filepath.Join(repoRoot, "main"))
+		if err != nil {
+			t.Fatalf("Open failed: %v", err)
+		}
+		if repo.Root != repoRoot {
+			t.Errorf("expected root %q, got %q", repoRoot, repo.Root)
+		}
+	})
+
+	t.Run("outside a repository", func(t *testing.T) {
+		if _, err := Open(t.TempDir()); err == nil {
+			t.Error("expected error outside a baretree repository")
+		}
+	})
+}
+
+func TestWorktreeLifecycle(t *testing.T) {
+	repoRoot := createTestRepo(t)
+	repo, err := Open(repoRoot)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	path, _, err := repo.AddWorktree("feature/api", AddOptions{NewBranch: true}, nil)
+	if err != nil {
+		t.Fatalf("AddWorktree failed: %v", err)
+	}
+	if path != filepath.Join(repoRoot, "feature", "api") {
+		t.Errorf("unexpected worktree path: %s", path)
+	}
+
+	worktrees, err := repo.Worktrees()
+	if err != nil {
+		t.Fatalf("Worktrees failed: %v", err)
+	}
+	if len(worktrees) != 2 {
+		t.Fatalf("expected 2 worktrees, got %d", len(worktrees))
+	}
+
+	t.Run("adding an existing worktree returns ErrWorktreeAlreadyExists", func(t *testing.T) {
+		_, _, err := repo.AddWorktree("feature/api", AddOptions{}, nil)
+		var existsErr *ErrWorktreeAlreadyExists
+		if !errors.As(err, &existsErr) {
+			t.Fatalf("expected ErrWorktreeAlreadyExists, got %v", err)
+		}
+		if existsErr.WorktreePath != path {
+			t.Errorf("expected path %q, got %q", path, existsErr.WorktreePath)
+		}
+	})
+
+	t.Run("conflicting ref returns ErrRefConflict", func(t *testing.T) {
+		_, _, err := repo.AddWorktree("feature/api/child", AddOptions{NewBranch: true}, nil)
+		var refErr *ErrRefConflict
+		if !errors.As(err, &refErr) {
+			t.Fatalf("expected ErrRefConflict, got %v", err)
+		}
+		if refErr.ConflictingRef != "feature/api" {
+			t.Errorf("expected conflicting ref 'feature/api', got %q", refErr.ConflictingRef)
+		}
+	})
+
+	t.Run("remove worktree and branch", func(t *testing.T) {
+		if err := repo.RemoveWorktree("feature/api", false); err != nil {
+			t.Fatalf("RemoveWorktree failed: %v", err)
+		}
+		if err := repo.DeleteBranch("feature/api", true); err != nil {
+			t.Fatalf("DeleteBranch failed: %v", err)
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed", path)
+		}
+	})
+}
+
+func TestConfigRoundTrip(t *testing.T) {
+	repoRoot := createTestRepo(t)
+	repo, err := Open(repoRoot)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+
+	cfg := repo.Config()
+	cfg.PostCreate = append(cfg.PostCreate, PostCreateAction{Source: "npm install", Type: "command"})
+	if err := repo.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	reopened, err := Open(repoRoot)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	if len(reopened.Config().PostCreate) != 1 || reopened.Config().PostCreate[0].Source != "npm install" {
+		t.Errorf("unexpected post-create config after reload: %+v", reopened.Config().PostCreate)
+	}
+}
+
+func TestParseRepoPath(t *testing.T) {
+	repoPath, err := ParseRepoPath("ssh://git@gitlab.example.com:2222/org/team/repo.git", "github.com", "")
+	if err != nil {
+		t.Fatalf("ParseRepoPath failed: %v", err)
+	}
+	want := RepoPath{Scheme: "ssh", Host: "gitlab.example.com", Port: "2222", User: "org", Repo: "team/repo"}
+	if *repoPath != want {
+		t.Errorf("ParseRepoPath() = %+v, want %+v", *repoPath, want)
+	}
+	if got := repoPath.String(); got != "gitlab.example.com/org/team/repo" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := repoPath.Name(); got != "repo" {
+		t.Errorf("Name() = %q", got)
+	}
+}
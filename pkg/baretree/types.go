@@ -0,0 +1,360 @@
+package baretree
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/amaya382/baretree/internal/config"
+	"github.com/amaya382/baretree/internal/git"
+	"github.com/amaya382/baretree/internal/global"
+	"github.com/amaya382/baretree/internal/url"
+	"github.com/amaya382/baretree/internal/worktree"
+)
+
+// Config represents the per-repository baretree configuration ([baretree] section in .git/config)
+type Config struct {
+	Repository   RepositoryConfig
+	PostCreate   []PostCreateAction
+	SyncToRoot   []SyncToRootAction
+	PreRemove    []HookAction
+	PostRemove   []HookAction
+	PostRename   []HookAction
+	PostCheckout []HookAction
+}
+
+// RepositoryConfig holds repository-level settings
+type RepositoryConfig struct {
+	// DefaultBranch is the branch of the default worktree
+	DefaultBranch string
+	// WorktreeTemplate maps branch names to worktree directories (Go text/template); empty uses
+	// the branch name as is
+	WorktreeTemplate string
+	// EditorWorkspace is the editor workspace format ("vscode" or "jetbrains") kept current as
+	// worktrees change; empty disables it
+	EditorWorkspace string
+}
+
+// PostCreateAction represents an action to perform after worktree creation.
+// Type is "symlink", "copy", "template" or "command"; the command options (Name, Parallel,
+// DependsOn, Timeout, Retries, Required) apply to commands only.
+type PostCreateAction struct {
+	Source    string
+	Type      string
+	Managed   bool // source is in the .shared/ directory (symlink, copy and template only)
+	Name      string
+	Parallel  bool
+	DependsOn []string
+	Timeout   string // Go duration, e.g. "5m"; empty for no limit
+	Retries   int
+	Required  bool // roll back worktree creation if the command fails
+}
+
+// SyncToRootAction represents a file or directory synced from the default worktree to the root
+type SyncToRootAction struct {
+	Source string // relative path in the default branch worktree
+	Target string // relative path in the repository root (empty means same as Source)
+}
+
+// HookAction represents a shell command run on a worktree lifecycle event
+type HookAction struct {
+	Command string
+}
+
+// Worktree describes a git worktree
+type Worktree struct {
+	Path   string
+	Head   string
+	Branch string
+	IsMain bool
+	IsBare bool
+}
+
+// AddOptions contains options for adding a worktree
+type AddOptions struct {
+	NewBranch  bool   // create a new branch
+	BaseBranch string // base branch for a new branch
+	TrackRef   string // remote ref to track (e.g. "origin/feature/x")
+}
+
+// PostCreateResult contains the results of applying post-create configuration
+type PostCreateResult struct {
+	FileActions    []FileActionResult
+	CommandResults []CommandResult
+}
+
+// CommandResult represents the result of executing a post-create command
+type CommandResult struct {
+	Command  string
+	Name     string // empty if the command is unnamed
+	Success  bool
+	Skipped  bool // not run because a dependency or a required command failed
+	Required bool
+	TimedOut bool
+	Attempts int // number of runs, including retries
+	Output   string
+	Error    string
+	Duration time.Duration // total run time across attempts
+}
+
+// FileActionResult represents the result of applying a post-create file action
+type FileActionResult struct {
+	Source  string
+	Type    string
+	Applied bool // false if skipped (already exists or source missing)
+}
+
+// RepoInfo holds information about a repository discovered under a root
+type RepoInfo struct {
+	Path         string // absolute path of the repository
+	RelativePath string // path relative to the root, e.g. "github.com/user/repo"
+	Name         string // last component of the path
+}
+
+// RepoPath represents a parsed repository path (host/user/repo)
+type RepoPath struct {
+	Scheme string // e.g. "https", "ssh", "file" (empty for scp-style SSH URLs and short paths)
+	Host   string // empty for local repositories
+	Port   string // empty for the default port
+	User   string // first path component: user, organization or top-level group
+	Repo   string // rest of the path, including subgroups
+}
+
+// String returns the path under a root (host/user/repo)
+func (r *RepoPath) String() string {
+	return r.toInternal().String()
+}
+
+// Name returns the repository name without its namespace
+func (r *RepoPath) Name() string {
+	return r.toInternal().Name()
+}
+
+// Namespace returns the path of the groups containing the repository
+func (r *RepoPath) Namespace() string {
+	return r.toInternal().Namespace()
+}
+
+// CloneURL builds the URL to clone the repository with protocol "ssh" or "https"
+func (r *RepoPath) CloneURL(protocol string) (string, error) {
+	return r.toInternal().CloneURL(protocol)
+}
+
+// GlobalConfig holds the global baretree configuration
+type GlobalConfig struct {
+	Roots      []string // root directories; the last one is primary
+	User       string   // default user for short repository names
+	Host       string   // default host for short repository names
+	Protocol   string   // default clone protocol, "ssh" or "https"
+	Hosts      map[string]HostConfig
+	Frecency   bool // rank matches by how often and how recently they were visited
+	KeepSubdir bool // bt cd keeps the subdirectory when switching worktrees
+}
+
+// HostConfig holds settings that override the defaults for a single host
+type HostConfig struct {
+	Protocol        string
+	User            string
+	PullRequestRef  string // ref pattern fetched by bt add --pr
+	MergeRequestRef string // ref pattern fetched by bt add --mr
+}
+
+// ErrWorktreeAlreadyExists is returned when the branch already has a worktree
+type ErrWorktreeAlreadyExists struct {
+	BranchName   string
+	WorktreePath string
+}
+
+func (e *ErrWorktreeAlreadyExists) Error() string {
+	return fmt.Sprintf("branch '%s' is already checked out at '%s'", e.BranchName, e.WorktreePath)
+}
+
+// ErrBranchNotFound is returned when the specified branch doesn't exist
+type ErrBranchNotFound struct {
+	BranchName string
+}
+
+func (e *ErrBranchNotFound) Error() string {
+	return fmt.Sprintf("branch '%s' not found locally or on any remote", e.BranchName)
+}
+
+// ErrRefConflict is returned when a branch conflicts with an existing ref
+type ErrRefConflict struct {
+	BranchName     string
+	ConflictingRef string
+}
+
+func (e *ErrRefConflict) Error() string {
+	return fmt.Sprintf("cannot create branch '%s': conflicts with existing ref '%s'\n"+
+		"Git does not allow refs like '%s' and '%s/...' to coexist because refs are stored as files/directories",
+		e.BranchName, e.ConflictingRef, e.ConflictingRef, e.ConflictingRef)
+}
+
+// ErrRequiredCommandFailed is returned when a required post-create command fails and the
+// worktree is rolled back
+type ErrRequiredCommandFailed struct {
+	BranchName string
+	Command    string
+	Err        string
+}
+
+func (e *ErrRequiredCommandFailed) Error() string {
+	return fmt.Sprintf("required post-create command failed for '%s': %s: %s", e.BranchName, e.Command, e.Err)
+}
+
+// AmbiguousMatchError is returned when multiple worktrees match a name
+type AmbiguousMatchError struct {
+	Name    string
+	Matches []Worktree
+}
+
+func (e *AmbiguousMatchError) Error() string {
+	return fmt.Sprintf("ambiguous worktree name '%s': %d matches found", e.Name, len(e.Matches))
+}
+
+// convertError replaces the structured errors of internal packages with the exported ones
+func convertError(err error) error {
+	var existsErr *worktree.ErrWorktreeAlreadyExists
+	var notFoundErr *worktree.ErrBranchNotFound
+	var refErr *worktree.ErrRefConflict
+	var requiredErr *worktree.ErrRequiredCommandFailed
+	var ambiguousErr *worktree.AmbiguousMatchError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &existsErr):
+		return &ErrWorktreeAlreadyExists{BranchName: existsErr.BranchName, WorktreePath: existsErr.WorktreePath}
+	case errors.As(err, &notFoundErr):
+		return &ErrBranchNotFound{BranchName: notFoundErr.BranchName}
+	case errors.As(err, &refErr):
+		return &ErrRefConflict{BranchName: refErr.BranchName, ConflictingRef: refErr.ConflictingRef}
+	case errors.As(err, &requiredErr):
+		return &ErrRequiredCommandFailed{BranchName: requiredErr.BranchName, Command: requiredErr.Command, Err: requiredErr.Err}
+	case errors.As(err, &ambiguousErr):
+		return &AmbiguousMatchError{Name: ambiguousErr.Name, Matches: fromInternalWorktrees(ambiguousErr.Matches)}
+	default:
+		return err
+	}
+}
+
+func fromInternalConfig(cfg *config.Config) *Config {
+	if cfg == nil {
+		return &Config{}
+	}
+	c := &Config{
+		Repository: RepositoryConfig{
+			DefaultBranch:    cfg.Repository.DefaultBranch,
+			WorktreeTemplate: cfg.Repository.WorktreeTemplate,
+			EditorWorkspace:  cfg.Repository.EditorWorkspace,
+		},
+		PreRemove:    fromInternalHooks(cfg.PreRemove),
+		PostRemove:   fromInternalHooks(cfg.PostRemove),
+		PostRename:   fromInternalHooks(cfg.PostRename),
+		PostCheckout: fromInternalHooks(cfg.PostCheckout),
+	}
+	for _, a := range cfg.PostCreate {
+		c.PostCreate = append(c.PostCreate, PostCreateAction{
+			Source: a.Source, Type: a.Type, Managed: a.Managed, Name: a.Name, Parallel: a.Parallel,
+			DependsOn: append([]string(nil), a.DependsOn...), Timeout: a.Timeout, Retries: a.Retries, Required: a.Required,
+		})
+	}
+	for _, a := range cfg.SyncToRoot {
+		c.SyncToRoot = append(c.SyncToRoot, SyncToRootAction{Source: a.Source, Target: a.Target})
+	}
+	return c
+}
+
+func (c *Config) toInternal() *config.Config {
+	cfg := &config.Config{
+		Repository: config.Repository{
+			DefaultBranch:    c.Repository.DefaultBranch,
+			WorktreeTemplate: c.Repository.WorktreeTemplate,
+			EditorWorkspace:  c.Repository.EditorWorkspace,
+		},
+		PreRemove:    toInternalHooks(c.PreRemove),
+		PostRemove:   toInternalHooks(c.PostRemove),
+		PostRename:   toInternalHooks(c.PostRename),
+		PostCheckout: toInternalHooks(c.PostCheckout),
+	}
+	for _, a := range c.PostCreate {
+		cfg.PostCreate = append(cfg.PostCreate, config.PostCreateAction{
+			Source: a.Source, Type: a.Type, Managed: a.Managed, Name: a.Name, Parallel: a.Parallel,
+			DependsOn: append([]string(nil), a.DependsOn...), Timeout: a.Timeout, Retries: a.Retries, Required: a.Required,
+		})
+	}
+	for _, a := range c.SyncToRoot {
+		cfg.SyncToRoot = append(cfg.SyncToRoot, config.SyncToRootAction{Source: a.Source, Target: a.Target})
+	}
+	return cfg
+}
+
+func fromInternalHooks(hooks []config.HookAction) []HookAction {
+	var result []HookAction
+	for _, h := range hooks {
+		result = append(result, HookAction{Command: h.Command})
+	}
+	return result
+}
+
+func toInternalHooks(hooks []HookAction) []config.HookAction {
+	var result []config.HookAction
+	for _, h := range hooks {
+		result = append(result, config.HookAction{Command: h.Command})
+	}
+	return result
+}
+
+func fromInternalWorktrees(worktrees []git.Worktree) []Worktree {
+	result := make([]Worktree, len(worktrees))
+	for i, wt := range worktrees {
+		result[i] = Worktree{Path: wt.Path, Head: wt.Head, Branch: wt.Branch, IsMain: wt.IsMain, IsBare: wt.IsBare}
+	}
+	return result
+}
+
+func (o AddOptions) toInternal() worktree.AddOptions {
+	return worktree.AddOptions{NewBranch: o.NewBranch, BaseBranch: o.BaseBranch, TrackRef: o.TrackRef}
+}
+
+func fromInternalPostCreateResult(result *worktree.PostCreateResult) *PostCreateResult {
+	if result == nil {
+		return nil
+	}
+	r := &PostCreateResult{}
+	for _, f := range result.FileActions {
+		r.FileActions = append(r.FileActions, FileActionResult{Source: f.Source, Type: f.Type, Applied: f.Applied})
+	}
+	for _, c := range result.CommandResults {
+		r.CommandResults = append(r.CommandResults, CommandResult{
+			Command: c.Command, Name: c.Name, Success: c.Success, Skipped: c.Skipped, Required: c.Required,
+			TimedOut: c.TimedOut, Attempts: c.Attempts, Output: c.Output, Error: c.Error, Duration: c.Duration,
+		})
+	}
+	return r
+}
+
+func fromInternalRepoPath(p *url.RepoPath) *RepoPath {
+	return &RepoPath{Scheme: p.Scheme, Host: p.Host, Port: p.Port, User: p.User, Repo: p.Repo}
+}
+
+func (r *RepoPath) toInternal() *url.RepoPath {
+	return &url.RepoPath{Scheme: r.Scheme, Host: r.Host, Port: r.Port, User: r.User, Repo: r.Repo}
+}
+
+func fromInternalGlobalConfig(cfg *global.Config) *GlobalConfig {
+	c := &GlobalConfig{
+		Roots:      append([]string(nil), cfg.Roots...),
+		User:       cfg.User,
+		Host:       cfg.Host,
+		Protocol:   cfg.Protocol,
+		Frecency:   cfg.Frecency,
+		KeepSubdir: cfg.KeepSubdir,
+	}
+	if cfg.Hosts != nil {
+		c.Hosts = make(map[string]HostConfig, len(cfg.Hosts))
+		for name, h := range cfg.Hosts {
+			c.Hosts[name] = HostConfig{Protocol: h.Protocol, User: h.User, PullRequestRef: h.PullRequestRef, MergeRequestRef: h.MergeRequestRef}
+		}
+	}
+	return c
+}